@@ -0,0 +1,29 @@
+// Package api holds the service's published contracts: the OpenAPI document of the HTTP API
+// and the protobuf definitions of the gRPC API.
+package api
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+//go:embed openapi.yaml
+var openAPISource []byte
+
+// LoadOpenAPI
+//
+// Returns:
+//   - The OpenAPI 3 document describing the HTTP API, with every reference resolved.
+//   - err: An error if the document cannot be parsed or is not valid OpenAPI 3.
+func LoadOpenAPI() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPISource)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// receiptSchemaName is the component schema every submitted or corrected receipt must match.
+const receiptSchemaName = "Receipt"
+
+// FieldError describes one value of a request that does not match the OpenAPI document.
+type FieldError struct {
+	Field   string `json:"field"`   // Path to the value, e.g. "items[0].price", or the name of a parameter; empty for the body as a whole
+	Message string `json:"message"` // Why the value was rejected
+}
+
+// ValidationError lists every value of a request that does not match the OpenAPI document.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		if field.Field == "" {
+			messages[i] = field.Message
+			continue
+		}
+		messages[i] = field.Field + ": " + field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validator checks requests and receipts against the OpenAPI document, so that the published contract is
+// the only place the rules for a valid receipt are written down.
+type Validator struct {
+	doc     *openapi3.T
+	receipt *openapi3.Schema
+}
+
+// NewValidator
+//
+// Parameters:
+//   - doc: The OpenAPI document, as returned by LoadOpenAPI.
+//
+// Returns:
+//   - A new instance of Validator for the document.
+//   - err: An error if the document does not define the Receipt schema.
+func NewValidator(doc *openapi3.T) (*Validator, error) {
+	ref, ok := doc.Components.Schemas[receiptSchemaName]
+	if !ok || ref.Value == nil {
+		return nil, fmt.Errorf("openapi document does not define the %s schema", receiptSchemaName)
+	}
+	return &Validator{doc: doc, receipt: ref.Value}, nil
+}
+
+// ValidateRequest
+//
+// The body is read and replaced with an unread copy, so the handler can still decode it. A body whose
+// Content-Type the operation does not list, or that has none, is validated as JSON.
+//
+// Parameters:
+//   - req: The incoming HTTP request.
+//   - path: The route template the request matched, in OpenAPI form, e.g. "/receipt/{id}/points".
+//   - pathParams: The values of the route's path parameters, by name.
+//
+// Returns:
+//   - err: A *ValidationError listing every parameter and body value that does not match the document;
+//     nil if the request is valid or the route is not described by the document.
+func (v *Validator) ValidateRequest(req *http.Request, path string, pathParams map[string]string) error {
+	original := req
+	pathItem := v.doc.Paths.Value(path)
+	if pathItem == nil {
+		return nil
+	}
+	operation := pathItem.GetOperation(req.Method)
+	if operation == nil {
+		return nil
+	}
+
+	if body := operation.RequestBody; body != nil && body.Value != nil && req.Body != nil && req.Body != http.NoBody {
+		if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); body.Value.GetMediaType(mediaType) == nil {
+			// Handlers decode bodies of any other type as JSON, so they are validated as JSON too
+			req = req.Clone(req.Context())
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route: &routers.Route{
+			Spec:      v.doc,
+			Path:      path,
+			PathItem:  pathItem,
+			Method:    req.Method,
+			Operation: operation,
+		},
+		Options: &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}
+	err := openapi3filter.ValidateRequest(req.Context(), input)
+	original.Body = req.Body
+	if err != nil {
+		return &ValidationError{Fields: fieldErrors(err)}
+	}
+	return nil
+}
+
+// ValidateReceipt
+//
+// Parameters:
+//   - receipt: The receipt to check, either as a JSON document or as any value that encodes to one.
+//
+// Returns:
+//   - err: A *ValidationError listing every field that does not match the Receipt schema; nil if the receipt is valid.
+func (v *Validator) ValidateReceipt(receipt any) error {
+	data, ok := receipt.([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(receipt); err != nil {
+			return err
+		}
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return &ValidationError{Fields: []FieldError{{Message: err.Error()}}}
+	}
+	if err := v.receipt.VisitJSON(value, openapi3.MultiErrors()); err != nil {
+		return &ValidationError{Fields: fieldErrors(err)}
+	}
+	return nil
+}
+
+// RegisterBodyFormat
+//
+// Lets every Validator read request bodies of another media type, by converting them into the document
+// their JSON equivalent would decode into before checking them against the schema.
+//
+// Parameters:
+//   - mediaType: The media type, e.g. "application/xml". Operations list it in their request body to accept it.
+//   - decode: Converts a body of the media type into its JSON-shaped document.
+func RegisterBodyFormat(mediaType string, decode func(data []byte) (any, error)) {
+	openapi3filter.RegisterBodyDecoder(mediaType, func(body io.Reader, _ http.Header, _ *openapi3.SchemaRef, _ openapi3filter.EncodingFn) (any, error) {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		return decode(data)
+	})
+}
+
+// defaultValidator is built from the embedded document the first time ValidateReceipt is called.
+var defaultValidator = sync.OnceValues(func() (*Validator, error) {
+	doc, err := LoadOpenAPI()
+	if err != nil {
+		return nil, err
+	}
+	return NewValidator(doc)
+})
+
+// ValidateReceipt
+//
+// Used by the transports that do not see the raw HTTP request, such as gRPC, GraphQL and batch uploads, and by
+// the receipt handlers themselves, so that they accept exactly the receipts POST /receipt/process does whether
+// or not the OpenAPI validation middleware is mounted.
+//
+// Parameters:
+//   - receipt: The receipt to check, either as a JSON document or as any value that encodes to one.
+//
+// Returns:
+//   - err: A *ValidationError listing every field that does not match the Receipt schema of the embedded document;
+//     nil if the receipt is valid.
+func ValidateReceipt(receipt any) error {
+	validator, err := defaultValidator()
+	if err != nil {
+		return err
+	}
+	return validator.ValidateReceipt(receipt)
+}
+
+// fieldErrors flattens the errors reported by kin-openapi into one FieldError per rejected value.
+// Errors are matched by their own type rather than with errors.As, which would skip past the
+// RequestError naming the parameter a nested schema error belongs to.
+func fieldErrors(err error) []FieldError {
+	switch err := err.(type) {
+	case openapi3.MultiError:
+		var fields []FieldError
+		for _, e := range err {
+			fields = append(fields, fieldErrors(e)...)
+		}
+		return fields
+	case *openapi3filter.RequestError:
+		var parameter string
+		if err.Parameter != nil {
+			parameter = err.Parameter.Name
+		}
+		if err.Err == nil {
+			return []FieldError{{Field: parameter, Message: err.Reason}}
+		}
+		fields := fieldErrors(err.Err)
+		for i := range fields {
+			fields[i].Field = joinField(parameter, fields[i].Field)
+		}
+		return fields
+	case *openapi3.SchemaError:
+		return []FieldError{{Field: fieldPath(err.JSONPointer()), Message: err.Reason}}
+	case *openapi3filter.ParseError:
+		return []FieldError{{Message: err.Error()}}
+	default:
+		return []FieldError{{Message: err.Error()}}
+	}
+}
+
+// fieldPath renders a JSON pointer such as ["items", "0", "price"] as "items[0].price"
+func fieldPath(pointer []string) string {
+	var path strings.Builder
+	for _, segment := range pointer {
+		if _, err := strconv.Atoi(segment); err == nil {
+			path.WriteString("[" + segment + "]")
+			continue
+		}
+		if path.Len() > 0 {
+			path.WriteString(".")
+		}
+		path.WriteString(segment)
+	}
+	return path.String()
+}
+
+// joinField prefixes a path within a parameter's value with the parameter's name
+func joinField(parameter, path string) string {
+	if parameter == "" || path == "" {
+		return parameter + path
+	}
+	if strings.HasPrefix(path, "[") {
+		return parameter + path
+	}
+	return parameter + "." + path
+}
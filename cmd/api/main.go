@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
 	"go-receipt-processor/cmd/container"
+	"log"
+	"net"
 
 	"github.com/gin-gonic/gin"
 )
@@ -9,15 +12,140 @@ import (
 // main is the entry point of the application.
 func main() {
 	// Initialize the dependency container, which manages all application services and handlers.
-	c := container.NewContainer()
+	cfg, err := container.LoadConfig()
+	if err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
+
+	c, err := container.NewContainer(cfg)
+	if err != nil {
+		log.Fatalf("failed to initialize container: %v", err)
+	}
+	defer c.Close()
+
+	// Webhooks are delivered, and in async mode queued receipts processed, in the background until the server stops
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go c.Webhooks.Run(ctx)
+	if pool := c.NewReceiptWorkerPool(); pool != nil {
+		go pool.Run(ctx)
+	}
+
+	// Serve the gRPC API alongside the HTTP API
+	listener, err := net.Listen("tcp", cfg.GRPCAddr)
+	if err != nil {
+		log.Fatalf("failed to listen for gRPC on %s: %v", cfg.GRPCAddr, err)
+	}
+	grpcServer := c.NewGRPCServer()
+	defer grpcServer.GracefulStop()
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Printf("gRPC server stopped: %v", err)
+		}
+	}()
 
 	// Create a new Gin router instance for handling HTTP requests.
 	g := gin.Default()
 
-	// Register the routes
-	g.POST("/receipt/process", c.NewReceiptProcessHandler().ProcessReceipt)
-	g.GET("/receipt/:id/points", c.NewGetReceiptPointsHandler().GetPoints)
+	// v1 is served under /v1 and, for existing clients, at its original unprefixed paths; both announce its sunset
+	if err := registerV1Routes(c, g.Group("/v1", c.NewV1DeprecationMiddleware()), "/v1"); err != nil {
+		log.Fatalf("failed to register v1 routes: %v", err)
+	}
+	if err := registerV1Routes(c, g.Group("/", c.NewV1DeprecationMiddleware()), ""); err != nil {
+		log.Fatalf("failed to register v1 routes: %v", err)
+	}
+	if err := registerV2Routes(c, g.Group("/v2")); err != nil {
+		log.Fatalf("failed to register v2 routes: %v", err)
+	}
+
+	// Routes that are not part of a versioned API run under REQUEST_TIMEOUT
+	unversioned := g.Group("/", c.NewRequestContextMiddleware())
+
+	graphQLHandler, err := c.NewGraphQLHandler()
+	if err != nil {
+		log.Fatalf("failed to build GraphQL schema: %v", err)
+	}
+	unversioned.POST("/graphql", graphQLHandler.Query)
+
+	openAPIHandler, err := c.NewOpenAPIHandler()
+	if err != nil {
+		log.Fatalf("failed to load OpenAPI document: %v", err)
+	}
+	unversioned.GET("/openapi.json", openAPIHandler.Spec)
+	unversioned.GET("/docs", openAPIHandler.Docs)
+
+	unversioned.GET("/health/ready", c.NewReadinessHandler().Ready)
+
+	// Admin routes are only available when the configured adapters support them
+	if h := c.NewBackupHandler(); h != nil {
+		unversioned.POST("/admin/backup", h.Backup)
+	}
+	if h := c.NewDeadLetterHandler(); h != nil {
+		unversioned.GET("/admin/dead-letters", h.ListDeadLetters)
+		unversioned.GET("/admin/dead-letters/:id", h.GetDeadLetter)
+		unversioned.POST("/admin/dead-letters/:id/requeue", h.RequeueDeadLetter)
+	}
 
 	// Start the Gin HTTP server on port 8080.
-	g.Run(":8080")
+	if err := g.Run(":8080"); err != nil {
+		log.Printf("server stopped: %v", err)
+	}
+}
+
+// registerV1Routes registers the v1 REST API on group, whose prefix is basePath
+func registerV1Routes(c *container.Container, group *gin.RouterGroup, basePath string) error {
+	// Streaming uploads may run far longer than REQUEST_TIMEOUT, so it is applied to each line instead
+	group.POST("/receipts/stream", c.NewStreamingRequestContextMiddleware(), c.NewStreamProcessHandler().ProcessStream)
+	// The event stream stays open until the client disconnects
+	group.GET("/receipts/stream", c.NewStreamingRequestContextMiddleware(), c.NewReceiptEventsHandler().StreamReceipts)
+
+	// Every other route runs under REQUEST_TIMEOUT, and those the OpenAPI document describes are validated
+	// against it before reaching their handler
+	validation, err := c.NewV1ValidationMiddleware(basePath)
+	if err != nil {
+		return err
+	}
+	emailHandler, err := c.NewEmailReceiptHandler()
+	if err != nil {
+		return err
+	}
+	api := group.Group("/", c.NewRequestContextMiddleware(), validation)
+	api.POST("/receipt/process", c.NewIdempotencyMiddleware(), c.NewReceiptProcessHandlerFunc())
+	api.POST("/receipts/batch", c.NewBatchProcessHandler().ProcessBatch)
+	api.POST("/receipts/text", c.NewTextReceiptHandler().ProcessText)
+	api.POST("/receipts/email", emailHandler.ProcessEmail)
+	api.GET("/receipt/:id", c.NewGetReceiptHandler().GetReceipt)
+	api.GET("/receipt/:id/points", c.NewGetReceiptPointsHandler().GetPoints)
+	api.GET("/receipts", c.NewListReceiptsHandler().ListReceipts)
+
+	updateHandler := c.NewUpdateReceiptHandler()
+	api.PUT("/receipt/:id", updateHandler.PutReceipt)
+	api.PATCH("/receipt/:id", updateHandler.PatchReceipt)
+	api.GET("/receipt/:id/revisions", updateHandler.GetRevisions)
+
+	deleteHandler := c.NewDeleteReceiptHandler()
+	api.DELETE("/receipt/:id", deleteHandler.DeleteReceipt)
+	api.POST("/receipts/erasure", deleteHandler.EraseReceipts)
+
+	webhookHandler := c.NewWebhookHandler()
+	api.POST("/webhooks", webhookHandler.CreateSubscription)
+	api.GET("/webhooks", webhookHandler.ListSubscriptions)
+	api.GET("/webhooks/:id", webhookHandler.GetSubscription)
+	api.DELETE("/webhooks/:id", webhookHandler.DeleteSubscription)
+	api.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+	return nil
+}
+
+// registerV2Routes registers the v2 REST API, which reports points breakdowns and wraps errors in an envelope, on group
+func registerV2Routes(c *container.Container, group *gin.RouterGroup) error {
+	validation, err := c.NewV2ValidationMiddleware()
+	if err != nil {
+		return err
+	}
+	api := group.Group("/", c.NewRequestContextMiddleware(), validation)
+
+	handler := c.NewReceiptV2Handler()
+	api.POST("/receipt/process", c.NewIdempotencyMiddleware(), c.NewReceiptV2ProcessHandlerFunc())
+	api.GET("/receipt/:id/points", handler.GetPoints)
+	return nil
 }
@@ -0,0 +1,246 @@
+package container
+
+import (
+	"fmt"
+	"go-receipt-processor/internal/application"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Supported values for Config.ProcessingMode.
+const (
+	ProcessingModeSync  = "sync"
+	ProcessingModeAsync = "async"
+)
+
+// Supported values for Config.StoreDriver.
+const (
+	StoreDriverMemory   = "memory"
+	StoreDriverBolt     = "bolt"
+	StoreDriverRedis    = "redis"
+	StoreDriverPostgres = "postgres"
+)
+
+// Config holds the deployment settings used to build the Container.
+type Config struct {
+	RequestTimeout time.Duration // Deadline applied to each HTTP request's and unary gRPC call's context; zero disables it
+	GRPCAddr       string        // Address the gRPC server listens on
+
+	APIV1DeprecatedAt time.Time // Announced in the Deprecation header of every v1 response
+	APIV1Sunset       time.Time // Announced in the Sunset header of every v1 response as the date v1 is removed
+
+	BatchMaxSize int // Most receipts accepted by a single batch request
+	BatchWorkers int // Receipts of a batch scored in parallel; zero uses one worker per CPU
+
+	IdempotencyWindow time.Duration // How long an Idempotency-Key is remembered after its first use
+	DuplicatePolicy   string        // What happens to resubmitted receipts ("off", "flag" or "reject")
+
+	ProcessingMode string // Whether POST /receipt/process scores receipts before responding ("sync") or queues them ("async")
+	QueueCapacity  int    // Most receipts waiting in the processing queue in async mode
+	QueueWorkers   int    // Receipts processed concurrently in async mode; zero uses one worker per CPU
+
+	RetryMaxAttempts    int           // Attempts at storing a queued receipt before it is dead-lettered
+	RetryInitialBackoff time.Duration // Wait before the first retry; each later wait doubles
+	RetryMaxBackoff     time.Duration // Upper bound on the wait between retries
+
+	WebhookWorkers        int           // Webhook deliveries made concurrently
+	WebhookBuffer         int           // Events waiting for delivery before new ones are dropped
+	WebhookMaxAttempts    int           // Attempts at delivering an event to a subscription before giving up
+	WebhookInitialBackoff time.Duration // Wait before the first redelivery; each later wait doubles
+	WebhookMaxBackoff     time.Duration // Upper bound on the wait between redeliveries
+	WebhookTimeout        time.Duration // Upper bound on each delivery attempt
+
+	StreamReplaySize int           // Recent events kept so GET /receipts/stream clients can resume with Last-Event-ID
+	StreamHeartbeat  time.Duration // Interval of keep-alive comments on idle event streams; zero disables them
+
+	StoreDriver string // Which ReceiptStore adapter to use ("memory", "bolt", "redis" or "postgres")
+	BoltPath    string // Database file used by the bolt store
+	BackupDir   string // Directory online backups are written to
+
+	RedisAddr      string        // host:port of the Redis server used by the redis store
+	RedisKeyPrefix string        // Prefix applied to every key written by the redis store
+	RedisTTL       time.Duration // Expiry of stored receipts; zero keeps them forever
+	RedisEncoding  string        // Value encoding used by the redis store ("json" or "msgpack")
+
+	PostgresDSN          string        // Connection string used by the postgres store
+	PostgresMaxConns     int32         // Upper bound on pooled connections; zero keeps the driver default
+	PostgresMinConns     int32         // Connections kept open while idle
+	PostgresQueryTimeout time.Duration // Upper bound applied to each postgres store call
+
+	EmailTemplates string // JSON file of email receipt templates tried before the built-in ones; empty uses only the built-in ones
+}
+
+// LoadConfig
+//
+// Returns:
+//   - A Config populated from environment variables, falling back to defaults for unset values:
+//     REQUEST_TIMEOUT (10s), GRPC_ADDR (:9090), API_V1_DEPRECATED_AT (2026-10-19), API_V1_SUNSET (2027-04-30), BATCH_MAX_SIZE (1000), BATCH_WORKERS (0), IDEMPOTENCY_WINDOW (24h),
+//     DUPLICATE_POLICY (off), PROCESSING_MODE (sync), QUEUE_CAPACITY (1000), QUEUE_WORKERS (0),
+//     RETRY_MAX_ATTEMPTS (5), RETRY_INITIAL_BACKOFF (100ms), RETRY_MAX_BACKOFF (10s),
+//     WEBHOOK_WORKERS (4), WEBHOOK_BUFFER (1000), WEBHOOK_MAX_ATTEMPTS (5), WEBHOOK_INITIAL_BACKOFF (1s),
+//     WEBHOOK_MAX_BACKOFF (1m), WEBHOOK_TIMEOUT (5s), STREAM_REPLAY_SIZE (1000), STREAM_HEARTBEAT (15s),
+//     RECEIPT_STORE (memory), RECEIPT_BOLT_PATH (receipts.db), RECEIPT_BACKUP_DIR (current directory),
+//     REDIS_ADDR (localhost:6379), REDIS_KEY_PREFIX (receipts:), REDIS_TTL (0), REDIS_ENCODING (json),
+//     POSTGRES_DSN (postgres://localhost:5432/receipts), POSTGRES_MAX_CONNS (0), POSTGRES_MIN_CONNS (0)
+//     POSTGRES_QUERY_TIMEOUT (5s) and EMAIL_TEMPLATES (unset).
+//   - err: An error if a variable holds a value that cannot be parsed.
+func LoadConfig() (Config, error) {
+	requestTimeout, err := time.ParseDuration(getEnv("REQUEST_TIMEOUT", "10s"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid REQUEST_TIMEOUT: %v", err)
+	}
+	apiV1DeprecatedAt, err := time.Parse(time.DateOnly, getEnv("API_V1_DEPRECATED_AT", "2026-10-19"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid API_V1_DEPRECATED_AT: %v", err)
+	}
+	apiV1Sunset, err := time.Parse(time.DateOnly, getEnv("API_V1_SUNSET", "2027-04-30"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid API_V1_SUNSET: %v", err)
+	}
+	batchMaxSize, err := strconv.Atoi(getEnv("BATCH_MAX_SIZE", "1000"))
+	if err != nil || batchMaxSize <= 0 {
+		return Config{}, fmt.Errorf("invalid BATCH_MAX_SIZE: must be a positive integer")
+	}
+	batchWorkers, err := strconv.Atoi(getEnv("BATCH_WORKERS", "0"))
+	if err != nil || batchWorkers < 0 {
+		return Config{}, fmt.Errorf("invalid BATCH_WORKERS: must be zero or a positive integer")
+	}
+	idempotencyWindow, err := time.ParseDuration(getEnv("IDEMPOTENCY_WINDOW", "24h"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid IDEMPOTENCY_WINDOW: %v", err)
+	}
+	duplicatePolicy := getEnv("DUPLICATE_POLICY", string(application.DuplicatePolicyOff))
+	switch application.DuplicatePolicy(duplicatePolicy) {
+	case application.DuplicatePolicyOff, application.DuplicatePolicyFlag, application.DuplicatePolicyReject:
+	default:
+		return Config{}, fmt.Errorf("invalid DUPLICATE_POLICY: must be off, flag or reject")
+	}
+	processingMode := getEnv("PROCESSING_MODE", ProcessingModeSync)
+	if processingMode != ProcessingModeSync && processingMode != ProcessingModeAsync {
+		return Config{}, fmt.Errorf("invalid PROCESSING_MODE: must be sync or async")
+	}
+	queueCapacity, err := strconv.Atoi(getEnv("QUEUE_CAPACITY", "1000"))
+	if err != nil || queueCapacity <= 0 {
+		return Config{}, fmt.Errorf("invalid QUEUE_CAPACITY: must be a positive integer")
+	}
+	queueWorkers, err := strconv.Atoi(getEnv("QUEUE_WORKERS", "0"))
+	if err != nil || queueWorkers < 0 {
+		return Config{}, fmt.Errorf("invalid QUEUE_WORKERS: must be zero or a positive integer")
+	}
+	retryMaxAttempts, err := strconv.Atoi(getEnv("RETRY_MAX_ATTEMPTS", "5"))
+	if err != nil || retryMaxAttempts <= 0 {
+		return Config{}, fmt.Errorf("invalid RETRY_MAX_ATTEMPTS: must be a positive integer")
+	}
+	retryInitialBackoff, err := time.ParseDuration(getEnv("RETRY_INITIAL_BACKOFF", "100ms"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid RETRY_INITIAL_BACKOFF: %v", err)
+	}
+	retryMaxBackoff, err := time.ParseDuration(getEnv("RETRY_MAX_BACKOFF", "10s"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid RETRY_MAX_BACKOFF: %v", err)
+	}
+	webhookWorkers, err := strconv.Atoi(getEnv("WEBHOOK_WORKERS", "4"))
+	if err != nil || webhookWorkers <= 0 {
+		return Config{}, fmt.Errorf("invalid WEBHOOK_WORKERS: must be a positive integer")
+	}
+	webhookBuffer, err := strconv.Atoi(getEnv("WEBHOOK_BUFFER", "1000"))
+	if err != nil || webhookBuffer <= 0 {
+		return Config{}, fmt.Errorf("invalid WEBHOOK_BUFFER: must be a positive integer")
+	}
+	webhookMaxAttempts, err := strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", "5"))
+	if err != nil || webhookMaxAttempts <= 0 {
+		return Config{}, fmt.Errorf("invalid WEBHOOK_MAX_ATTEMPTS: must be a positive integer")
+	}
+	webhookInitialBackoff, err := time.ParseDuration(getEnv("WEBHOOK_INITIAL_BACKOFF", "1s"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid WEBHOOK_INITIAL_BACKOFF: %v", err)
+	}
+	webhookMaxBackoff, err := time.ParseDuration(getEnv("WEBHOOK_MAX_BACKOFF", "1m"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid WEBHOOK_MAX_BACKOFF: %v", err)
+	}
+	webhookTimeout, err := time.ParseDuration(getEnv("WEBHOOK_TIMEOUT", "5s"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid WEBHOOK_TIMEOUT: %v", err)
+	}
+	streamReplaySize, err := strconv.Atoi(getEnv("STREAM_REPLAY_SIZE", "1000"))
+	if err != nil || streamReplaySize <= 0 {
+		return Config{}, fmt.Errorf("invalid STREAM_REPLAY_SIZE: must be a positive integer")
+	}
+	streamHeartbeat, err := time.ParseDuration(getEnv("STREAM_HEARTBEAT", "15s"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid STREAM_HEARTBEAT: %v", err)
+	}
+	redisTTL, err := time.ParseDuration(getEnv("REDIS_TTL", "0"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid REDIS_TTL: %v", err)
+	}
+	postgresMaxConns, err := strconv.ParseInt(getEnv("POSTGRES_MAX_CONNS", "0"), 10, 32)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid POSTGRES_MAX_CONNS: %v", err)
+	}
+	postgresMinConns, err := strconv.ParseInt(getEnv("POSTGRES_MIN_CONNS", "0"), 10, 32)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid POSTGRES_MIN_CONNS: %v", err)
+	}
+	postgresQueryTimeout, err := time.ParseDuration(getEnv("POSTGRES_QUERY_TIMEOUT", "5s"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid POSTGRES_QUERY_TIMEOUT: %v", err)
+	}
+
+	return Config{
+		RequestTimeout: requestTimeout,
+		GRPCAddr:       getEnv("GRPC_ADDR", ":9090"),
+
+		APIV1DeprecatedAt: apiV1DeprecatedAt,
+		APIV1Sunset:       apiV1Sunset,
+
+		BatchMaxSize: batchMaxSize,
+		BatchWorkers: batchWorkers,
+
+		IdempotencyWindow: idempotencyWindow,
+		DuplicatePolicy:   duplicatePolicy,
+
+		ProcessingMode: processingMode,
+		QueueCapacity:  queueCapacity,
+		QueueWorkers:   queueWorkers,
+
+		RetryMaxAttempts:    retryMaxAttempts,
+		RetryInitialBackoff: retryInitialBackoff,
+		RetryMaxBackoff:     retryMaxBackoff,
+
+		WebhookWorkers:        webhookWorkers,
+		WebhookBuffer:         webhookBuffer,
+		WebhookMaxAttempts:    webhookMaxAttempts,
+		WebhookInitialBackoff: webhookInitialBackoff,
+		WebhookMaxBackoff:     webhookMaxBackoff,
+		WebhookTimeout:        webhookTimeout,
+
+		StreamReplaySize: streamReplaySize,
+		StreamHeartbeat:  streamHeartbeat,
+
+		StoreDriver:    getEnv("RECEIPT_STORE", StoreDriverMemory),
+		BoltPath:       getEnv("RECEIPT_BOLT_PATH", "receipts.db"),
+		BackupDir:      getEnv("RECEIPT_BACKUP_DIR", "."),
+		RedisAddr:      getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisKeyPrefix: getEnv("REDIS_KEY_PREFIX", "receipts:"),
+		RedisTTL:       redisTTL,
+		RedisEncoding:  getEnv("REDIS_ENCODING", "json"),
+
+		PostgresDSN:          getEnv("POSTGRES_DSN", "postgres://localhost:5432/receipts"),
+		PostgresMaxConns:     int32(postgresMaxConns),
+		PostgresMinConns:     int32(postgresMinConns),
+		PostgresQueryTimeout: postgresQueryTimeout,
+
+		EmailTemplates: getEnv("EMAIL_TEMPLATES", ""),
+	}, nil
+}
+
+// getEnv returns the value of the environment variable key, or fallback if it is unset or empty
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -1,28 +1,128 @@
 package container
 
 import (
+	"context"
+	"fmt"
+	"go-receipt-processor/api"
+	"go-receipt-processor/internal/adapters/bolt"
+	"go-receipt-processor/internal/adapters/email"
+	adaptersGraphql "go-receipt-processor/internal/adapters/graphql"
+	adaptersGrpc "go-receipt-processor/internal/adapters/grpc"
 	adaptersHttp "go-receipt-processor/internal/adapters/http"
 	"go-receipt-processor/internal/adapters/memory"
+	"go-receipt-processor/internal/adapters/postgres"
+	"go-receipt-processor/internal/adapters/redis"
+	"go-receipt-processor/internal/adapters/webhook"
+	"go-receipt-processor/internal/adapters/worker"
 	"go-receipt-processor/internal/application"
 	portsHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/grpc/receiptpb"
+	"go-receipt-processor/internal/ports/repository"
+	"io"
+	"runtime"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	goredis "github.com/redis/go-redis/v9"
+	googleGrpc "google.golang.org/grpc"
 )
 
 // Container holds the application's dependencies
 type Container struct {
-	ReceiptService portsHttp.ReceiptService
+	Config           Config
+	ReceiptStore     repository.ReceiptStore
+	IdempotencyStore repository.IdempotencyStore
+	ReceiptQueue     repository.ReceiptQueue // nil unless PROCESSING_MODE is async
+	WebhookStore     repository.WebhookStore
+	Webhooks         *webhook.Dispatcher // Delivers receipt events to webhook subscribers once started with Run
+	EventStream      repository.EventStream
+	PointsCalculator portsHttp.PointsCalculator
+	ReceiptService   portsHttp.ReceiptService
 }
 
 // NewContainer
 //
+// Parameters:
+//   - cfg: The Config selecting which adapters to build.
+//
 // Returns:
 //   - A new instance of Container with all dependencies initialized.
-func NewContainer() *Container {
-	return &Container{
-		ReceiptService: application.NewReceiptService(
-			application.NewPointsCalculator(application.NewPointsCalculatorHelper()),
-			memory.NewReceiptStore(),
-		),
+//   - err: An error if the configured store cannot be created.
+func NewContainer(cfg Config) (*Container, error) {
+	store, err := newReceiptStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookStore := memory.NewWebhookStore()
+	dispatcher := webhook.NewDispatcher(webhookStore, nil, webhook.Options{
+		Workers:        cfg.WebhookWorkers,
+		BufferSize:     cfg.WebhookBuffer,
+		MaxAttempts:    cfg.WebhookMaxAttempts,
+		InitialBackoff: cfg.WebhookInitialBackoff,
+		MaxBackoff:     cfg.WebhookMaxBackoff,
+		Timeout:        cfg.WebhookTimeout,
+	})
+
+	eventStream := memory.NewEventStream(cfg.StreamReplaySize)
+	calculator := application.NewPointsCalculator(application.NewPointsCalculatorHelper())
+
+	opts := []application.ReceiptServiceOption{
+		application.WithBatchWorkers(cfg.BatchWorkers),
+		application.WithDuplicatePolicy(application.DuplicatePolicy(cfg.DuplicatePolicy)),
+		application.WithEventPublisher(dispatcher),
+		application.WithEventPublisher(eventStream),
+	}
+	var queue repository.ReceiptQueue
+	if cfg.ProcessingMode == ProcessingModeAsync {
+		queue = memory.NewReceiptQueue(cfg.QueueCapacity)
+		opts = append(opts,
+			application.WithReceiptQueue(queue),
+			application.WithDeadLetterStore(memory.NewDeadLetterStore()),
+			application.WithRetryPolicy(application.RetryPolicy{
+				MaxAttempts:    cfg.RetryMaxAttempts,
+				InitialBackoff: cfg.RetryInitialBackoff,
+				MaxBackoff:     cfg.RetryMaxBackoff,
+			}),
+		)
 	}
+
+	return &Container{
+		Config:           cfg,
+		ReceiptStore:     store,
+		IdempotencyStore: memory.NewIdempotencyStore(),
+		ReceiptQueue:     queue,
+		WebhookStore:     webhookStore,
+		Webhooks:         dispatcher,
+		EventStream:      eventStream,
+		PointsCalculator: calculator,
+		ReceiptService:   application.NewReceiptService(calculator, store, opts...),
+	}, nil
+}
+
+// NewRequestContextMiddleware
+//
+// Returns:
+//   - A gin middleware that attaches request ID, tenant ID and the configured deadline to each request's context.
+func (c *Container) NewRequestContextMiddleware() gin.HandlerFunc {
+	return adaptersHttp.RequestContextMiddleware(c.Config.RequestTimeout)
+}
+
+// NewStreamingRequestContextMiddleware
+//
+// Returns:
+//   - A gin middleware like NewRequestContextMiddleware but without the overall deadline, for streaming uploads
+//     that may legitimately run far longer than REQUEST_TIMEOUT.
+func (c *Container) NewStreamingRequestContextMiddleware() gin.HandlerFunc {
+	return adaptersHttp.RequestContextMiddleware(0)
+}
+
+// NewIdempotencyMiddleware
+//
+// Returns:
+//   - A gin middleware that replays the recorded response for requests retried with the same Idempotency-Key.
+func (c *Container) NewIdempotencyMiddleware() gin.HandlerFunc {
+	return adaptersHttp.IdempotencyMiddleware(c.IdempotencyStore, c.Config.IdempotencyWindow)
 }
 
 // NewReceiptProcessHandler
@@ -33,6 +133,38 @@ func (c *Container) NewReceiptProcessHandler() *adaptersHttp.ReceiptProcessHandl
 	return adaptersHttp.NewReceiptProcessHandler(c.ReceiptService)
 }
 
+// NewReceiptProcessHandlerFunc
+//
+// Returns:
+//   - The handler for POST /receipt/process: ProcessReceipt, or EnqueueReceipt when PROCESSING_MODE is async.
+func (c *Container) NewReceiptProcessHandlerFunc() gin.HandlerFunc {
+	h := c.NewReceiptProcessHandler()
+	if c.ReceiptQueue != nil {
+		return h.EnqueueReceipt
+	}
+	return h.ProcessReceipt
+}
+
+// NewReceiptV2Handler
+//
+// Returns:
+//   - A new instance of ReceiptV2Handler, which serves the v2 receipt endpoints.
+func (c *Container) NewReceiptV2Handler() *adaptersHttp.ReceiptV2Handler {
+	return adaptersHttp.NewReceiptV2Handler(c.ReceiptService, c.PointsCalculator)
+}
+
+// NewReceiptV2ProcessHandlerFunc
+//
+// Returns:
+//   - The handler for POST /v2/receipt/process: ProcessReceipt, or EnqueueReceipt when PROCESSING_MODE is async.
+func (c *Container) NewReceiptV2ProcessHandlerFunc() gin.HandlerFunc {
+	h := c.NewReceiptV2Handler()
+	if c.ReceiptQueue != nil {
+		return h.EnqueueReceipt
+	}
+	return h.ProcessReceipt
+}
+
 // NewGetReceiptPointsHandler
 //
 // Returns:
@@ -40,3 +172,296 @@ func (c *Container) NewReceiptProcessHandler() *adaptersHttp.ReceiptProcessHandl
 func (c *Container) NewGetReceiptPointsHandler() *adaptersHttp.GetReceiptPointsHandler {
 	return adaptersHttp.NewGetReceiptPointsHandler(c.ReceiptService)
 }
+
+// NewGetReceiptHandler
+//
+// Returns:
+//   - A new instance of GetReceiptHandler, which can handle requests to retrieve a stored receipt.
+func (c *Container) NewGetReceiptHandler() *adaptersHttp.GetReceiptHandler {
+	return adaptersHttp.NewGetReceiptHandler(c.ReceiptService)
+}
+
+// NewListReceiptsHandler
+//
+// Returns:
+//   - A new instance of ListReceiptsHandler, which can handle requests to list and search receipts.
+func (c *Container) NewListReceiptsHandler() *adaptersHttp.ListReceiptsHandler {
+	return adaptersHttp.NewListReceiptsHandler(c.ReceiptService)
+}
+
+// NewUpdateReceiptHandler
+//
+// Returns:
+//   - A new instance of UpdateReceiptHandler, which can handle receipt corrections and revision history requests.
+func (c *Container) NewUpdateReceiptHandler() *adaptersHttp.UpdateReceiptHandler {
+	return adaptersHttp.NewUpdateReceiptHandler(c.ReceiptService)
+}
+
+// NewBatchProcessHandler
+//
+// Returns:
+//   - A new instance of BatchProcessHandler, which can handle batch receipt processing requests.
+func (c *Container) NewBatchProcessHandler() *adaptersHttp.BatchProcessHandler {
+	return adaptersHttp.NewBatchProcessHandler(c.ReceiptService, c.Config.BatchMaxSize)
+}
+
+// NewTextReceiptHandler
+//
+// Returns:
+//   - A new instance of TextReceiptHandler, which can handle the plain text of printed receipts.
+func (c *Container) NewTextReceiptHandler() *adaptersHttp.TextReceiptHandler {
+	return adaptersHttp.NewTextReceiptHandler(c.ReceiptService)
+}
+
+// NewEmailExtractor
+//
+// Returns:
+//   - A new instance of email.Extractor trying the templates of EMAIL_TEMPLATES, if set, before the built-in ones.
+//   - err: An error if the templates cannot be read or one of them is invalid.
+func (c *Container) NewEmailExtractor() (*email.Extractor, error) {
+	templates, err := email.DefaultTemplates()
+	if err != nil {
+		return nil, err
+	}
+	if c.Config.EmailTemplates != "" {
+		custom, err := email.LoadTemplates(c.Config.EmailTemplates)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(custom, templates...)
+	}
+	return email.NewExtractor(templates)
+}
+
+// NewEmailReceiptHandler
+//
+// Returns:
+//   - A new instance of EmailReceiptHandler, which can handle receipts delivered by email.
+//   - err: An error if the email templates cannot be loaded.
+func (c *Container) NewEmailReceiptHandler() (*adaptersHttp.EmailReceiptHandler, error) {
+	extractor, err := c.NewEmailExtractor()
+	if err != nil {
+		return nil, err
+	}
+	return adaptersHttp.NewEmailReceiptHandler(c.ReceiptService, extractor), nil
+}
+
+// NewStreamProcessHandler
+//
+// Returns:
+//   - A new instance of StreamProcessHandler, which can handle NDJSON receipt uploads,
+//     applying REQUEST_TIMEOUT to each line rather than to the whole upload.
+func (c *Container) NewStreamProcessHandler() *adaptersHttp.StreamProcessHandler {
+	return adaptersHttp.NewStreamProcessHandler(c.ReceiptService, c.Config.RequestTimeout)
+}
+
+// NewReceiptEventsHandler
+//
+// Returns:
+//   - A new instance of ReceiptEventsHandler, which streams newly scored receipts as server-sent events.
+func (c *Container) NewReceiptEventsHandler() *adaptersHttp.ReceiptEventsHandler {
+	return adaptersHttp.NewReceiptEventsHandler(c.EventStream, c.Config.StreamHeartbeat)
+}
+
+// NewDeleteReceiptHandler
+//
+// Returns:
+//   - A new instance of DeleteReceiptHandler, which can handle receipt deletion and bulk erasure requests.
+func (c *Container) NewDeleteReceiptHandler() *adaptersHttp.DeleteReceiptHandler {
+	return adaptersHttp.NewDeleteReceiptHandler(c.ReceiptService)
+}
+
+// NewOpenAPIHandler
+//
+// Returns:
+//   - A new instance of OpenAPIHandler, which serves the OpenAPI document and its docs page.
+//   - err: An error if the embedded document is invalid.
+func (c *Container) NewOpenAPIHandler() (*adaptersHttp.OpenAPIHandler, error) {
+	doc, err := api.LoadOpenAPI()
+	if err != nil {
+		return nil, err
+	}
+	return adaptersHttp.NewOpenAPIHandler(doc)
+}
+
+// NewV1ValidationMiddleware
+//
+// Parameters:
+//   - basePath: The prefix of the route group the middleware is used in, "/v1" or empty for the unprefixed aliases.
+//
+// Returns:
+//   - A gin middleware that rejects requests not matching the OpenAPI document before they reach their handler.
+//   - err: An error if the embedded document is invalid.
+func (c *Container) NewV1ValidationMiddleware(basePath string) (gin.HandlerFunc, error) {
+	return newOpenAPIValidationMiddleware(basePath, adaptersHttp.WriteValidationError)
+}
+
+// NewV2ValidationMiddleware
+//
+// Returns:
+//   - A gin middleware like NewV1ValidationMiddleware for the /v2 routes, answering in the v2 error envelope.
+//   - err: An error if the embedded document is invalid.
+func (c *Container) NewV2ValidationMiddleware() (gin.HandlerFunc, error) {
+	return newOpenAPIValidationMiddleware("/v2", adaptersHttp.WriteV2ValidationError)
+}
+
+// newOpenAPIValidationMiddleware builds the validation middleware for a route group from the embedded document
+func newOpenAPIValidationMiddleware(basePath string, writeError adaptersHttp.ValidationErrorWriter) (gin.HandlerFunc, error) {
+	doc, err := api.LoadOpenAPI()
+	if err != nil {
+		return nil, err
+	}
+	validator, err := api.NewValidator(doc)
+	if err != nil {
+		return nil, err
+	}
+	return adaptersHttp.OpenAPIValidationMiddleware(validator, basePath, writeError), nil
+}
+
+// NewV1DeprecationMiddleware
+//
+// Returns:
+//   - A gin middleware that announces API_V1_DEPRECATED_AT and API_V1_SUNSET on every v1 response.
+func (c *Container) NewV1DeprecationMiddleware() gin.HandlerFunc {
+	return adaptersHttp.DeprecationMiddleware(c.Config.APIV1DeprecatedAt, c.Config.APIV1Sunset)
+}
+
+// NewGraphQLHandler
+//
+// Returns:
+//   - A new instance of GraphQLHandler serving the receipt schema, resolved through the ReceiptService.
+//   - err: An error if the schema does not match its resolvers.
+func (c *Container) NewGraphQLHandler() (*adaptersHttp.GraphQLHandler, error) {
+	schema, err := adaptersGraphql.NewSchema(c.ReceiptService, c.PointsCalculator)
+	if err != nil {
+		return nil, err
+	}
+	return adaptersHttp.NewGraphQLHandler(schema), nil
+}
+
+// NewGRPCServer
+//
+// Returns:
+//   - A new gRPC server exposing the receipt.v1.ReceiptService, backed by the same ReceiptService as the HTTP API.
+//     Unary calls run under REQUEST_TIMEOUT; ProcessReceipts applies it to each receipt of the stream instead.
+func (c *Container) NewGRPCServer() *googleGrpc.Server {
+	server := googleGrpc.NewServer(
+		googleGrpc.UnaryInterceptor(adaptersGrpc.RequestContextUnaryInterceptor(c.Config.RequestTimeout)),
+		googleGrpc.StreamInterceptor(adaptersGrpc.RequestContextStreamInterceptor()),
+	)
+	receiptpb.RegisterReceiptServiceServer(server, adaptersGrpc.NewReceiptServer(c.ReceiptService, c.Config.RequestTimeout))
+	return server
+}
+
+// NewReceiptWorkerPool
+//
+// Returns:
+//   - A new instance of ReceiptWorkerPool that processes queued receipts with QUEUE_WORKERS workers,
+//     or nil if PROCESSING_MODE is not async.
+func (c *Container) NewReceiptWorkerPool() *worker.ReceiptWorkerPool {
+	if c.ReceiptQueue == nil {
+		return nil
+	}
+	workers := c.Config.QueueWorkers
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+	return worker.NewReceiptWorkerPool(c.ReceiptService, workers)
+}
+
+// NewDeadLetterHandler
+//
+// Returns:
+//   - A new instance of DeadLetterHandler, or nil if PROCESSING_MODE is not async and nothing is ever dead-lettered.
+func (c *Container) NewDeadLetterHandler() *adaptersHttp.DeadLetterHandler {
+	if c.ReceiptQueue == nil {
+		return nil
+	}
+	return adaptersHttp.NewDeadLetterHandler(c.ReceiptService)
+}
+
+// NewWebhookHandler
+//
+// Returns:
+//   - A new instance of WebhookHandler, which can handle webhook subscription and delivery log requests.
+func (c *Container) NewWebhookHandler() *adaptersHttp.WebhookHandler {
+	return adaptersHttp.NewWebhookHandler(c.WebhookStore)
+}
+
+// NewBackupHandler
+//
+// Returns:
+//   - A new instance of BackupHandler, or nil if the configured store does not support online backups.
+func (c *Container) NewBackupHandler() *adaptersHttp.BackupHandler {
+	store, ok := c.ReceiptStore.(repository.BackupStore)
+	if !ok {
+		return nil
+	}
+	return adaptersHttp.NewBackupHandler(store, c.Config.BackupDir)
+}
+
+// NewReadinessHandler
+//
+// Returns:
+//   - A new instance of ReadinessHandler that checks every configured adapter able to report its health.
+func (c *Container) NewReadinessHandler() *adaptersHttp.ReadinessHandler {
+	checkers := map[string]repository.HealthChecker{}
+	if checker, ok := c.ReceiptStore.(repository.HealthChecker); ok {
+		checkers["receiptStore"] = checker
+	}
+	return adaptersHttp.NewReadinessHandler(checkers)
+}
+
+// Close releases any resources held by the configured adapters.
+func (c *Container) Close() error {
+	if closer, ok := c.ReceiptStore.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// newReceiptStore builds the ReceiptStore adapter selected by cfg.StoreDriver
+func newReceiptStore(cfg Config) (repository.ReceiptStore, error) {
+	switch cfg.StoreDriver {
+	case StoreDriverMemory, "":
+		return memory.NewReceiptStore(), nil
+	case StoreDriverBolt:
+		return bolt.NewReceiptStore(cfg.BoltPath)
+	case StoreDriverRedis:
+		client := goredis.NewClient(&goredis.Options{Addr: cfg.RedisAddr})
+		store, err := redis.NewReceiptStore(client, redis.Options{
+			KeyPrefix: cfg.RedisKeyPrefix,
+			TTL:       cfg.RedisTTL,
+			Encoding:  redis.Encoding(cfg.RedisEncoding),
+		})
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+		return store, nil
+	case StoreDriverPostgres:
+		return newPostgresReceiptStore(cfg)
+	default:
+		return nil, fmt.Errorf("unknown receipt store driver '%s'", cfg.StoreDriver)
+	}
+}
+
+// newPostgresReceiptStore connects the postgres pool, applies pending migrations and builds the store
+func newPostgresReceiptStore(cfg Config) (repository.ReceiptStore, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := postgres.NewPool(ctx, cfg.PostgresDSN, postgres.PoolOptions{
+		MaxConns: cfg.PostgresMaxConns,
+		MinConns: cfg.PostgresMinConns,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := postgres.Migrate(ctx, pool); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return postgres.NewReceiptStore(pool, cfg.PostgresQueryTimeout), nil
+}
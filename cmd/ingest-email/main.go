@@ -0,0 +1,136 @@
+// Command ingest-email extracts receipts from e-receipt emails saved as .eml files and stores them with the
+// service's configured receipt store, as POST /receipts/email does. It is configured with the same
+// environment variables as the server, so RECEIPT_STORE must name a persistent store for the receipts to
+// be visible to it.
+//
+// Usage:
+//
+//	ingest-email [-dry-run] [file.eml ...]
+//
+// With no files, or "-", the message is read from standard input. One JSON line is printed per message, and
+// the command exits with status 1 if any of them could not be stored.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go-receipt-processor/api"
+	"go-receipt-processor/cmd/container"
+	"go-receipt-processor/internal/adapters/email"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	"io"
+	"log"
+	"os"
+)
+
+// result reports what became of one message
+type result struct {
+	File string `json:"file"`
+	response.ParsedReceiptResponse
+}
+
+// main is the entry point of the command.
+func main() {
+	dryRun := flag.Bool("dry-run", false, "extract and validate the receipts without storing them")
+	flag.Parse()
+
+	cfg, err := container.LoadConfig()
+	if err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
+	c, err := container.NewContainer(cfg)
+	if err != nil {
+		log.Fatalf("failed to initialize container: %v", err)
+	}
+	defer c.Close()
+
+	extractor, err := c.NewEmailExtractor()
+	if err != nil {
+		log.Fatalf("failed to load email templates: %v", err)
+	}
+
+	files := flag.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	failed := false
+	for _, file := range files {
+		out := ingest(context.Background(), c.ReceiptService, extractor, file, *dryRun)
+		if out.Error != "" {
+			failed = true
+		}
+		if err := encoder.Encode(out); err != nil {
+			log.Fatalf("failed to write result: %v", err)
+		}
+	}
+	if failed {
+		c.Close()
+		os.Exit(1)
+	}
+}
+
+// ingest extracts the receipt from one message and, unless dryRun is set, processes it
+func ingest(ctx context.Context, service internalHttp.ReceiptService, extractor *email.Extractor, file string, dryRun bool) result {
+	out := result{File: file}
+
+	var r io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			out.Error = err.Error()
+			return out
+		}
+		defer f.Close()
+		r = f
+	}
+
+	extracted, err := extractor.Extract(r)
+	if err != nil {
+		out.Error = err.Error()
+		return out
+	}
+	receipt := extracted.Receipt
+	out.Template = extracted.Template
+	out.Confidence = extracted.Confidence
+	out.Receipt = response.ParsedReceipt{
+		Retailer:     receipt.Retailer,
+		PurchaseDate: receipt.PurchaseDate,
+		PurchaseTime: receipt.PurchaseTime,
+		Items:        make([]response.ReceiptItem, len(receipt.Items)),
+		Total:        receipt.Total,
+	}
+	for i, item := range receipt.Items {
+		out.Receipt.Items[i] = response.ReceiptItem{ShortDescription: item.ShortDescription, Price: item.Price}
+	}
+
+	if err := api.ValidateReceipt(receipt); err != nil {
+		out.Error = "Could not extract a valid receipt"
+		var invalid *api.ValidationError
+		if errors.As(err, &invalid) {
+			for _, field := range invalid.Fields {
+				out.Fields = append(out.Fields, response.FieldError{Field: field.Field, Message: field.Message})
+			}
+		}
+		return out
+	}
+	if dryRun {
+		return out
+	}
+
+	id, err := service.ProcessReceipt(ctx, receipt)
+	var duplicate *internalHttp.DuplicateReceiptError
+	if errors.As(err, &duplicate) {
+		out.OriginalID = duplicate.OriginalID
+	}
+	if err != nil {
+		out.Error = fmt.Sprintf("failed to process receipt: %v", err)
+		return out
+	}
+	out.ID = id
+	return out
+}
@@ -0,0 +1,571 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// encodingVersion is written as the first byte of every stored receipt blob so the
+// on-disk format can evolve without breaking existing databases.
+const encodingVersion byte = 1
+
+// indexSeparator separates the indexed value from the receipt ID in index keys.
+const indexSeparator = "\x00"
+
+var (
+	receiptsBucket          = []byte("receipts")
+	retailerIndexBucket     = []byte("idx_retailer")
+	purchaseDateIndexBucket = []byte("idx_purchase_date")
+	tombstonesBucket        = []byte("tombstones")
+	revisionsBucket         = []byte("revisions")
+	fingerprintIndexBucket  = []byte("idx_fingerprint")
+)
+
+// ReceiptStoreImpl stores receipts in an embedded bbolt database file.
+//
+// bbolt transactions cannot be interrupted, so the context passed to each method is checked
+// before the transaction starts rather than during it.
+//
+// Receipts are kept in a single bucket keyed by ID, with secondary index buckets
+// for retailer, purchase date and fingerprint whose keys are "<value>\x00<id>". Replaced revisions are
+// kept in their own bucket keyed by "<id>\x00<zero-padded revision>" so they sort oldest first.
+type ReceiptStoreImpl struct {
+	db *bbolt.DB
+}
+
+// NewReceiptStore
+//
+// Parameters:
+//   - path: The file path of the bbolt database. It is created if it does not exist.
+//
+// Returns:
+//   - A new instance of ReceiptStoreImpl backed by the database at path.
+//   - err: An error if the database cannot be opened or initialized.
+func NewReceiptStore(path string) (*ReceiptStoreImpl, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open bolt database '%s': %v", path, err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{receiptsBucket, retailerIndexBucket, purchaseDateIndexBucket, tombstonesBucket, revisionsBucket, fingerprintIndexBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to initialize bolt buckets: %v", err)
+	}
+
+	return &ReceiptStoreImpl{db: db}, nil
+}
+
+// Save stores a receipt and its index entries in a single transaction and returns its ID
+func (r *ReceiptStoreImpl) Save(ctx context.Context, receipt domain.Receipt) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	receiptID := repository.AssignReceiptID(receipt)
+	receipt.ID = receiptID
+
+	blob, err := encodeReceipt(receipt)
+	if err != nil {
+		return "", err
+	}
+
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket(receiptsBucket).Put([]byte(receiptID), blob); err != nil {
+			return err
+		}
+		return indexReceipt(tx, receipt)
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to save receipt: %v", err)
+	}
+
+	return receiptID, nil
+}
+
+// SaveUnique stores a receipt and its index entries unless another receipt with its fingerprint is
+// already stored, looking it up in the same transaction as the insert
+func (r *ReceiptStoreImpl) SaveUnique(ctx context.Context, receipt domain.Receipt) (string, string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", "", err
+	}
+
+	receiptID := repository.AssignReceiptID(receipt)
+	receipt.ID = receiptID
+
+	blob, err := encodeReceipt(receipt)
+	if err != nil {
+		return "", "", err
+	}
+
+	var originalID string
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(receiptsBucket)
+		if data.Get([]byte(receiptID)) != nil {
+			return nil
+		}
+		matches, err := indexedReceipts(tx, fingerprintIndexBucket, receipt.Fingerprint)
+		if err != nil {
+			return err
+		}
+		if original, found := repository.EarliestReceipt(matches); found {
+			originalID = original.ID
+			return nil
+		}
+
+		if err := data.Put([]byte(receiptID), blob); err != nil {
+			return err
+		}
+		return indexReceipt(tx, receipt)
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("unable to save receipt: %v", err)
+	}
+	if originalID != "" {
+		return "", originalID, nil
+	}
+
+	return receiptID, "", nil
+}
+
+// Find retrieves a receipt by ID
+func (r *ReceiptStoreImpl) Find(ctx context.Context, id string) (domain.Receipt, error) {
+	var receipt domain.Receipt
+	if err := ctx.Err(); err != nil {
+		return receipt, err
+	}
+
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		blob := tx.Bucket(receiptsBucket).Get([]byte(id))
+		if blob == nil {
+			return missingReceiptError(tx, id)
+		}
+
+		var err error
+		receipt, err = decodeReceipt(blob)
+		return err
+	})
+
+	return receipt, err
+}
+
+// Update replaces a receipt with a new revision in a single transaction, moving the replaced
+// version into the revisions bucket and re-indexing the receipt
+func (r *ReceiptStoreImpl) Update(ctx context.Context, receipt domain.Receipt, expectedRevision int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	blob, err := encodeReceipt(receipt)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		currentBlob := tx.Bucket(receiptsBucket).Get([]byte(receipt.ID))
+		if currentBlob == nil {
+			return missingReceiptError(tx, receipt.ID)
+		}
+		current, err := decodeReceipt(currentBlob)
+		if err != nil {
+			return err
+		}
+		if current.Revision != expectedRevision {
+			return repository.ErrRevisionConflict
+		}
+
+		if err := tx.Bucket(revisionsBucket).Put(revisionKey(current.ID, current.Revision), currentBlob); err != nil {
+			return err
+		}
+		if err := unindexReceipt(tx, current); err != nil {
+			return err
+		}
+		if err := tx.Bucket(receiptsBucket).Put([]byte(receipt.ID), blob); err != nil {
+			return err
+		}
+		return indexReceipt(tx, receipt)
+	})
+}
+
+// Revisions returns the earlier revisions of a receipt, oldest first
+func (r *ReceiptStoreImpl) Revisions(ctx context.Context, id string) ([]domain.Receipt, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	revisions := []domain.Receipt{}
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket(receiptsBucket).Get([]byte(id)) == nil {
+			return missingReceiptError(tx, id)
+		}
+
+		prefix := []byte(id + indexSeparator)
+		c := tx.Bucket(revisionsBucket).Cursor()
+		for k, blob := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, blob = c.Next() {
+			revision, err := decodeReceipt(blob)
+			if err != nil {
+				return err
+			}
+			revisions = append(revisions, revision)
+		}
+		return nil
+	})
+
+	return revisions, err
+}
+
+// FindByRetailer returns all receipts whose retailer matches, ignoring case and surrounding whitespace
+func (r *ReceiptStoreImpl) FindByRetailer(ctx context.Context, retailer string) ([]domain.Receipt, error) {
+	return r.findByIndex(ctx, retailerIndexBucket, repository.NormalizeRetailer(retailer))
+}
+
+// FindByPurchaseDate returns all receipts with the given purchase date ("YYYY-MM-DD")
+func (r *ReceiptStoreImpl) FindByPurchaseDate(ctx context.Context, purchaseDate string) ([]domain.Receipt, error) {
+	return r.findByIndex(ctx, purchaseDateIndexBucket, purchaseDate)
+}
+
+// FindByFingerprint returns all receipts with the given canonical fingerprint
+func (r *ReceiptStoreImpl) FindByFingerprint(ctx context.Context, fingerprint string) ([]domain.Receipt, error) {
+	return r.findByIndex(ctx, fingerprintIndexBucket, fingerprint)
+}
+
+// Delete removes a receipt and its index entries and stores the tombstone in a single transaction
+func (r *ReceiptStoreImpl) Delete(ctx context.Context, id string, tombstone domain.Tombstone) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tombstone.ReceiptID = id
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		blob := tx.Bucket(receiptsBucket).Get([]byte(id))
+		if blob == nil {
+			return missingReceiptError(tx, id)
+		}
+		receipt, err := decodeReceipt(blob)
+		if err != nil {
+			return err
+		}
+		return removeReceipt(tx, receipt, tombstone)
+	})
+}
+
+// Erase deletes every receipt matching the selector in a single transaction,
+// using the retailer index when erasing by retailer and a full scan otherwise
+func (r *ReceiptStoreImpl) Erase(ctx context.Context, selector repository.ErasureSelector, audit domain.Tombstone) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var candidates []domain.Receipt
+	var err error
+	if selector.Retailer != "" {
+		candidates, err = r.findByIndex(ctx, retailerIndexBucket, repository.NormalizeRetailer(selector.Retailer))
+	} else {
+		candidates, err = r.findAll()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		for _, candidate := range candidates {
+			// Re-read inside the write transaction in case the receipt changed since the scan
+			blob := tx.Bucket(receiptsBucket).Get([]byte(candidate.ID))
+			if blob == nil {
+				continue
+			}
+			receipt, err := decodeReceipt(blob)
+			if err != nil {
+				return err
+			}
+			if !selector.Matches(receipt) {
+				continue
+			}
+
+			tombstone := audit
+			tombstone.ReceiptID = receipt.ID
+			if err := removeReceipt(tx, receipt, tombstone); err != nil {
+				return err
+			}
+			ids = append(ids, receipt.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to erase receipts: %v", err)
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Query returns a page of receipts matching the query.
+//
+// Candidates come from the retailer index when a retailer is given, a range scan of the
+// purchase date index when a date range is given, and a full scan otherwise.
+func (r *ReceiptStoreImpl) Query(ctx context.Context, query repository.ReceiptQuery) (repository.ReceiptPage, error) {
+	if err := ctx.Err(); err != nil {
+		return repository.ReceiptPage{}, err
+	}
+
+	var candidates []domain.Receipt
+	var err error
+	switch {
+	case query.Retailer != "":
+		candidates, err = r.findByIndex(ctx, retailerIndexBucket, repository.NormalizeRetailer(query.Retailer))
+	case query.PurchaseDateFrom != "" || query.PurchaseDateTo != "":
+		candidates, err = r.findByDateRange(query.PurchaseDateFrom, query.PurchaseDateTo)
+	default:
+		candidates, err = r.findAll()
+	}
+	if err != nil {
+		return repository.ReceiptPage{}, err
+	}
+
+	return query.Paginate(candidates)
+}
+
+// BackupToFile writes a consistent snapshot of the database to path while the store stays online.
+//
+// The snapshot is written to a temporary file next to path and renamed into place once it
+// has been synced, so a partially written backup is never left at path.
+func (r *ReceiptStoreImpl) BackupToFile(ctx context.Context, path string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return 0, fmt.Errorf("unable to create backup file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	var written int64
+	err = r.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		written, err = tx.WriteTo(tmp)
+		return err
+	})
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to write backup: %v", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("unable to move backup into place: %v", err)
+	}
+
+	return written, nil
+}
+
+// Close releases the underlying database file
+func (r *ReceiptStoreImpl) Close() error {
+	return r.db.Close()
+}
+
+// findByIndex scans an index bucket for keys prefixed by value and loads the referenced receipts
+func (r *ReceiptStoreImpl) findByIndex(ctx context.Context, bucket []byte, value string) ([]domain.Receipt, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var receipts []domain.Receipt
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		receipts, err = indexedReceipts(tx, bucket, value)
+		return err
+	})
+
+	return receipts, err
+}
+
+// indexedReceipts loads every receipt an index bucket lists under value
+func indexedReceipts(tx *bbolt.Tx, bucket []byte, value string) ([]domain.Receipt, error) {
+	receipts := []domain.Receipt{}
+	prefix := []byte(value + indexSeparator)
+
+	data := tx.Bucket(receiptsBucket)
+	c := tx.Bucket(bucket).Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		blob := data.Get(k[len(prefix):])
+		if blob == nil {
+			continue
+		}
+		receipt, err := decodeReceipt(blob)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, nil
+}
+
+// findByDateRange scans the purchase date index from "from" up to and including "to"; either bound may be empty
+func (r *ReceiptStoreImpl) findByDateRange(from, to string) ([]domain.Receipt, error) {
+	receipts := []domain.Receipt{}
+
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(receiptsBucket)
+		c := tx.Bucket(purchaseDateIndexBucket).Cursor()
+
+		k, _ := c.First()
+		if from != "" {
+			k, _ = c.Seek([]byte(from))
+		}
+		for ; k != nil; k, _ = c.Next() {
+			date, id, _ := bytes.Cut(k, []byte(indexSeparator))
+			if to != "" && string(date) > to {
+				break
+			}
+			blob := data.Get(id)
+			if blob == nil {
+				continue
+			}
+			receipt, err := decodeReceipt(blob)
+			if err != nil {
+				return err
+			}
+			receipts = append(receipts, receipt)
+		}
+		return nil
+	})
+
+	return receipts, err
+}
+
+// findAll loads every stored receipt
+func (r *ReceiptStoreImpl) findAll() ([]domain.Receipt, error) {
+	receipts := []domain.Receipt{}
+
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(receiptsBucket).ForEach(func(_, blob []byte) error {
+			receipt, err := decodeReceipt(blob)
+			if err != nil {
+				return err
+			}
+			receipts = append(receipts, receipt)
+			return nil
+		})
+	})
+
+	return receipts, err
+}
+
+// removeReceipt deletes a receipt, its index entries and its earlier revisions and writes its tombstone within tx
+func removeReceipt(tx *bbolt.Tx, receipt domain.Receipt, tombstone domain.Tombstone) error {
+	blob, err := json.Marshal(tombstone)
+	if err != nil {
+		return fmt.Errorf("unable to encode tombstone: %v", err)
+	}
+	if err := tx.Bucket(receiptsBucket).Delete([]byte(receipt.ID)); err != nil {
+		return err
+	}
+	if err := unindexReceipt(tx, receipt); err != nil {
+		return err
+	}
+
+	// Collect the keys first, since deleting while iterating moves a bbolt cursor
+	prefix := []byte(receipt.ID + indexSeparator)
+	revisions := tx.Bucket(revisionsBucket)
+	var keys [][]byte
+	c := revisions.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		keys = append(keys, append([]byte{}, k...))
+	}
+	for _, k := range keys {
+		if err := revisions.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return tx.Bucket(tombstonesBucket).Put([]byte(receipt.ID), blob)
+}
+
+// indexReceipt adds a receipt's retailer, purchase date and fingerprint index entries within tx
+func indexReceipt(tx *bbolt.Tx, receipt domain.Receipt) error {
+	if err := tx.Bucket(retailerIndexBucket).Put(indexKey(repository.NormalizeRetailer(receipt.Retailer), receipt.ID), nil); err != nil {
+		return err
+	}
+	if receipt.Fingerprint != "" {
+		if err := tx.Bucket(fingerprintIndexBucket).Put(indexKey(receipt.Fingerprint, receipt.ID), nil); err != nil {
+			return err
+		}
+	}
+	return tx.Bucket(purchaseDateIndexBucket).Put(indexKey(receipt.PurchaseDate, receipt.ID), nil)
+}
+
+// unindexReceipt removes a receipt's retailer, purchase date and fingerprint index entries within tx
+func unindexReceipt(tx *bbolt.Tx, receipt domain.Receipt) error {
+	if err := tx.Bucket(retailerIndexBucket).Delete(indexKey(repository.NormalizeRetailer(receipt.Retailer), receipt.ID)); err != nil {
+		return err
+	}
+	if receipt.Fingerprint != "" {
+		if err := tx.Bucket(fingerprintIndexBucket).Delete(indexKey(receipt.Fingerprint, receipt.ID)); err != nil {
+			return err
+		}
+	}
+	return tx.Bucket(purchaseDateIndexBucket).Delete(indexKey(receipt.PurchaseDate, receipt.ID))
+}
+
+// missingReceiptError distinguishes a deleted receipt from one that never existed
+func missingReceiptError(tx *bbolt.Tx, id string) error {
+	if tx.Bucket(tombstonesBucket).Get([]byte(id)) != nil {
+		return repository.ErrReceiptDeleted
+	}
+	return repository.ErrReceiptNotFound
+}
+
+// encodeReceipt serializes a receipt as a version byte followed by its JSON encoding
+func encodeReceipt(receipt domain.Receipt) ([]byte, error) {
+	body, err := json.Marshal(receipt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode receipt: %v", err)
+	}
+	return append([]byte{encodingVersion}, body...), nil
+}
+
+// decodeReceipt reverses encodeReceipt, rejecting blobs written with an unknown version
+func decodeReceipt(blob []byte) (domain.Receipt, error) {
+	var receipt domain.Receipt
+	if len(blob) == 0 {
+		return receipt, fmt.Errorf("unable to decode receipt: empty record")
+	}
+	if blob[0] != encodingVersion {
+		return receipt, fmt.Errorf("unable to decode receipt: unsupported encoding version %d", blob[0])
+	}
+	if err := json.Unmarshal(blob[1:], &receipt); err != nil {
+		return receipt, fmt.Errorf("unable to decode receipt: %v", err)
+	}
+	return receipt, nil
+}
+
+func indexKey(value, receiptID string) []byte {
+	return []byte(value + indexSeparator + receiptID)
+}
+
+func revisionKey(receiptID string, revision int) []byte {
+	return []byte(fmt.Sprintf("%s%s%010d", receiptID, indexSeparator, revision))
+}
@@ -0,0 +1,217 @@
+// Package email extracts receipts from e-receipts delivered by email. A message is matched against
+// per-retailer templates by its sender and subject; messages no template reads are parsed like the
+// text of a printed receipt.
+package email
+
+import (
+	"go-receipt-processor/internal/adapters/plaintext"
+	"go-receipt-processor/internal/domain"
+	"io"
+	"mime"
+	"net/mail"
+	"strings"
+)
+
+// GenericTemplate is reported when no retailer template matched and the receipt was read by plaintext.Parse.
+const GenericTemplate = "generic"
+
+// Confidence in the fields of a generic extraction taken from the message's headers rather than its body.
+const (
+	senderConfidence     = 0.8 // The retailer's name is the sender's display name
+	sentDateConfidence   = 0.6 // The purchase date and time are when the email was sent
+	headerDateTimeLayout = "2006-01-02 15:04"
+)
+
+// Result is a receipt extracted from an email.
+type Result struct {
+	Receipt    domain.Receipt
+	Template   string             // Name of the template that matched, or GenericTemplate
+	Part       string             // Media type of the body the receipt was read from, MIMEHTML or MIMEPlain
+	Confidence map[string]float64 // How sure plaintext.Parse is of each field; only set for GenericTemplate
+}
+
+// Extractor reads receipts out of emails with a list of templates, trying them in order.
+type Extractor struct {
+	templates []*compiledTemplate
+}
+
+// NewExtractor
+//
+// Parameters:
+//   - templates: The templates to try, in order, e.g. those of LoadTemplates followed by DefaultTemplates.
+//
+// Returns:
+//   - A new instance of Extractor.
+//   - err: An error if a template is incomplete or one of its patterns does not compile.
+func NewExtractor(templates []Template) (*Extractor, error) {
+	e := &Extractor{}
+	for _, template := range templates {
+		compiled, err := template.compile()
+		if err != nil {
+			return nil, err
+		}
+		e.templates = append(e.templates, compiled)
+	}
+	return e, nil
+}
+
+// Extract
+//
+// HTML bodies are read before plain text ones. A template matches when it applies to the sender and
+// subject of the message, or of a message attached to it, and finds at least one item and the total.
+//
+// Parameters:
+//   - r: A raw RFC 5322 message, e.g. the content of an .eml file.
+//
+// Returns:
+//   - The extracted receipt and how it was found. The receipt is not validated.
+//   - err: ErrInvalidMessage if the message cannot be read, ErrNoBody if it has no text or HTML body,
+//     or ErrPartTooLarge if one of them is longer than MaxPartBytes.
+func (e *Extractor) Extract(r io.Reader) (Result, error) {
+	msg, err := readMessage(r)
+	if err != nil {
+		return Result{}, err
+	}
+
+	parts := make([]part, 0, len(msg.parts))
+	for _, mediaType := range []string{MIMEHTML, MIMEPlain} {
+		for _, p := range msg.parts {
+			if p.mediaType == mediaType {
+				parts = append(parts, p)
+			}
+		}
+	}
+
+	for _, template := range e.templates {
+		for _, header := range msg.headers {
+			if !template.appliesTo(sender(header).Address, subject(header)) {
+				continue
+			}
+			for _, p := range parts {
+				if receipt, ok := template.extract(p.text, header); ok {
+					return Result{Receipt: receipt, Template: template.Name, Part: p.mediaType}, nil
+				}
+			}
+		}
+	}
+	return genericResult(msg, parts), nil
+}
+
+// extract reads a receipt from the text of a body, taking the date and time from the header if the body lacks them
+func (t *compiledTemplate) extract(text string, header mail.Header) (domain.Receipt, bool) {
+	lines := strings.Split(text, "\n")
+	receipt := domain.Receipt{Retailer: t.Retailer}
+
+	items := lines
+	if t.itemsStart != nil {
+		items = nil
+		for i, line := range lines {
+			if t.itemsStart.MatchString(line) {
+				items = lines[i+1:]
+				break
+			}
+		}
+	}
+	if t.itemsEnd != nil {
+		for i, line := range items {
+			if t.itemsEnd.MatchString(line) {
+				items = items[:i]
+				break
+			}
+		}
+	}
+	for _, line := range items {
+		m := t.item.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		price, ok := plaintext.ParseAmount(m[t.item.SubexpIndex("price")])
+		description := plaintext.CleanDescription(m[t.item.SubexpIndex("description")])
+		if ok && description != "" {
+			receipt.Items = append(receipt.Items, domain.Item{ShortDescription: description, Price: price})
+		}
+	}
+
+	if total, ok := capture(t.total, lines, "total"); ok {
+		receipt.Total, _ = plaintext.ParseAmount(total)
+	}
+	if len(receipt.Items) == 0 || receipt.Total == "" {
+		return domain.Receipt{}, false
+	}
+
+	if date, ok := capture(t.date, lines, "date"); ok {
+		receipt.PurchaseDate, _ = plaintext.ParseDate(date)
+	}
+	if clock, ok := capture(t.clock, lines, "time"); ok {
+		receipt.PurchaseTime, _ = plaintext.ParseTime(clock)
+	}
+	// The time the email was sent only stands in for the purchase time if it was sent on the day of purchase
+	sentDate, sentTime := sentAt(header)
+	if receipt.PurchaseDate == "" {
+		receipt.PurchaseDate = sentDate
+	}
+	if receipt.PurchaseTime == "" && receipt.PurchaseDate == sentDate {
+		receipt.PurchaseTime = sentTime
+	}
+	return receipt, true
+}
+
+// genericResult reads the receipt from the first plain text body, or the first HTML one, like printed text.
+// The innermost message is taken to be the one the retailer sent, so its sender names the retailer and its
+// Date header stands in for a purchase date and time the body lacks.
+func genericResult(msg message, parts []part) Result {
+	body := parts[0]
+	for _, p := range parts {
+		if p.mediaType == MIMEPlain {
+			body = p
+			break
+		}
+	}
+
+	parsed := plaintext.Parse(body.text)
+	result := Result{Receipt: parsed.Receipt, Template: GenericTemplate, Part: body.mediaType, Confidence: parsed.Confidence}
+
+	header := msg.headers[len(msg.headers)-1]
+	if name := plaintext.CleanRetailer(sender(header).Name); name != "" {
+		result.Receipt.Retailer = name
+		result.Confidence[plaintext.FieldRetailer] = senderConfidence
+	}
+	sentDate, sentTime := sentAt(header)
+	if result.Receipt.PurchaseDate == "" && sentDate != "" {
+		result.Receipt.PurchaseDate = sentDate
+		result.Confidence[plaintext.FieldPurchaseDate] = sentDateConfidence
+	}
+	if result.Receipt.PurchaseTime == "" && sentTime != "" && result.Receipt.PurchaseDate == sentDate {
+		result.Receipt.PurchaseTime = sentTime
+		result.Confidence[plaintext.FieldPurchaseTime] = sentDateConfidence
+	}
+	return result
+}
+
+// sender returns the From address of a header, or an empty address if it has none
+func sender(header mail.Header) *mail.Address {
+	address, err := mail.ParseAddress(header.Get("From"))
+	if err != nil {
+		return &mail.Address{}
+	}
+	return address
+}
+
+// subject returns the decoded Subject of a header
+func subject(header mail.Header) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+	if err != nil {
+		return header.Get("Subject")
+	}
+	return decoded
+}
+
+// sentAt returns the date and time of a header's Date in the sender's time zone, or empty strings if it has none
+func sentAt(header mail.Header) (string, string) {
+	sent, err := header.Date()
+	if err != nil {
+		return "", ""
+	}
+	date, clock, _ := strings.Cut(sent.Format(headerDateTimeLayout), " ")
+	return date, clock
+}
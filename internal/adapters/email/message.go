@@ -0,0 +1,222 @@
+package email
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// Media types of the body parts receipts are extracted from.
+const (
+	MIMEHTML  = "text/html"
+	MIMEPlain = "text/plain"
+)
+
+// ErrInvalidMessage is returned for input that is not an RFC 5322 message or whose MIME structure cannot be read.
+var ErrInvalidMessage = errors.New("invalid email message")
+
+// ErrNoBody is returned for messages without a text or HTML body.
+var ErrNoBody = errors.New("email message has no text or HTML body")
+
+// MaxPartBytes bounds each decoded text or HTML part, as the HTTP API bounds a receipt sent as text.
+const MaxPartBytes = 1 << 20
+
+// ErrPartTooLarge is returned for messages with a text or HTML part longer than MaxPartBytes once decoded.
+var ErrPartTooLarge = fmt.Errorf("email body part exceeds %d bytes", MaxPartBytes)
+
+// part is a decoded body of a message
+type part struct {
+	mediaType string
+	text      string // The body as text: HTML is reduced to one line per block and a tab between table cells
+}
+
+// message is the content of an email that receipts are extracted from
+type message struct {
+	headers []mail.Header // The message's header, then those of the messages attached to it, e.g. when forwarded
+	parts   []part        // In the order they appear in the message
+}
+
+// readMessage parses a raw RFC 5322 message and decodes every text and HTML part that is not an attachment
+func readMessage(r io.Reader) (message, error) {
+	var m message
+	err := m.read(r)
+	switch {
+	case errors.Is(err, ErrPartTooLarge):
+		return message{}, err
+	case err != nil:
+		return message{}, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	if len(m.parts) == 0 {
+		return message{}, ErrNoBody
+	}
+	return m, nil
+}
+
+// read parses a message and walks its body
+func (m *message) read(r io.Reader) error {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return err
+	}
+	m.headers = append(m.headers, msg.Header)
+	return m.walk(msg.Header, decodeTransfer(msg.Header.Get("Content-Transfer-Encoding"), msg.Body))
+}
+
+// walk decodes a part whose transfer encoding has been removed, descending into multipart bodies and attached messages
+func (m *message) walk(header map[string][]string, body io.Reader) error {
+	get := func(key string) string {
+		if values := header[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	mediaType, params, err := mime.ParseMediaType(get("Content-Type"))
+	if err != nil {
+		// RFC 2045: a part without a valid Content-Type is plain US-ASCII text
+		mediaType, params = MIMEPlain, nil
+	}
+	disposition, _, _ := mime.ParseMediaType(get("Content-Disposition"))
+
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			p, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := m.walk(p.Header, decodeTransfer(p.Header.Get("Content-Transfer-Encoding"), p)); err != nil {
+				return err
+			}
+		}
+	case mediaType == "message/rfc822":
+		// Forwarded receipts are often attached as the original message
+		return m.read(body)
+	case disposition == "attachment":
+		return nil
+	case mediaType == MIMEHTML || mediaType == MIMEPlain:
+		label := params["charset"]
+		if label == "" {
+			label = "utf-8"
+		}
+		decoded, err := charset.NewReaderLabel(label, body)
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(io.LimitReader(decoded, MaxPartBytes+1))
+		if err != nil {
+			return err
+		}
+		if len(data) > MaxPartBytes {
+			return ErrPartTooLarge
+		}
+		text := string(data)
+		if mediaType == MIMEHTML {
+			text = htmlText(text)
+		}
+		m.parts = append(m.parts, part{mediaType: mediaType, text: text})
+	}
+	return nil
+}
+
+// decodeTransfer removes a quoted-printable or base64 Content-Transfer-Encoding
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &newlineSkipper{r: body})
+	default:
+		return body
+	}
+}
+
+// newlineSkipper drops the line breaks base64 bodies are wrapped with
+type newlineSkipper struct {
+	r io.Reader
+}
+
+func (s *newlineSkipper) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	kept := 0
+	for _, b := range p[:n] {
+		if b != '\r' && b != '\n' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
+
+// blockElements end a line of the text an HTML body is reduced to
+var blockElements = map[string]bool{
+	"br": true, "p": true, "div": true, "tr": true, "table": true, "li": true, "ul": true, "ol": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "hr": true, "section": true,
+}
+
+// htmlText reduces an HTML body to the text a reader sees: one line per block element, a tab between
+// table cells, and no scripts, styles or markup
+func htmlText(body string) string {
+	var text strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(body))
+	skip := 0
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return tidyLines(text.String())
+		case html.TextToken:
+			if skip == 0 {
+				// Line breaks in the markup are not line breaks on screen
+				text.WriteString(" " + strings.Join(strings.Fields(string(tokenizer.Text())), " ") + " ")
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			switch tag := string(name); {
+			case tag == "script" || tag == "style" || tag == "head":
+				skip++
+			case tag == "td" || tag == "th":
+				text.WriteString("\t")
+			case blockElements[tag]:
+				text.WriteString("\n")
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch tag := string(name); {
+			case tag == "script" || tag == "style" || tag == "head":
+				skip = max(skip-1, 0)
+			case blockElements[tag]:
+				text.WriteString("\n")
+			}
+		}
+	}
+}
+
+// tidyLines collapses the whitespace of every line and table cell, and drops empty cells and lines
+func tidyLines(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		var cells []string
+		for _, cell := range strings.Split(line, "\t") {
+			if cell = strings.Join(strings.Fields(cell), " "); cell != "" {
+				cells = append(cells, cell)
+			}
+		}
+		if len(cells) > 0 {
+			lines = append(lines, strings.Join(cells, "\t"))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
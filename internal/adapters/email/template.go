@@ -0,0 +1,137 @@
+package email
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+//go:embed templates.json
+var defaultTemplates []byte
+
+// Template describes how the e-receipts of one retailer are laid out. Patterns are regular expressions
+// matched against the body of the email as text, one line at a time; HTML bodies are reduced to one line
+// per block element with a tab between table cells.
+type Template struct {
+	Name       string   `json:"name"`                 // Reported as the template that matched
+	Retailer   string   `json:"retailer"`             // Stored as the receipt's retailer
+	From       []string `json:"from"`                 // Sender domains the template applies to, e.g. "target.com", which also covers its subdomains
+	Subject    string   `json:"subject,omitempty"`    // If set, the subject must match it
+	ItemsStart string   `json:"itemsStart,omitempty"` // If set, items are only read after the first line matching it
+	ItemsEnd   string   `json:"itemsEnd,omitempty"`   // If set, items are only read before the first line after ItemsStart matching it
+	Item       string   `json:"item"`                 // Matches an item line, capturing "description" and "price"
+	Total      string   `json:"total"`                // Matches the total line, capturing "total"
+	Date       string   `json:"date,omitempty"`       // Matches the purchase date, capturing "date"; the Date header is used if unset or not found
+	Time       string   `json:"time,omitempty"`       // Matches the purchase time, capturing "time"; otherwise taken from the Date header if sent that day
+}
+
+// compiledTemplate is a Template with its patterns compiled
+type compiledTemplate struct {
+	Template
+	subject, itemsStart, itemsEnd, item, total, date, clock *regexp.Regexp
+}
+
+// DefaultTemplates
+//
+// Returns:
+//   - The templates built into the service.
+//   - err: An error if the built-in templates cannot be read.
+func DefaultTemplates() ([]Template, error) {
+	var templates []Template
+	if err := json.Unmarshal(defaultTemplates, &templates); err != nil {
+		return nil, fmt.Errorf("failed to read built-in email templates: %w", err)
+	}
+	return templates, nil
+}
+
+// LoadTemplates
+//
+// Parameters:
+//   - path: A JSON file holding an array of templates.
+//
+// Returns:
+//   - The templates in the file.
+//   - err: An error if the file cannot be read or is not an array of templates.
+func LoadTemplates(path string) ([]Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email templates: %w", err)
+	}
+	var templates []Template
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("failed to read email templates from %s: %w", path, err)
+	}
+	return templates, nil
+}
+
+// compile checks a template and compiles its patterns
+func (t Template) compile() (*compiledTemplate, error) {
+	if t.Name == "" || t.Retailer == "" || len(t.From) == 0 || t.Item == "" || t.Total == "" {
+		return nil, fmt.Errorf("email template %q must set name, retailer, from, item and total", t.Name)
+	}
+
+	compiled := &compiledTemplate{Template: t}
+	patterns := []struct {
+		source string
+		target **regexp.Regexp
+		groups []string
+	}{
+		{t.Subject, &compiled.subject, nil},
+		{t.ItemsStart, &compiled.itemsStart, nil},
+		{t.ItemsEnd, &compiled.itemsEnd, nil},
+		{t.Item, &compiled.item, []string{"description", "price"}},
+		{t.Total, &compiled.total, []string{"total"}},
+		{t.Date, &compiled.date, []string{"date"}},
+		{t.Time, &compiled.clock, []string{"time"}},
+	}
+	for _, pattern := range patterns {
+		if pattern.source == "" {
+			continue
+		}
+		re, err := regexp.Compile(pattern.source)
+		if err != nil {
+			return nil, fmt.Errorf("email template %q: %w", t.Name, err)
+		}
+		for _, group := range pattern.groups {
+			if re.SubexpIndex(group) < 0 {
+				return nil, fmt.Errorf("email template %q: pattern %q must capture %q", t.Name, pattern.source, group)
+			}
+		}
+		*pattern.target = re
+	}
+	return compiled, nil
+}
+
+// appliesTo reports whether the template is meant for an email from the given address with the given subject
+func (t *compiledTemplate) appliesTo(address, subject string) bool {
+	_, domain, ok := strings.Cut(strings.ToLower(address), "@")
+	if !ok {
+		return false
+	}
+	if t.subject != nil && !t.subject.MatchString(subject) {
+		return false
+	}
+	for _, from := range t.From {
+		from = strings.ToLower(from)
+		if domain == from || strings.HasSuffix(domain, "."+from) {
+			return true
+		}
+	}
+	return false
+}
+
+// capture returns the named group of the first line matching re
+func capture(re *regexp.Regexp, lines []string, group string) (string, bool) {
+	if re == nil {
+		return "", false
+	}
+	for _, line := range lines {
+		if m := re.FindStringSubmatch(line); m != nil {
+			return strings.TrimSpace(m[re.SubexpIndex(group)]), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,220 @@
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-receipt-processor/internal/domain"
+	"io"
+	"strconv"
+)
+
+// Columns of the CSV layout. The first row names the columns, in any order; every other row is one item.
+// Rows with the same "receipt" key belong to the same receipt and must be consecutive, and repeat its
+// receipt-level columns. "customerId" may be left out.
+//
+//	receipt,retailer,purchaseDate,purchaseTime,total,customerId,shortDescription,price
+//	1,Target,2022-01-01,13:01,35.35,,Mountain Dew 12PK,6.49
+//	1,Target,2022-01-01,13:01,35.35,,Emils Cheese Pizza,12.25
+const (
+	CSVColumnReceipt          = "receipt"
+	CSVColumnRetailer         = "retailer"
+	CSVColumnPurchaseDate     = "purchaseDate"
+	CSVColumnPurchaseTime     = "purchaseTime"
+	CSVColumnTotal            = "total"
+	CSVColumnCustomerID       = "customerId"
+	CSVColumnShortDescription = "shortDescription"
+	CSVColumnPrice            = "price"
+)
+
+// csvColumns lists every column in the order MarshalReceiptsCSV writes them
+var csvColumns = []string{CSVColumnReceipt, CSVColumnRetailer, CSVColumnPurchaseDate, CSVColumnPurchaseTime,
+	CSVColumnTotal, CSVColumnCustomerID, CSVColumnShortDescription, CSVColumnPrice}
+
+// csvReceiptColumns are repeated on every row of a receipt and must agree
+var csvReceiptColumns = []string{CSVColumnRetailer, CSVColumnPurchaseDate, CSVColumnPurchaseTime, CSVColumnTotal, CSVColumnCustomerID}
+
+// MarshalReceiptsCSV
+//
+// Parameters:
+//   - receipts: The receipts to encode. Each is keyed by its ID, or by its position if it has none.
+//
+// Returns:
+//   - The receipts in the CSV layout, one row per item.
+//   - err: An error if the receipts cannot be written.
+func MarshalReceiptsCSV(receipts []domain.Receipt) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvColumns); err != nil {
+		return nil, err
+	}
+
+	for i, receipt := range receipts {
+		key := receipt.ID
+		if key == "" {
+			key = strconv.Itoa(i + 1)
+		}
+		for _, item := range receipt.Items {
+			row := []string{key, receipt.Retailer, receipt.PurchaseDate, receipt.PurchaseTime, receipt.Total,
+				receipt.CustomerID, item.ShortDescription, item.Price}
+			if err := w.Write(row); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// UnmarshalReceiptsCSV
+//
+// Parameters:
+//   - data: Receipts in the CSV layout.
+//
+// Returns:
+//   - The receipts, in the order they appear. They are not validated.
+//   - err: An error if data does not follow the layout.
+func UnmarshalReceiptsCSV(data []byte) ([]domain.Receipt, error) {
+	documents, err := csvDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := make([]domain.Receipt, len(documents))
+	for i, document := range documents {
+		encoded, err := json.Marshal(document)
+		if err != nil {
+			return nil, err
+		}
+		if receipts[i], err = jsonReceipt(encoded); err != nil {
+			return nil, err
+		}
+	}
+	return receipts, nil
+}
+
+// csvDocument decodes a CSV body holding exactly one receipt into its JSON-shaped document
+func csvDocument(data []byte) (map[string]any, error) {
+	documents, err := csvDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(documents) != 1 {
+		return nil, fmt.Errorf("csv holds %d receipts; submit one receipt per request", len(documents))
+	}
+	return documents[0], nil
+}
+
+// csvDocuments decodes every receipt of a CSV body into its JSON-shaped document
+func csvDocuments(data []byte) ([]map[string]any, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns, err := csvColumnIndexes(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []map[string]any
+	var rows [][]string // Rows of the receipt being read
+	seen := make(map[string]bool)
+	flush := func() {
+		if len(rows) > 0 {
+			documents = append(documents, csvReceiptDocument(columns, rows))
+			rows = nil
+		}
+	}
+
+	for line := 2; ; line++ {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		key := row[columns[CSVColumnReceipt]]
+		if len(rows) > 0 && rows[0][columns[CSVColumnReceipt]] != key {
+			flush()
+		}
+		if len(rows) == 0 {
+			if seen[key] {
+				return nil, fmt.Errorf("row %d: rows of receipt '%s' must be consecutive", line, key)
+			}
+			seen[key] = true
+		}
+		for _, column := range csvReceiptColumns {
+			index, ok := columns[column]
+			if ok && len(rows) > 0 && row[index] != rows[0][index] {
+				return nil, fmt.Errorf("row %d: %s differs from the first row of receipt '%s'", line, column, key)
+			}
+		}
+		rows = append(rows, row)
+	}
+	flush()
+
+	if len(documents) == 0 {
+		return nil, errors.New("csv holds no receipts")
+	}
+	return documents, nil
+}
+
+// csvColumnIndexes maps each column named in header to its index, rejecting unknown and missing columns
+func csvColumnIndexes(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		known := false
+		for _, column := range csvColumns {
+			known = known || name == column
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown csv column '%s'", name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("csv column '%s' appears twice", name)
+		}
+		columns[name] = i
+	}
+
+	for _, column := range csvColumns {
+		if _, ok := columns[column]; !ok && column != CSVColumnCustomerID {
+			return nil, fmt.Errorf("csv column '%s' is missing", column)
+		}
+	}
+	return columns, nil
+}
+
+// csvReceiptDocument builds the document of the receipt whose item rows are rows
+func csvReceiptDocument(columns map[string]int, rows [][]string) map[string]any {
+	first := rows[0]
+	document := map[string]any{
+		"retailer":     first[columns[CSVColumnRetailer]],
+		"purchaseDate": first[columns[CSVColumnPurchaseDate]],
+		"purchaseTime": first[columns[CSVColumnPurchaseTime]],
+		"total":        first[columns[CSVColumnTotal]],
+	}
+	if index, ok := columns[CSVColumnCustomerID]; ok && first[index] != "" {
+		document["customerId"] = first[index]
+	}
+
+	items := make([]any, len(rows))
+	for i, row := range rows {
+		items[i] = map[string]any{
+			"shortDescription": row[columns[CSVColumnShortDescription]],
+			"price":            row[columns[CSVColumnPrice]],
+		}
+	}
+	document["items"] = items
+	return document
+}
@@ -0,0 +1,119 @@
+// Package format converts receipts between domain.Receipt and the wire formats the HTTP API accepts
+// besides JSON. Every format decodes into the same JSON-shaped document, so a receipt is validated
+// against the OpenAPI Receipt schema the same way whatever format it was sent in. The binary formats,
+// protobuf and MessagePack, also decode straight into a domain.Receipt, so accepting a receipt never
+// goes through JSON.
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-receipt-processor/internal/domain"
+)
+
+// Media types understood by DecodeReceipt.
+const (
+	MIMEJSON     = "application/json"
+	MIMEXML      = "application/xml"
+	MIMETextXML  = "text/xml"
+	MIMECSV      = "text/csv"
+	MIMEProtobuf = "application/x-protobuf" // A receiptpb.Receipt message
+	MIMEMsgpack  = "application/msgpack"    // A map with the field names of the JSON encoding
+)
+
+// documentDecoders turn a request body into the JSON-shaped document of the receipt it holds
+var documentDecoders = map[string]func(data []byte) (map[string]any, error){
+	MIMEXML:      xmlDocument,
+	MIMETextXML:  xmlDocument,
+	MIMECSV:      csvDocument,
+	MIMEProtobuf: protobufDocument,
+	MIMEMsgpack:  msgpackDocument,
+}
+
+// receiptDecoders decode the formats that map directly onto a receipt without building its document first
+var receiptDecoders = map[string]func(data []byte) (domain.Receipt, error){
+	MIMEProtobuf: protobufReceipt,
+	MIMEMsgpack:  msgpackReceipt,
+}
+
+// ReceiptMediaTypes lists the media types other than JSON that DecodeReceipt understands.
+func ReceiptMediaTypes() []string {
+	return []string{MIMEXML, MIMETextXML, MIMECSV, MIMEProtobuf, MIMEMsgpack}
+}
+
+// submission holds the fields of a receipt a client may submit, under their JSON names. Receipts are decoded
+// through it so that server-owned fields, such as points or revision metadata, cannot be set by a request body.
+type submission struct {
+	Retailer     string        `json:"retailer"`
+	PurchaseDate string        `json:"purchaseDate"`
+	PurchaseTime string        `json:"purchaseTime"`
+	Items        []domain.Item `json:"items"`
+	Total        string        `json:"total"`
+	CustomerID   string        `json:"customerId,omitempty"`
+}
+
+// receipt returns the submitted receipt, with every server-owned field left zero
+func (s submission) receipt() domain.Receipt {
+	return domain.Receipt{
+		Retailer:     s.Retailer,
+		PurchaseDate: s.PurchaseDate,
+		PurchaseTime: s.PurchaseTime,
+		Items:        s.Items,
+		Total:        s.Total,
+		CustomerID:   s.CustomerID,
+	}
+}
+
+// jsonReceipt decodes a JSON receipt, ignoring any server-owned fields it sets
+func jsonReceipt(data []byte) (domain.Receipt, error) {
+	var submitted submission
+	err := json.Unmarshal(data, &submitted)
+	return submitted.receipt(), err
+}
+
+// DecodeReceiptDocument
+//
+// Parameters:
+//   - mediaType: The media type of data, without parameters.
+//   - data: A receipt encoded in one of ReceiptMediaTypes.
+//
+// Returns:
+//   - The receipt as the document its JSON encoding would decode into, holding only the fields present in data.
+//   - err: An error if the media type is not supported or data cannot be decoded.
+func DecodeReceiptDocument(mediaType string, data []byte) (map[string]any, error) {
+	decode, ok := documentDecoders[mediaType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported media type '%s'", mediaType)
+	}
+	return decode(data)
+}
+
+// DecodeReceipt
+//
+// Bodies of any media type other than ReceiptMediaTypes are decoded as JSON, as ShouldBindJSON would.
+//
+// Parameters:
+//   - mediaType: The media type of data, without parameters.
+//   - data: The encoded receipt.
+//
+// Returns:
+//   - The decoded receipt. It is not validated, and only the fields a client submits are set.
+//   - err: An error if data cannot be decoded.
+func DecodeReceipt(mediaType string, data []byte) (domain.Receipt, error) {
+	if decode, ok := receiptDecoders[mediaType]; ok {
+		return decode(data)
+	}
+	if _, ok := documentDecoders[mediaType]; !ok {
+		return jsonReceipt(data)
+	}
+
+	document, err := DecodeReceiptDocument(mediaType, data)
+	if err != nil {
+		return domain.Receipt{}, err
+	}
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return domain.Receipt{}, err
+	}
+	return jsonReceipt(encoded)
+}
@@ -0,0 +1,98 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"go-receipt-processor/internal/domain"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MarshalMsgpack
+//
+// Values are encoded using their json struct tags, so a MessagePack body has the same field names as its
+// JSON equivalent.
+//
+// Parameters:
+//   - v: The value to encode, e.g. a domain.Receipt or a response DTO.
+//
+// Returns:
+//   - The value as MessagePack.
+//   - err: An error if the value cannot be encoded.
+func MarshalMsgpack(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// msgpackReceipt decodes a MessagePack map straight into a receipt, reading fields by their json names
+// and ignoring any server-owned fields it sets
+func msgpackReceipt(data []byte) (domain.Receipt, error) {
+	var submitted submission
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	err := dec.Decode(&submitted)
+	return submitted.receipt(), err
+}
+
+// msgpackDocument decodes a MessagePack map into its JSON-shaped document
+func msgpackDocument(data []byte) (map[string]any, error) {
+	var value any
+	if err := msgpack.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	document, ok := jsonValue(value).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("receipt must be a map, got %T", value)
+	}
+	return document, nil
+}
+
+// jsonValue converts a decoded MessagePack value into the types encoding/json decodes into,
+// so the schema sees a number or a byte string the way it would see them in JSON
+func jsonValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, field := range value {
+			value[key] = jsonValue(field)
+		}
+		return value
+	case map[any]any:
+		document := make(map[string]any, len(value))
+		for key, field := range value {
+			document[fmt.Sprint(key)] = jsonValue(field)
+		}
+		return document
+	case []any:
+		for i, element := range value {
+			value[i] = jsonValue(element)
+		}
+		return value
+	case []byte:
+		return string(value)
+	case int8:
+		return float64(value)
+	case int16:
+		return float64(value)
+	case int32:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case uint8:
+		return float64(value)
+	case uint16:
+		return float64(value)
+	case uint32:
+		return float64(value)
+	case uint64:
+		return float64(value)
+	case float32:
+		return float64(value)
+	default:
+		return value
+	}
+}
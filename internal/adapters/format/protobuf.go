@@ -0,0 +1,86 @@
+package format
+
+import (
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/grpc/receiptpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// MarshalReceiptProtobuf
+//
+// Parameters:
+//   - receipt: The receipt to encode; only the fields a client submits are included.
+//
+// Returns:
+//   - The receipt as a receiptpb.Receipt message, the body DecodeReceipt reads back.
+//   - err: An error if the receipt cannot be encoded.
+func MarshalReceiptProtobuf(receipt domain.Receipt) ([]byte, error) {
+	message := &receiptpb.Receipt{
+		Retailer:     receipt.Retailer,
+		PurchaseDate: receipt.PurchaseDate,
+		PurchaseTime: receipt.PurchaseTime,
+		Items:        make([]*receiptpb.Item, len(receipt.Items)),
+		Total:        receipt.Total,
+		CustomerId:   receipt.CustomerID,
+	}
+	for i, item := range receipt.Items {
+		message.Items[i] = &receiptpb.Item{ShortDescription: item.ShortDescription, Price: item.Price}
+	}
+	return proto.Marshal(message)
+}
+
+// protobufReceipt decodes a receiptpb.Receipt message straight into a receipt
+func protobufReceipt(data []byte) (domain.Receipt, error) {
+	var message receiptpb.Receipt
+	if err := proto.Unmarshal(data, &message); err != nil {
+		return domain.Receipt{}, err
+	}
+
+	receipt := domain.Receipt{
+		Retailer:     message.GetRetailer(),
+		PurchaseDate: message.GetPurchaseDate(),
+		PurchaseTime: message.GetPurchaseTime(),
+		Total:        message.GetTotal(),
+		CustomerID:   message.GetCustomerId(),
+	}
+	for _, item := range message.GetItems() {
+		receipt.Items = append(receipt.Items, domain.Item{ShortDescription: item.GetShortDescription(), Price: item.GetPrice()})
+	}
+	return receipt, nil
+}
+
+// protobufDocument decodes a receiptpb.Receipt message into its JSON-shaped document.
+// Proto3 does not tell an unset string from an empty one, so empty fields are left out and reported
+// as missing, like a JSON receipt without them.
+func protobufDocument(data []byte) (map[string]any, error) {
+	var message receiptpb.Receipt
+	if err := proto.Unmarshal(data, &message); err != nil {
+		return nil, err
+	}
+
+	document := make(map[string]any)
+	setNonEmpty(document, "retailer", message.GetRetailer())
+	setNonEmpty(document, "purchaseDate", message.GetPurchaseDate())
+	setNonEmpty(document, "purchaseTime", message.GetPurchaseTime())
+	setNonEmpty(document, "total", message.GetTotal())
+	setNonEmpty(document, "customerId", message.GetCustomerId())
+	if len(message.GetItems()) > 0 {
+		items := make([]any, len(message.GetItems()))
+		for i, item := range message.GetItems() {
+			fields := make(map[string]any)
+			setNonEmpty(fields, "shortDescription", item.GetShortDescription())
+			setNonEmpty(fields, "price", item.GetPrice())
+			items[i] = fields
+		}
+		document["items"] = items
+	}
+	return document, nil
+}
+
+// setNonEmpty adds value to document under key unless it is empty
+func setNonEmpty(document map[string]any, key, value string) {
+	if value != "" {
+		document[key] = value
+	}
+}
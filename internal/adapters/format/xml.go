@@ -0,0 +1,101 @@
+package format
+
+import (
+	"encoding/xml"
+	"go-receipt-processor/internal/domain"
+)
+
+// xmlReceipt is the XML encoding of a receipt:
+//
+//	<receipt>
+//	  <retailer>Target</retailer>
+//	  <purchaseDate>2022-01-01</purchaseDate>
+//	  <purchaseTime>13:01</purchaseTime>
+//	  <items>
+//	    <item><shortDescription>Mountain Dew 12PK</shortDescription><price>6.49</price></item>
+//	  </items>
+//	  <total>6.49</total>
+//	</receipt>
+//
+// Fields are pointers so that missing elements are told apart from empty ones.
+type xmlReceipt struct {
+	XMLName      xml.Name  `xml:"receipt"`
+	Retailer     *string   `xml:"retailer"`
+	PurchaseDate *string   `xml:"purchaseDate"`
+	PurchaseTime *string   `xml:"purchaseTime"`
+	Items        *xmlItems `xml:"items"`
+	Total        *string   `xml:"total"`
+	CustomerID   *string   `xml:"customerId,omitempty"`
+}
+
+type xmlItems struct {
+	Items []xmlItem `xml:"item"`
+}
+
+type xmlItem struct {
+	ShortDescription *string `xml:"shortDescription"`
+	Price            *string `xml:"price"`
+}
+
+// MarshalReceiptXML
+//
+// Parameters:
+//   - receipt: The receipt to encode; only the fields a client submits are included.
+//
+// Returns:
+//   - The receipt as an XML document that DecodeReceipt reads back.
+//   - err: An error if the receipt cannot be encoded.
+func MarshalReceiptXML(receipt domain.Receipt) ([]byte, error) {
+	doc := xmlReceipt{
+		Retailer:     &receipt.Retailer,
+		PurchaseDate: &receipt.PurchaseDate,
+		PurchaseTime: &receipt.PurchaseTime,
+		Items:        &xmlItems{Items: make([]xmlItem, len(receipt.Items))},
+		Total:        &receipt.Total,
+	}
+	if receipt.CustomerID != "" {
+		doc.CustomerID = &receipt.CustomerID
+	}
+	for i := range receipt.Items {
+		doc.Items.Items[i] = xmlItem{ShortDescription: &receipt.Items[i].ShortDescription, Price: &receipt.Items[i].Price}
+	}
+
+	encoded, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), encoded...), nil
+}
+
+// xmlDocument decodes an XML receipt into its JSON-shaped document
+func xmlDocument(data []byte) (map[string]any, error) {
+	var doc xmlReceipt
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	document := make(map[string]any)
+	setString(document, "retailer", doc.Retailer)
+	setString(document, "purchaseDate", doc.PurchaseDate)
+	setString(document, "purchaseTime", doc.PurchaseTime)
+	setString(document, "total", doc.Total)
+	setString(document, "customerId", doc.CustomerID)
+	if doc.Items != nil {
+		items := make([]any, len(doc.Items.Items))
+		for i, item := range doc.Items.Items {
+			fields := make(map[string]any)
+			setString(fields, "shortDescription", item.ShortDescription)
+			setString(fields, "price", item.Price)
+			items[i] = fields
+		}
+		document["items"] = items
+	}
+	return document, nil
+}
+
+// setString adds value to document under key if it is present
+func setString(document map[string]any, key string, value *string) {
+	if value != nil {
+		document[key] = *value
+	}
+}
@@ -0,0 +1,99 @@
+package graphql
+
+import (
+	"context"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"time"
+
+	graphqlGo "github.com/graph-gophers/graphql-go"
+)
+
+// receiptResolver resolves the fields of a Receipt
+type receiptResolver struct {
+	receipt    domain.Receipt
+	calculator internalHttp.PointsCalculator
+}
+
+func (r *receiptResolver) ID() graphqlGo.ID       { return graphqlGo.ID(r.receipt.ID) }
+func (r *receiptResolver) Retailer() string       { return r.receipt.Retailer }
+func (r *receiptResolver) PurchaseDate() string   { return r.receipt.PurchaseDate }
+func (r *receiptResolver) PurchaseTime() string   { return r.receipt.PurchaseTime }
+func (r *receiptResolver) Total() string          { return r.receipt.Total }
+func (r *receiptResolver) Points() int32          { return int32(r.receipt.Points) }
+func (r *receiptResolver) ScoringVersion() string { return r.receipt.ScoringVersion }
+func (r *receiptResolver) Revision() int32        { return int32(r.receipt.Revision) }
+func (r *receiptResolver) CustomerID() *string    { return optionalString(r.receipt.CustomerID) }
+func (r *receiptResolver) DuplicateOf() *string   { return optionalString(r.receipt.DuplicateOf) }
+
+func (r *receiptResolver) ReceivedAt() string {
+	return r.receipt.ReceivedAt.UTC().Format(time.RFC3339)
+}
+
+func (r *receiptResolver) Items() []*itemResolver {
+	items := make([]*itemResolver, len(r.receipt.Items))
+	for i, item := range r.receipt.Items {
+		items[i] = &itemResolver{item: item}
+	}
+	return items
+}
+
+// Breakdown is only computed when the field is selected
+func (r *receiptResolver) Breakdown(ctx context.Context) ([]*rulePointsResolver, error) {
+	breakdown, err := r.calculator.Breakdown(ctx, r.receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*rulePointsResolver, len(breakdown))
+	for i, rule := range breakdown {
+		rules[i] = &rulePointsResolver{rule: rule}
+	}
+	return rules, nil
+}
+
+// itemResolver resolves the fields of an Item
+type itemResolver struct {
+	item domain.Item
+}
+
+func (r *itemResolver) ShortDescription() string { return r.item.ShortDescription }
+func (r *itemResolver) Price() string            { return r.item.Price }
+
+// rulePointsResolver resolves the fields of a RulePoints
+type rulePointsResolver struct {
+	rule domain.RulePoints
+}
+
+func (r *rulePointsResolver) Rule() string  { return r.rule.Rule }
+func (r *rulePointsResolver) Points() int32 { return int32(r.rule.Points) }
+
+// receiptConnectionResolver resolves the fields of a ReceiptConnection
+type receiptConnectionResolver struct {
+	receipts   []*receiptResolver
+	nextCursor *string
+}
+
+func (r *receiptConnectionResolver) Receipts() []*receiptResolver { return r.receipts }
+func (r *receiptConnectionResolver) NextCursor() *string          { return r.nextCursor }
+
+// processReceiptPayloadResolver resolves the fields of a ProcessReceiptPayload
+type processReceiptPayloadResolver struct {
+	id       string
+	resolver *Resolver
+}
+
+func (r *processReceiptPayloadResolver) ID() graphqlGo.ID { return graphqlGo.ID(r.id) }
+
+// Receipt is only fetched when the field is selected
+func (r *processReceiptPayloadResolver) Receipt(ctx context.Context) (*receiptResolver, error) {
+	return r.resolver.Receipt(ctx, struct{ ID graphqlGo.ID }{graphqlGo.ID(r.id)})
+}
+
+// optionalString returns nil for empty strings, so they resolve to null
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
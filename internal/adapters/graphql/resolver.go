@@ -0,0 +1,204 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"go-receipt-processor/api"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/repository"
+	"time"
+
+	graphqlGo "github.com/graph-gophers/graphql-go"
+)
+
+const (
+	maxReceiptsPerPage = 100 // Largest page requested with "first", matching GET /receipts
+	maxQueryDepth      = 10  // Deepest selection accepted, so a single query cannot fan out without bound
+)
+
+//go:embed schema.graphql
+var schemaSource string
+
+// sortFields maps the ReceiptSort enum onto the store's sort fields.
+var sortFields = map[string]string{
+	"PURCHASE_DATE": repository.SortByPurchaseDate,
+	"RETAILER":      repository.SortByRetailer,
+	"POINTS":        repository.SortByPoints,
+	"TOTAL":         repository.SortByTotal,
+}
+
+// Resolver resolves the root Query and Mutation fields through the ReceiptService port.
+type Resolver struct {
+	ReceiptService   internalHttp.ReceiptService
+	PointsCalculator internalHttp.PointsCalculator // Explains receipts' points in Receipt.breakdown
+}
+
+// NewSchema
+//
+// Parameters:
+//   - service: The ReceiptService receipts are processed, fetched and listed through.
+//   - calculator: The PointsCalculator used to break a receipt's points down by rule.
+//
+// Returns:
+//   - The parsed schema, ready to execute queries; queries nested deeper than 10 levels are refused.
+//   - err: An error if the schema does not match the resolvers.
+func NewSchema(service internalHttp.ReceiptService, calculator internalHttp.PointsCalculator) (*graphqlGo.Schema, error) {
+	return graphqlGo.ParseSchema(schemaSource, &Resolver{ReceiptService: service, PointsCalculator: calculator},
+		graphqlGo.MaxDepth(maxQueryDepth))
+}
+
+// Receipt resolves Query.receipt, returning null for unknown and deleted receipts
+func (r *Resolver) Receipt(ctx context.Context, args struct{ ID graphqlGo.ID }) (*receiptResolver, error) {
+	receipt, err := r.ReceiptService.GetReceipt(ctx, string(args.ID))
+	if errors.Is(err, repository.ErrReceiptNotFound) || errors.Is(err, repository.ErrReceiptDeleted) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &receiptResolver{receipt: receipt, calculator: r.PointsCalculator}, nil
+}
+
+// receiptFilter is the ReceiptFilter input
+type receiptFilter struct {
+	Retailer         *string
+	PurchaseDateFrom *string
+	PurchaseDateTo   *string
+	MinPoints        *int32
+	MaxPoints        *int32
+	MinTotal         *float64
+	MaxTotal         *float64
+}
+
+// receiptsArgs are the arguments of Query.receipts
+type receiptsArgs struct {
+	Filter     *receiptFilter
+	Sort       string
+	Descending bool
+	First      int32
+	After      *string
+}
+
+// Receipts resolves Query.receipts
+func (r *Resolver) Receipts(ctx context.Context, args receiptsArgs) (*receiptConnectionResolver, error) {
+	query, err := receiptQuery(args)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := r.ReceiptService.ListReceipts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &receiptConnectionResolver{receipts: make([]*receiptResolver, len(page.Receipts))}
+	for i, receipt := range page.Receipts {
+		connection.receipts[i] = &receiptResolver{receipt: receipt, calculator: r.PointsCalculator}
+	}
+	if page.NextCursor != "" {
+		connection.nextCursor = &page.NextCursor
+	}
+	return connection, nil
+}
+
+// receiptQuery validates the arguments of Query.receipts and converts them into a ReceiptQuery
+func receiptQuery(args receiptsArgs) (repository.ReceiptQuery, error) {
+	if args.First < 1 || args.First > maxReceiptsPerPage {
+		return repository.ReceiptQuery{}, fmt.Errorf("first must be between 1 and %d", maxReceiptsPerPage)
+	}
+
+	query := repository.ReceiptQuery{
+		SortBy:         sortFields[args.Sort],
+		SortDescending: args.Descending,
+		Limit:          int(args.First),
+	}
+	if args.After != nil {
+		query.Cursor = *args.After
+	}
+
+	filter := args.Filter
+	if filter == nil {
+		return query, nil
+	}
+	if filter.Retailer != nil {
+		query.Retailer = *filter.Retailer
+	}
+	for name, date := range map[string]*string{"purchaseDateFrom": filter.PurchaseDateFrom, "purchaseDateTo": filter.PurchaseDateTo} {
+		if date == nil {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", *date); err != nil {
+			return query, fmt.Errorf("%s must be a date in YYYY-MM-DD format", name)
+		}
+	}
+	if filter.PurchaseDateFrom != nil {
+		query.PurchaseDateFrom = *filter.PurchaseDateFrom
+	}
+	if filter.PurchaseDateTo != nil {
+		query.PurchaseDateTo = *filter.PurchaseDateTo
+	}
+	query.MinPoints = intPointer(filter.MinPoints)
+	query.MaxPoints = intPointer(filter.MaxPoints)
+	query.MinTotal = filter.MinTotal
+	query.MaxTotal = filter.MaxTotal
+	return query, nil
+}
+
+// Points resolves Query.points
+func (r *Resolver) Points(ctx context.Context, args struct{ ID graphqlGo.ID }) (int32, error) {
+	points, err := r.ReceiptService.GetPoints(ctx, string(args.ID))
+	if err != nil {
+		return 0, err
+	}
+	return int32(points), nil
+}
+
+// receiptInput is the ReceiptInput input
+type receiptInput struct {
+	Retailer     string
+	PurchaseDate string
+	PurchaseTime string
+	Items        []struct {
+		ShortDescription string
+		Price            string
+	}
+	Total      string
+	CustomerID *string
+}
+
+// ProcessReceipt resolves Mutation.processReceipt, validating the receipt with the rules applied to JSON submissions
+func (r *Resolver) ProcessReceipt(ctx context.Context, args struct{ Input receiptInput }) (*processReceiptPayloadResolver, error) {
+	receipt := domain.Receipt{
+		Retailer:     args.Input.Retailer,
+		PurchaseDate: args.Input.PurchaseDate,
+		PurchaseTime: args.Input.PurchaseTime,
+		Total:        args.Input.Total,
+	}
+	if args.Input.CustomerID != nil {
+		receipt.CustomerID = *args.Input.CustomerID
+	}
+	for _, item := range args.Input.Items {
+		receipt.Items = append(receipt.Items, domain.Item{ShortDescription: item.ShortDescription, Price: item.Price})
+	}
+	if err := api.ValidateReceipt(receipt); err != nil {
+		return nil, fmt.Errorf("invalid receipt: %w", err)
+	}
+
+	receiptID, err := r.ReceiptService.ProcessReceipt(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+	return &processReceiptPayloadResolver{id: receiptID, resolver: r}, nil
+}
+
+// intPointer converts an optional GraphQL Int into an optional int
+func intPointer(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+	converted := int(*value)
+	return &converted
+}
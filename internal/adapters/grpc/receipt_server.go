@@ -0,0 +1,159 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"go-receipt-processor/api"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/grpc/receiptpb"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReceiptServer implements the receipt.v1.ReceiptService gRPC service on top of the ReceiptService port.
+type ReceiptServer struct {
+	receiptpb.UnimplementedReceiptServiceServer
+
+	ReceiptService internalHttp.ReceiptService
+	ReceiptTimeout time.Duration // Deadline applied to each receipt of a ProcessReceipts stream; zero disables it
+}
+
+// NewReceiptServer
+//
+// Parameters:
+//   - service: The ReceiptService responsible for scoring, storing and looking up receipts.
+//   - receiptTimeout: The deadline applied to each receipt of a ProcessReceipts stream, since the stream as a
+//     whole may stay open far longer.
+//
+// Returns:
+//   - A new instance of ReceiptServer with the provided ReceiptService.
+func NewReceiptServer(service internalHttp.ReceiptService, receiptTimeout time.Duration) *ReceiptServer {
+	return &ReceiptServer{ReceiptService: service, ReceiptTimeout: receiptTimeout}
+}
+
+// ProcessReceipt
+//
+// Parameters:
+//   - ctx: The call context, carrying the client's deadline.
+//   - req: The receipt to score and store.
+//
+// Returns:
+//   - The ID of the stored receipt.
+//   - err: An InvalidArgument status if the receipt is incomplete, AlreadyExists if it duplicates a stored receipt
+//     and duplicates are rejected, or the status matching any other processing error.
+func (s *ReceiptServer) ProcessReceipt(ctx context.Context, req *receiptpb.ProcessReceiptRequest) (*receiptpb.ProcessReceiptResponse, error) {
+	receipt, err := receiptFromProto(req.GetReceipt())
+	if err != nil {
+		return nil, err
+	}
+
+	receiptID, err := s.ReceiptService.ProcessReceipt(ctx, receipt)
+	if err != nil {
+		return nil, statusForError(err)
+	}
+
+	return &receiptpb.ProcessReceiptResponse{Id: receiptID}, nil
+}
+
+// GetPoints
+//
+// Parameters:
+//   - ctx: The call context, carrying the client's deadline.
+//   - req: The ID of the receipt.
+//
+// Returns:
+//   - The points awarded to the receipt.
+//   - err: A NotFound status if no receipt has the ID or it was deleted, Unavailable if a queued receipt has not
+//     been processed yet, or the status matching any other error.
+func (s *ReceiptServer) GetPoints(ctx context.Context, req *receiptpb.GetPointsRequest) (*receiptpb.GetPointsResponse, error) {
+	points, err := s.ReceiptService.GetPoints(ctx, req.GetId())
+	if err != nil {
+		return nil, statusForError(err)
+	}
+
+	return &receiptpb.GetPointsResponse{Points: int64(points)}, nil
+}
+
+// ProcessReceipts
+//
+// Receipts are processed one at a time as they arrive and each is answered before the next is read, so results
+// come back in request order. A receipt that fails is reported with the status code ProcessReceipt would have
+// returned, and the stream carries on.
+//
+// Parameters:
+//   - stream: The bidirectional stream of receipts and their results.
+//
+// Returns:
+//   - err: nil once the client closes its side of the stream, or the error that ended it early.
+func (s *ReceiptServer) ProcessReceipts(stream receiptpb.ReceiptService_ProcessReceiptsServer) error {
+	for index := int64(0); ; index++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		result := &receiptpb.ProcessReceiptsResult{Index: index}
+		id, err := s.processOne(stream.Context(), req)
+		if err != nil {
+			st := status.Convert(err)
+			result.Code = uint32(st.Code())
+			result.Message = st.Message()
+		}
+		result.Id = id
+
+		if err := stream.Send(result); err != nil {
+			return err
+		}
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+	}
+}
+
+// processOne handles one receipt of a ProcessReceipts stream under ReceiptTimeout
+func (s *ReceiptServer) processOne(ctx context.Context, req *receiptpb.ProcessReceiptRequest) (string, error) {
+	if s.ReceiptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.ReceiptTimeout)
+		defer cancel()
+	}
+
+	resp, err := s.ProcessReceipt(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return resp.GetId(), nil
+}
+
+// receiptFromProto converts a protobuf receipt and validates it with the rules applied to JSON submissions
+func receiptFromProto(pb *receiptpb.Receipt) (domain.Receipt, error) {
+	if pb == nil {
+		return domain.Receipt{}, status.Error(codes.InvalidArgument, "receipt is required")
+	}
+
+	receipt := domain.Receipt{
+		Retailer:     pb.GetRetailer(),
+		PurchaseDate: pb.GetPurchaseDate(),
+		PurchaseTime: pb.GetPurchaseTime(),
+		Total:        pb.GetTotal(),
+		CustomerID:   pb.GetCustomerId(),
+	}
+	for _, item := range pb.GetItems() {
+		receipt.Items = append(receipt.Items, domain.Item{
+			ShortDescription: item.GetShortDescription(),
+			Price:            item.GetPrice(),
+		})
+	}
+
+	if err := api.ValidateReceipt(receipt); err != nil {
+		return domain.Receipt{}, status.Errorf(codes.InvalidArgument, "invalid receipt: %v", err)
+	}
+	return receipt, nil
+}
@@ -0,0 +1,115 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/pkg/utils"
+	"time"
+
+	"github.com/google/uuid"
+	googleGrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys read and written by the request context interceptors, matching the HTTP headers.
+const (
+	RequestIDMetadata = "x-request-id"
+	TenantIDMetadata  = "x-tenant-id"
+)
+
+// RequestContextUnaryInterceptor
+//
+// Parameters:
+//   - timeout: The deadline applied to every call's context; zero or negative leaves calls with only the client's deadline.
+//
+// Returns:
+//   - A unary interceptor that stores the request ID (taken from x-request-id metadata or generated) and tenant ID
+//     (from x-tenant-id) in the call context, echoes the request ID in the response header, and applies the deadline.
+func RequestContextUnaryInterceptor(timeout time.Duration) googleGrpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *googleGrpc.UnaryServerInfo, handler googleGrpc.UnaryHandler) (interface{}, error) {
+		ctx = withRequestContext(ctx)
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RequestContextStreamInterceptor
+//
+// Returns:
+//   - A stream interceptor like RequestContextUnaryInterceptor but without the overall deadline, for streams that
+//     may legitimately stay open far longer than REQUEST_TIMEOUT.
+func RequestContextStreamInterceptor() googleGrpc.StreamServerInterceptor {
+	return func(srv interface{}, ss googleGrpc.ServerStream, _ *googleGrpc.StreamServerInfo, handler googleGrpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withRequestContext(ss.Context())})
+	}
+}
+
+// contextStream overrides the context of a server stream
+type contextStream struct {
+	googleGrpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// withRequestContext copies the request and tenant IDs from incoming metadata into ctx
+func withRequestContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := firstMetadata(md, RequestIDMetadata)
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	googleGrpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, requestID))
+
+	ctx = utils.WithRequestID(ctx, requestID)
+	if tenantID := firstMetadata(md, TenantIDMetadata); tenantID != "" {
+		ctx = utils.WithTenantID(ctx, tenantID)
+	}
+	return ctx
+}
+
+// firstMetadata returns the first value of a metadata key, or "" if it is absent
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// statusForError maps an error returned by the service to a gRPC status, following the same
+// distinctions statusForError draws for HTTP.
+func statusForError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code := codes.Internal
+	switch {
+	case errors.Is(err, repository.ErrReceiptNotFound), errors.Is(err, repository.ErrReceiptDeleted):
+		code = codes.NotFound
+	case errors.Is(err, repository.ErrRevisionConflict):
+		code = codes.Aborted
+	case errors.Is(err, internalHttp.ErrDuplicateReceipt):
+		code = codes.AlreadyExists
+	case errors.Is(err, internalHttp.ErrReceiptPending):
+		code = codes.Unavailable
+	case errors.Is(err, repository.ErrQueueFull):
+		code = codes.ResourceExhausted
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"fmt"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/internal/ports/repository"
+	netHttp "net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BackupHandler manages HTTP requests for taking online backups of the receipt store.
+type BackupHandler struct {
+	Store repository.BackupStore // Store capable of writing a consistent snapshot
+	Dir   string                 // Directory backup files are written to
+}
+
+// NewBackupHandler
+//
+// Parameters:
+//   - store: The BackupStore responsible for writing the snapshot.
+//   - dir: The directory in which backup files are created.
+//
+// Returns:
+//   - A new instance of BackupHandler with the provided store and directory.
+func NewBackupHandler(store repository.BackupStore, dir string) *BackupHandler {
+	return &BackupHandler{Store: store, Dir: dir}
+}
+
+// Backup
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and the backup location and size,
+//     or a 500 Internal Server Error if the backup fails.
+func (h *BackupHandler) Backup(c *gin.Context) {
+	name := fmt.Sprintf("receipts-%s.db", time.Now().UTC().Format("20060102T150405Z"))
+	path := filepath.Join(h.Dir, name)
+
+	written, err := h.Store.BackupToFile(c.Request.Context(), path)
+	if err != nil {
+		c.JSON(netHttp.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(netHttp.StatusOK, response.BackupResponse{
+		Path:  path,
+		Bytes: written,
+	})
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-receipt-processor/api"
+	"go-receipt-processor/internal/adapters/format"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	"io"
+	netHttp "net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BatchProcessHandler manages HTTP requests for processing many receipts in one call.
+type BatchProcessHandler struct {
+	ReceiptService internalHttp.ReceiptService
+	MaxSize        int // Most receipts accepted in a single request
+}
+
+// NewBatchProcessHandler
+//
+// Parameters:
+//   - service: The ReceiptService responsible for scoring and storing the receipts.
+//   - maxSize: The most receipts accepted in a single request.
+//
+// Returns:
+//   - A new instance of BatchProcessHandler with the provided ReceiptService and size limit.
+func NewBatchProcessHandler(service internalHttp.ReceiptService, maxSize int) *BatchProcessHandler {
+	return &BatchProcessHandler{ReceiptService: service, MaxSize: maxSize}
+}
+
+// ProcessBatch
+//
+// The body is a JSON array of receipts in the same format accepted by ProcessReceipt, or, with a text/csv
+// Content-Type, any number of receipts in the CSV layout of format.MarshalReceiptsCSV. Each receipt is
+// validated and processed independently: invalid or failing receipts are reported in their result
+// without affecting the others.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and one result per receipt in submission order,
+//     a 400 Bad Request if the body is not a non-empty JSON array or does not follow the CSV layout,
+//     or a 413 Request Entity Too Large if it holds more receipts than allowed or is larger than
+//     MaxSize receipts of maxStreamLineBytes each.
+func (h *BatchProcessHandler) ProcessBatch(c *gin.Context) {
+	maxBytes := int64(h.MaxSize) * maxStreamLineBytes
+	c.Request.Body = netHttp.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+
+	items, err := bindBatch(c)
+	var tooLarge *netHttp.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(netHttp.StatusRequestEntityTooLarge, gin.H{
+			"error":   "Batch too large",
+			"details": fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+	if len(items) == 0 {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": "batch must contain at least one receipt"})
+		return
+	}
+	if len(items) > h.MaxSize {
+		c.JSON(netHttp.StatusRequestEntityTooLarge, gin.H{
+			"error":   "Batch too large",
+			"details": fmt.Sprintf("batch holds %d receipts; at most %d are accepted", len(items), h.MaxSize),
+		})
+		return
+	}
+
+	body := response.BatchProcessResponse{Results: make([]response.BatchItemResult, len(items))}
+
+	// Only valid receipts are sent to the service; positions maps them back to their index in the batch
+	var receipts []domain.Receipt
+	var positions []int
+	for i, item := range items {
+		body.Results[i].Index = i
+		receipt, err := decodeReceipt(item)
+		if err != nil {
+			body.Results[i].Error = &response.BatchError{Status: netHttp.StatusBadRequest, Message: err.Error()}
+			continue
+		}
+		receipts = append(receipts, receipt)
+		positions = append(positions, i)
+	}
+
+	if len(receipts) > 0 {
+		for j, result := range h.ReceiptService.ProcessReceipts(c.Request.Context(), receipts) {
+			i := positions[j]
+			if result.Err != nil {
+				body.Results[i].Error = &response.BatchError{Status: statusForError(result.Err), Message: result.Err.Error()}
+				continue
+			}
+			body.Results[i].ID = result.ReceiptID
+		}
+	}
+
+	for _, result := range body.Results {
+		if result.Error != nil {
+			body.Failed++
+		} else {
+			body.Succeeded++
+		}
+	}
+
+	c.JSON(netHttp.StatusOK, body)
+}
+
+// bindBatch splits the request body into the JSON document of each receipt it holds, reading CSV bodies
+// with format.UnmarshalReceiptsCSV and any other type as a JSON array
+func bindBatch(c *gin.Context) ([]json.RawMessage, error) {
+	if c.ContentType() != format.MIMECSV {
+		var items []json.RawMessage
+		err := c.ShouldBindJSON(&items)
+		return items, err
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := format.UnmarshalReceiptsCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]json.RawMessage, len(receipts))
+	for i, receipt := range receipts {
+		if items[i], err = json.Marshal(receipt); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// decodeReceipt validates a single receipt against the OpenAPI Receipt schema, the rules the validation
+// middleware applies to POST /receipt/process, and decodes it
+func decodeReceipt(data []byte) (domain.Receipt, error) {
+	if err := api.ValidateReceipt(data); err != nil {
+		return domain.Receipt{}, err
+	}
+	return format.DecodeReceipt(format.MIMEJSON, data)
+}
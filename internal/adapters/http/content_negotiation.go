@@ -0,0 +1,88 @@
+package http
+
+import (
+	"go-receipt-processor/api"
+	"go-receipt-processor/internal/adapters/format"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/grpc/receiptpb"
+	"io"
+	netHttp "net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+)
+
+// receiptFormats are the representations the receipt submission endpoints answer with, the first being the default
+var receiptFormats = []string{gin.MIMEJSON, format.MIMEProtobuf, format.MIMEMsgpack}
+
+// pointsFormats are the representations GET /receipt/:id/points answers with, the first being the default
+var pointsFormats = []string{gin.MIMEJSON, gin.MIMEXML, gin.MIMEXML2, gin.MIMEPlain, format.MIMEProtobuf, format.MIMEMsgpack}
+
+// representation is a response body in each of the formats negotiate can write it in
+type representation struct {
+	body  any           // Written as JSON, XML or MessagePack
+	text  string        // Written as plain text
+	proto proto.Message // Written as protobuf
+}
+
+// errorRepresentation is the body of a failed request: {"error": message}, or a receiptpb.Error
+func errorRepresentation(message string) representation {
+	return representation{body: gin.H{"error": message}, text: message, proto: &receiptpb.Error{Error: message}}
+}
+
+// bindReceipt decodes the request body as a receipt in the format named by its Content-Type: XML, the CSV layout
+// of format.MarshalReceiptsCSV, protobuf, MessagePack, or JSON for any other type. The receipt is checked against
+// the Receipt schema even when OpenAPIValidationMiddleware already has, so handlers mounted without it accept
+// exactly the same receipts; an invalid one is reported as an *api.ValidationError.
+func bindReceipt(c *gin.Context) (domain.Receipt, error) {
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return domain.Receipt{}, err
+	}
+	receipt, err := format.DecodeReceipt(c.ContentType(), data)
+	if err != nil {
+		return domain.Receipt{}, err
+	}
+	if err := api.ValidateReceipt(receipt); err != nil {
+		return domain.Receipt{}, err
+	}
+	return receipt, nil
+}
+
+// negotiate writes rep in whichever of formats the Accept header prefers,
+// and answers 406 Not Acceptable if it accepts none of them
+func negotiate(c *gin.Context, status int, formats []string, rep representation) {
+	accepted := c.NegotiateFormat(formats...)
+	if accepted == "" {
+		c.JSON(netHttp.StatusNotAcceptable, gin.H{"error": "Accept must allow " + strings.Join(formats, ", ")})
+		return
+	}
+	render(c, status, accepted, rep)
+}
+
+// render writes rep in the given negotiated format
+func render(c *gin.Context, status int, mediaType string, rep representation) {
+	switch mediaType {
+	case gin.MIMEXML, gin.MIMEXML2:
+		c.XML(status, rep.body)
+	case gin.MIMEPlain:
+		c.String(status, rep.text)
+	case format.MIMEProtobuf:
+		data, err := proto.Marshal(rep.proto)
+		if err != nil {
+			c.JSON(netHttp.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(status, format.MIMEProtobuf, data)
+	case format.MIMEMsgpack:
+		data, err := format.MarshalMsgpack(rep.body)
+		if err != nil {
+			c.JSON(netHttp.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(status, format.MIMEMsgpack, data)
+	default:
+		c.JSON(status, rep.body)
+	}
+}
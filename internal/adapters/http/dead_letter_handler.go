@@ -0,0 +1,121 @@
+package http
+
+import (
+	"errors"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/internal/ports/repository"
+	netHttp "net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeadLetterHandler manages admin HTTP requests for queued receipts that could not be processed.
+type DeadLetterHandler struct {
+	ReceiptService internalHttp.ReceiptService
+}
+
+// NewDeadLetterHandler
+//
+// Parameters:
+//   - service: The ReceiptService holding the dead letters.
+//
+// Returns:
+//   - A new instance of DeadLetterHandler with the provided ReceiptService.
+func NewDeadLetterHandler(service internalHttp.ReceiptService) *DeadLetterHandler {
+	return &DeadLetterHandler{ReceiptService: service}
+}
+
+// ListDeadLetters
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and every dead letter, or a 500 Internal Server Error if an error occurs.
+func (h *DeadLetterHandler) ListDeadLetters(c *gin.Context) {
+	letters, err := h.ReceiptService.ListDeadLetters(c.Request.Context())
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	body := response.DeadLettersResponse{
+		DeadLetters: make([]response.DeadLetterResponse, len(letters)),
+		Count:       len(letters),
+	}
+	for i, letter := range letters {
+		body.DeadLetters[i] = newDeadLetterResponse(letter)
+	}
+	c.JSON(netHttp.StatusOK, body)
+}
+
+// GetDeadLetter
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and the dead letter, a 404 Not Found if there is none with the ID,
+//     or a 500 Internal Server Error if an error occurs.
+func (h *DeadLetterHandler) GetDeadLetter(c *gin.Context) {
+	letter, err := h.ReceiptService.GetDeadLetter(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(netHttp.StatusOK, newDeadLetterResponse(letter))
+}
+
+// RequeueDeadLetter
+//
+// The receipt is queued again under the same ID, so clients polling for its points pick it up once processed.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 202 Accepted status, the receipt ID and status "pending", a 404 Not Found
+//     if there is no dead letter with the ID, a 429 Too Many Requests if the queue is full,
+//     or a 500 Internal Server Error if an error occurs.
+func (h *DeadLetterHandler) RequeueDeadLetter(c *gin.Context) {
+	job, err := h.ReceiptService.RequeueDeadLetter(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrQueueFull) {
+		c.Header("Retry-After", queueFullRetryAfter)
+	}
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(netHttp.StatusAccepted, response.ReceiptStatusResponse{
+		ID:     job.ID,
+		Status: string(job.Status),
+	})
+}
+
+// newDeadLetterResponse maps a dead letter onto its response DTO
+func newDeadLetterResponse(letter domain.DeadLetter) response.DeadLetterResponse {
+	receipt := response.SubmittedReceipt{
+		Retailer:     letter.Receipt.Retailer,
+		PurchaseDate: letter.Receipt.PurchaseDate,
+		PurchaseTime: letter.Receipt.PurchaseTime,
+		Items:        make([]response.ReceiptItem, len(letter.Receipt.Items)),
+		Total:        letter.Receipt.Total,
+		CustomerID:   letter.Receipt.CustomerID,
+	}
+	for i, item := range letter.Receipt.Items {
+		receipt.Items[i] = response.ReceiptItem{ShortDescription: item.ShortDescription, Price: item.Price}
+	}
+
+	return response.DeadLetterResponse{
+		ID:         letter.ID,
+		Receipt:    receipt,
+		Reason:     letter.Reason,
+		Attempts:   letter.Attempts,
+		EnqueuedAt: letter.EnqueuedAt,
+		FailedAt:   letter.FailedAt,
+	}
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/internal/ports/repository"
+	netHttp "net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader names the caller responsible for a deletion; it is recorded in the audit tombstone.
+const ActorHeader = "X-Actor"
+
+// DeleteReceiptHandler manages HTTP requests for deleting single receipts and erasing receipts in bulk.
+type DeleteReceiptHandler struct {
+	ReceiptService internalHttp.ReceiptService
+}
+
+// eraseReceiptsRequest is the body of a bulk erasure request; exactly one selector must be set.
+type eraseReceiptsRequest struct {
+	CustomerID string `json:"customerId"`
+	Retailer   string `json:"retailer"`
+	Reason     string `json:"reason" binding:"required"`
+}
+
+// NewDeleteReceiptHandler
+//
+// Parameters:
+//   - service: The ReceiptService responsible for deleting receipts.
+//
+// Returns:
+//   - A new instance of DeleteReceiptHandler with the provided ReceiptService.
+func NewDeleteReceiptHandler(service internalHttp.ReceiptService) *DeleteReceiptHandler {
+	return &DeleteReceiptHandler{ReceiptService: service}
+}
+
+// DeleteReceipt
+//
+// The X-Actor header and the "reason" query parameter are required and stored in the receipt's tombstone.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and the tombstone, a 400 Bad Request if the actor or reason
+//     is missing, a 404 Not Found if no receipt has the ID, a 410 Gone if it was already deleted,
+//     or a 500 Internal Server Error if an error occurs.
+func (h *DeleteReceiptHandler) DeleteReceipt(c *gin.Context) {
+	id := c.Param("id")
+	actor := strings.TrimSpace(c.GetHeader(ActorHeader))
+	reason := strings.TrimSpace(c.Query("reason"))
+
+	if actor == "" {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": ActorHeader + " header is required"})
+		return
+	}
+	if reason == "" {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid query parameter", "details": "reason is required"})
+		return
+	}
+
+	tombstone, err := h.ReceiptService.DeleteReceipt(c.Request.Context(), id, actor, reason)
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(netHttp.StatusOK, response.DeleteReceiptResponse{
+		ID:        tombstone.ReceiptID,
+		DeletedAt: tombstone.DeletedAt,
+		DeletedBy: tombstone.DeletedBy,
+		Reason:    tombstone.Reason,
+	})
+}
+
+// EraseReceipts
+//
+// The body names either a customerId or a retailer, plus a reason; the X-Actor header is required.
+// Every matching receipt is deleted and leaves a tombstone, so later lookups return 410 Gone.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and the erased IDs, a 400 Bad Request if the body or actor
+//     is invalid, or a 500 Internal Server Error if an error occurs.
+func (h *DeleteReceiptHandler) EraseReceipts(c *gin.Context) {
+	var req eraseReceiptsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	selector := repository.ErasureSelector{
+		CustomerID: strings.TrimSpace(req.CustomerID),
+		Retailer:   strings.TrimSpace(req.Retailer),
+	}
+	if (selector.CustomerID == "") == (selector.Retailer == "") {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": "exactly one of customerId or retailer is required"})
+		return
+	}
+
+	actor := strings.TrimSpace(c.GetHeader(ActorHeader))
+	if actor == "" {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": ActorHeader + " header is required"})
+		return
+	}
+
+	erased, err := h.ReceiptService.EraseReceipts(c.Request.Context(), selector, actor, strings.TrimSpace(req.Reason))
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(netHttp.StatusOK, response.EraseReceiptsResponse{
+		ErasedIDs: erased,
+		Count:     len(erased),
+	})
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	netHttp "net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headers written by DeprecationMiddleware.
+const (
+	DeprecationHeader = "Deprecation" // RFC 9745: when the API was deprecated, as "@" and Unix seconds
+	SunsetHeader      = "Sunset"      // RFC 8594: when the API stops being served, as an HTTP-date
+)
+
+// DeprecationMiddleware
+//
+// Parameters:
+//   - deprecatedAt: When the routes were deprecated.
+//   - sunset: When the routes will be removed.
+//
+// Returns:
+//   - A gin middleware that announces both dates on every response of the routes it is used on.
+func DeprecationMiddleware(deprecatedAt, sunset time.Time) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetDate := sunset.UTC().Format(netHttp.TimeFormat)
+
+	return func(c *gin.Context) {
+		c.Header(DeprecationHeader, deprecation)
+		c.Header(SunsetHeader, sunsetDate)
+		c.Next()
+	}
+}
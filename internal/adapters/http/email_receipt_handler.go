@@ -0,0 +1,87 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go-receipt-processor/internal/adapters/email"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"io"
+	netHttp "net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxEmailBytes bounds a raw email message. It is larger than a receipt, since attachments are sent along
+// even though they are skipped, while each text or HTML part is bounded by email.MaxPartBytes.
+const maxEmailBytes = 10 << 20
+
+// EmailReceiptHandler manages HTTP requests for processing receipts delivered by email.
+type EmailReceiptHandler struct {
+	ReceiptService internalHttp.ReceiptService
+	Extractor      *email.Extractor
+}
+
+// NewEmailReceiptHandler
+//
+// Parameters:
+//   - service: The ReceiptService responsible for scoring and storing the extracted receipts.
+//   - extractor: The Extractor reading receipts out of the emails with per-retailer templates.
+//
+// Returns:
+//   - A new instance of EmailReceiptHandler with the provided ReceiptService and Extractor.
+func NewEmailReceiptHandler(service internalHttp.ReceiptService, extractor *email.Extractor) *EmailReceiptHandler {
+	return &EmailReceiptHandler{ReceiptService: service, Extractor: extractor}
+}
+
+// ProcessEmail
+//
+// The body is a raw RFC 5322 message, e.g. the content of an .eml file. The receipt is extracted from its
+// HTML or text body, or that of a message attached to it, with the first template matching its sender, or
+// read like a printed receipt if none does. If it is valid, it is scored and stored like one sent to
+// POST /receipt/process. Receipts are always processed synchronously.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with the extracted receipt and the template that matched, or the confidence in each field
+//     if none did, and either a 200 OK status and the receipt ID, a 400 Bad Request if the body is not an email
+//     with a text or HTML body, a 413 Request Entity Too Large if the email is longer than 10 MiB or its text or
+//     HTML body longer than 1 MiB, a 422 Unprocessable Entity listing the fields that could not be extracted, a
+//     409 Conflict if the receipt duplicates a stored one, or the status POST /receipt/process would answer
+//     with if processing fails.
+func (h *EmailReceiptHandler) ProcessEmail(c *gin.Context) {
+	data, err := io.ReadAll(netHttp.MaxBytesReader(c.Writer, c.Request.Body, maxEmailBytes))
+	var tooLarge *netHttp.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(netHttp.StatusRequestEntityTooLarge, gin.H{
+			"error":   "Invalid request payload",
+			"details": fmt.Sprintf("email exceeds %d bytes", tooLarge.Limit),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	result, err := h.Extractor.Extract(bytes.NewReader(data))
+	if errors.Is(err, email.ErrPartTooLarge) {
+		c.JSON(netHttp.StatusRequestEntityTooLarge, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+	if errors.Is(err, email.ErrInvalidMessage) || errors.Is(err, email.ErrNoBody) {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(netHttp.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status, body := submitParsedReceipt(c, h.ReceiptService, result.Receipt)
+	body.Template = result.Template
+	body.Confidence = result.Confidence
+	c.JSON(status, body)
+}
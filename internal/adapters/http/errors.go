@@ -0,0 +1,32 @@
+package http
+
+import (
+	"context"
+	"errors"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/repository"
+	netHttp "net/http"
+)
+
+// statusForError maps an error returned by the service to an HTTP status code,
+// distinguishing missing and deleted receipts, lost update races, rejected duplicates, a full processing queue
+// and requests that ran out of time from genuine failures.
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrReceiptNotFound), errors.Is(err, repository.ErrDeadLetterNotFound),
+		errors.Is(err, repository.ErrSubscriptionNotFound):
+		return netHttp.StatusNotFound
+	case errors.Is(err, repository.ErrReceiptDeleted):
+		return netHttp.StatusGone
+	case errors.Is(err, repository.ErrRevisionConflict):
+		return netHttp.StatusPreconditionFailed
+	case errors.Is(err, internalHttp.ErrDuplicateReceipt):
+		return netHttp.StatusConflict
+	case errors.Is(err, repository.ErrQueueFull):
+		return netHttp.StatusTooManyRequests
+	case errors.Is(err, context.DeadlineExceeded):
+		return netHttp.StatusGatewayTimeout
+	default:
+		return netHttp.StatusInternalServerError
+	}
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	netHttp "net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetReceiptHandler manages HTTP requests for retrieving a stored receipt by ID.
+type GetReceiptHandler struct {
+	ReceiptService internalHttp.ReceiptService
+}
+
+// NewGetReceiptHandler
+//
+// Parameters:
+//   - service: The ReceiptService responsible for fetching receipts.
+//
+// Returns:
+//   - A new instance of GetReceiptHandler with the provided ReceiptService.
+func NewGetReceiptHandler(service internalHttp.ReceiptService) *GetReceiptHandler {
+	return &GetReceiptHandler{ReceiptService: service}
+}
+
+// GetReceipt
+//
+// The receipt's revision is sent in the ETag header for use with If-Match when correcting it.
+// The optional "fields" query parameter is a comma separated list of response fields to return,
+// e.g. ?fields=retailer,total,points. All fields are returned when it is omitted.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and the receipt, a 400 Bad Request if an unknown field
+//     is requested, a 404 Not Found if no receipt has the ID, a 410 Gone if the receipt was deleted, or a 500 Internal Server Error if an error occurs.
+func (h *GetReceiptHandler) GetReceipt(c *gin.Context) {
+	id := c.Param("id")
+
+	receipt, err := h.ReceiptService.GetReceipt(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("ETag", receiptETag(receipt))
+	body := newGetReceiptResponse(receipt)
+
+	fields := c.Query("fields")
+	if fields == "" {
+		c.JSON(netHttp.StatusOK, body)
+		return
+	}
+
+	selected, err := selectFields(body, strings.Split(fields, ","))
+	if err != nil {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid query parameter", "details": err.Error()})
+		return
+	}
+	c.JSON(netHttp.StatusOK, selected)
+}
+
+// newGetReceiptResponse maps a stored receipt onto its response DTO
+func newGetReceiptResponse(receipt domain.Receipt) response.GetReceiptResponse {
+	body := response.GetReceiptResponse{
+		ID:             receipt.ID,
+		Retailer:       receipt.Retailer,
+		PurchaseDate:   receipt.PurchaseDate,
+		PurchaseTime:   receipt.PurchaseTime,
+		Items:          make([]response.ReceiptItem, len(receipt.Items)),
+		Total:          receipt.Total,
+		Points:         receipt.Points,
+		CustomerID:     receipt.CustomerID,
+		ScoringVersion: receipt.ScoringVersion,
+		Revision:       receipt.Revision,
+		PointsDelta:    receipt.PointsDelta,
+		Fingerprint:    receipt.Fingerprint,
+		DuplicateOf:    receipt.DuplicateOf,
+	}
+	for i, item := range receipt.Items {
+		body.Items[i] = response.ReceiptItem{ShortDescription: item.ShortDescription, Price: item.Price}
+	}
+	if !receipt.ReceivedAt.IsZero() {
+		receivedAt := receipt.ReceivedAt
+		body.ReceivedAt = &receivedAt
+	}
+	if !receipt.RevisedAt.IsZero() {
+		revisedAt := receipt.RevisedAt
+		body.RevisedAt = &revisedAt
+	}
+	return body
+}
+
+// selectFields returns only the named top-level JSON fields of body.
+// Every requested name must be a field of the response, even if it is omitted for this receipt.
+func selectFields(body response.GetReceiptResponse, fields []string) (map[string]json.RawMessage, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &all); err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if !isReceiptResponseField(field) {
+			return nil, fmt.Errorf("unknown field '%s'", field)
+		}
+		if value, ok := all[field]; ok {
+			selected[field] = value
+		}
+	}
+	return selected, nil
+}
+
+// isReceiptResponseField reports whether name is one of GetReceiptResponse's JSON field names
+func isReceiptResponseField(name string) bool {
+	switch name {
+	case "id", "retailer", "purchaseDate", "purchaseTime", "items", "total", "points", "customerId", "receivedAt", "scoringVersion",
+		"revision", "pointsDelta", "revisedAt", "fingerprint", "duplicateOf":
+		return true
+	}
+	return false
+}
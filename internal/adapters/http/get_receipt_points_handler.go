@@ -1,9 +1,12 @@
 package http
 
 import (
+	"errors"
 	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/grpc/receiptpb"
 	"go-receipt-processor/internal/ports/http/response"
 	netHttp "net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,21 +29,39 @@ func NewGetReceiptPointsHandler(service internalHttp.ReceiptService) *GetReceipt
 
 // GetPoints
 //
+// Receipts submitted asynchronously get a 202 Accepted with their status until a worker has stored them.
+// The response is JSON, XML, plain text, protobuf or MessagePack as the Accept header prefers; plain text
+// holds only the points, or the status while the receipt is queued, and protobuf is a receiptpb.GetPointsResponse
+// or receiptpb.ReceiptStatus.
+//
 // Parameters:
 //   - c: The Gin context, which contains the HTTP request and response data.
 //
 // Returns:
-//   - A JSON response with either a 200 OK status and the points, or a 500 Internal Server Error if an error occurs.
+//   - A response with either a 200 OK status and the points, a 202 Accepted status and the processing status,
+//     a 406 Not Acceptable if no supported format is accepted, a 504 Gateway Timeout if the request deadline passes,
+//     or a 500 Internal Server Error if another error occurs.
 func (h *GetReceiptPointsHandler) GetPoints(c *gin.Context) {
 	id := c.Param("id")
 
-	points, err := h.ReceiptService.GetPoints(id)
+	points, err := h.ReceiptService.GetPoints(c.Request.Context(), id)
+	var pending *internalHttp.ReceiptPendingError
+	if errors.As(err, &pending) {
+		negotiate(c, netHttp.StatusAccepted, pointsFormats, representation{
+			body:  response.ReceiptStatusResponse{ID: id, Status: string(pending.Status)},
+			text:  string(pending.Status),
+			proto: &receiptpb.ReceiptStatus{Id: id, Status: string(pending.Status)},
+		})
+		return
+	}
 	if err != nil {
-		c.JSON(netHttp.StatusInternalServerError, gin.H{"error": err.Error()})
+		negotiate(c, statusForError(err), pointsFormats, errorRepresentation(err.Error()))
 		return
 	}
 
-	c.JSON(netHttp.StatusOK, response.GetReceiptPointsResponse{
-		Points: points,
+	negotiate(c, netHttp.StatusOK, pointsFormats, representation{
+		body:  response.GetReceiptPointsResponse{Points: points},
+		text:  strconv.Itoa(points),
+		proto: &receiptpb.GetPointsResponse{Points: int64(points)},
 	})
 }
@@ -0,0 +1,52 @@
+package http
+
+import (
+	netHttp "net/http"
+
+	"github.com/gin-gonic/gin"
+	graphqlGo "github.com/graph-gophers/graphql-go"
+)
+
+// GraphQLHandler manages HTTP requests for the GraphQL API.
+type GraphQLHandler struct {
+	Schema *graphqlGo.Schema
+}
+
+// graphQLRequest is the body of a GraphQL request.
+type graphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// NewGraphQLHandler
+//
+// Parameters:
+//   - schema: The executable GraphQL schema.
+//
+// Returns:
+//   - A new instance of GraphQLHandler with the provided schema.
+func NewGraphQLHandler(schema *graphqlGo.Schema) *GraphQLHandler {
+	return &GraphQLHandler{Schema: schema}
+}
+
+// Query
+//
+// Executes a query or mutation. As is conventional for GraphQL, field errors such as an invalid receipt
+// are reported in the "errors" array of a 200 OK response, next to whatever data could still be resolved.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and the "data" and "errors" of the execution,
+//     or a 400 Bad Request if the body is not a GraphQL request.
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	c.JSON(netHttp.StatusOK, h.Schema.Exec(c.Request.Context(), req.Query, req.OperationName, req.Variables))
+}
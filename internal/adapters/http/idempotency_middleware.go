@@ -0,0 +1,169 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/pkg/utils"
+	"io"
+	"mime"
+	netHttp "net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headers read and written by IdempotencyMiddleware.
+const (
+	IdempotencyKeyHeader    = "Idempotency-Key"
+	IdempotentReplayHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyMiddleware
+//
+// Requests without an Idempotency-Key header pass straight through. For the first request with a key,
+// a successful response is recorded; retries with the same key and body within the window receive the
+// recorded status and body again (with Idempotent-Replayed: true) instead of being processed twice.
+// A retry whose Accept header does not allow the recorded response's Content-Type counts as a different
+// request, since replaying it would ignore the format the client asked for.
+// Keys are scoped to the tenant from X-Tenant-ID and to the route.
+//
+// Parameters:
+//   - store: The IdempotencyStore holding the recorded responses.
+//   - window: How long a key is remembered after its first use.
+//
+// Returns:
+//   - A gin middleware answering 422 Unprocessable Entity when a key is reused with a different body or Accept,
+//     409 Conflict while the first request with the key is still in progress, and 400 Bad Request for
+//     keys longer than 255 characters.
+func IdempotencyMiddleware(store repository.IdempotencyStore, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": IdempotencyKeyHeader + " must be at most 255 characters"})
+			return
+		}
+		// A response recorded for one route, e.g. /receipt/process, is never replayed on another such as
+		// /v2/receipt/process, whose responses have a different shape
+		key = c.FullPath() + ":" + key
+		if tenantID := utils.TenantIDFrom(c.Request.Context()); tenantID != "" {
+			key = tenantID + ":" + key
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := requestHash(body)
+		now := time.Now().UTC()
+		existing, reserved, err := store.Reserve(c.Request.Context(), domain.IdempotencyRecord{
+			Key:         key,
+			RequestHash: hash,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(window),
+		})
+		if err != nil {
+			c.AbortWithStatusJSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		if !reserved {
+			switch {
+			case existing.RequestHash != hash:
+				c.AbortWithStatusJSON(netHttp.StatusUnprocessableEntity, gin.H{"error": IdempotencyKeyHeader + " was already used with a different request"})
+			case !existing.Completed:
+				c.AbortWithStatusJSON(netHttp.StatusConflict, gin.H{"error": "a request with this " + IdempotencyKeyHeader + " is still being processed"})
+			case !acceptsReplay(c, existing.ContentType):
+				c.AbortWithStatusJSON(netHttp.StatusUnprocessableEntity, gin.H{"error": IdempotencyKeyHeader + " was already used with a request for " + existing.ContentType})
+			default:
+				c.Header(IdempotentReplayHeader, "true")
+				c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		// Record the outcome even if the client has gone away, so its retry is answered correctly
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		// Failed requests are not remembered, so a retry is processed afresh. The reservation is released
+		// in a defer so that a handler that panics does not leave the key in progress until it expires.
+		completed := false
+		defer func() {
+			if !completed {
+				store.Release(ctx, key)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status < 200 || status >= 300 {
+			return
+		}
+
+		completed = true
+		store.Complete(ctx, domain.IdempotencyRecord{
+			Key:         key,
+			RequestHash: hash,
+			Completed:   true,
+			StatusCode:  status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(window),
+		})
+	}
+}
+
+// acceptsReplay reports whether the Accept header of the request allows a recorded response of the given Content-Type
+func acceptsReplay(c *gin.Context, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return true
+	}
+	return c.NegotiateFormat(mediaType) != ""
+}
+
+// requestHash fingerprints a request body. JSON bodies are compared by value,
+// so retries that only differ in whitespace or key order count as the same request.
+func requestHash(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err == nil {
+		if canonical, err := json.Marshal(value); err == nil {
+			body = canonical
+		}
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// responseRecorder copies everything written to the response so it can be stored for replay
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/internal/ports/repository"
+	netHttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Page size limits for receipt listings.
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// ListReceiptsHandler manages HTTP requests for listing and searching receipts.
+type ListReceiptsHandler struct {
+	ReceiptService internalHttp.ReceiptService
+}
+
+// NewListReceiptsHandler
+//
+// Parameters:
+//   - service: The ReceiptService responsible for querying receipts.
+//
+// Returns:
+//   - A new instance of ListReceiptsHandler with the provided ReceiptService.
+func NewListReceiptsHandler(service internalHttp.ReceiptService) *ListReceiptsHandler {
+	return &ListReceiptsHandler{ReceiptService: service}
+}
+
+// ListReceipts
+//
+// Supported query parameters: retailer, purchaseDateFrom, purchaseDateTo (YYYY-MM-DD), minPoints, maxPoints,
+// minTotal, maxTotal, sort (purchaseDate, retailer, points or total; prefix with "-" for descending),
+// limit (1-100, default 20) and cursor (the nextCursor of the previous page).
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and a page of receipts, a 400 Bad Request if a query
+//     parameter is invalid, or a 500 Internal Server Error if the listing fails.
+func (h *ListReceiptsHandler) ListReceipts(c *gin.Context) {
+	query, err := parseReceiptQuery(c)
+	if err != nil {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid query parameter", "details": err.Error()})
+		return
+	}
+
+	page, err := h.ReceiptService.ListReceipts(c.Request.Context(), query)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid query parameter", "details": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	body := response.ListReceiptsResponse{
+		Receipts:   make([]response.ReceiptSummary, len(page.Receipts)),
+		NextCursor: page.NextCursor,
+	}
+	for i, receipt := range page.Receipts {
+		body.Receipts[i] = response.ReceiptSummary{
+			ID:           receipt.ID,
+			Retailer:     receipt.Retailer,
+			PurchaseDate: receipt.PurchaseDate,
+			PurchaseTime: receipt.PurchaseTime,
+			Total:        receipt.Total,
+			Points:       receipt.Points,
+			ItemCount:    len(receipt.Items),
+		}
+	}
+
+	c.JSON(netHttp.StatusOK, body)
+}
+
+// parseReceiptQuery converts the request's query parameters into a ReceiptQuery
+func parseReceiptQuery(c *gin.Context) (repository.ReceiptQuery, error) {
+	query := repository.ReceiptQuery{
+		Retailer: c.Query("retailer"),
+		Cursor:   c.Query("cursor"),
+		Limit:    defaultListLimit,
+	}
+
+	for param, target := range map[string]*string{
+		"purchaseDateFrom": &query.PurchaseDateFrom,
+		"purchaseDateTo":   &query.PurchaseDateTo,
+	} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return query, fmt.Errorf("%s must be a date in YYYY-MM-DD format", param)
+		}
+		*target = value
+	}
+
+	for param, target := range map[string]**int{
+		"minPoints": &query.MinPoints,
+		"maxPoints": &query.MaxPoints,
+	} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		points, err := strconv.Atoi(value)
+		if err != nil {
+			return query, fmt.Errorf("%s must be an integer", param)
+		}
+		*target = &points
+	}
+
+	for param, target := range map[string]**float64{
+		"minTotal": &query.MinTotal,
+		"maxTotal": &query.MaxTotal,
+	} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		total, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return query, fmt.Errorf("%s must be a number", param)
+		}
+		*target = &total
+	}
+
+	if sortParam := c.Query("sort"); sortParam != "" {
+		query.SortDescending = strings.HasPrefix(sortParam, "-")
+		query.SortBy = strings.TrimPrefix(sortParam, "-")
+		switch query.SortBy {
+		case repository.SortByPurchaseDate, repository.SortByRetailer, repository.SortByPoints, repository.SortByTotal:
+		default:
+			return query, fmt.Errorf("sort must be one of purchaseDate, retailer, points or total")
+		}
+	}
+
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			return query, fmt.Errorf("limit must be an integer between 1 and %d", maxListLimit)
+		}
+		query.Limit = limit
+	}
+
+	return query, nil
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"encoding/json"
+	netHttp "net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+// docsPage renders the document served at /openapi.json with Swagger UI, loaded from a CDN.
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Receipt Processor API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => { window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" }); };
+  </script>
+</body>
+</html>
+`
+
+// OpenAPIHandler serves the OpenAPI document describing the HTTP API and a page for browsing it.
+type OpenAPIHandler struct {
+	Document []byte // The OpenAPI document, encoded as JSON once at startup
+}
+
+// NewOpenAPIHandler
+//
+// Parameters:
+//   - doc: The OpenAPI document to serve.
+//
+// Returns:
+//   - A new instance of OpenAPIHandler serving doc.
+//   - err: An error if the document cannot be encoded as JSON.
+func NewOpenAPIHandler(doc *openapi3.T) (*OpenAPIHandler, error) {
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &OpenAPIHandler{Document: encoded}, nil
+}
+
+// Spec
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A 200 OK status with the OpenAPI document as JSON.
+func (h *OpenAPIHandler) Spec(c *gin.Context) {
+	c.Data(netHttp.StatusOK, "application/json; charset=utf-8", h.Document)
+}
+
+// Docs
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A 200 OK status with an HTML page rendering the OpenAPI document with Swagger UI.
+func (h *OpenAPIHandler) Docs(c *gin.Context) {
+	c.Data(netHttp.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"go-receipt-processor/api"
+	"go-receipt-processor/internal/adapters/format"
+	netHttp "net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerReceiptFormats teaches the validator the receipt formats bindReceipt accepts besides JSON
+var registerReceiptFormats sync.Once
+
+// ValidationErrorWriter writes the response for a request rejected by OpenAPIValidationMiddleware and aborts it.
+type ValidationErrorWriter func(c *gin.Context, err error)
+
+// OpenAPIValidationMiddleware
+//
+// Checks each request against the operation the OpenAPI document defines for its route, so the published
+// schema is what decides whether a receipt is valid. Routes the document does not describe pass through.
+//
+// Parameters:
+//   - validator: The validator built from the OpenAPI document.
+//   - basePath: The prefix of the route group the middleware is used in, e.g. "/v1", which the document's paths do not include.
+//   - writeError: Writes the response for invalid requests, e.g. WriteValidationError.
+//
+// Returns:
+//   - A gin middleware that aborts invalid requests through writeError.
+func OpenAPIValidationMiddleware(validator *api.Validator, basePath string, writeError ValidationErrorWriter) gin.HandlerFunc {
+	registerReceiptFormats.Do(func() {
+		for _, mediaType := range format.ReceiptMediaTypes() {
+			api.RegisterBodyFormat(mediaType, func(data []byte) (any, error) {
+				return format.DecodeReceiptDocument(mediaType, data)
+			})
+		}
+	})
+
+	return func(c *gin.Context) {
+		route, ok := strings.CutPrefix(c.FullPath(), basePath)
+		if !ok || route == "" {
+			c.Next()
+			return
+		}
+
+		params := make(map[string]string, len(c.Params))
+		for _, param := range c.Params {
+			params[param.Key] = param.Value
+		}
+
+		if err := validator.ValidateRequest(c.Request, openAPIPath(route), params); err != nil {
+			writeError(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// WriteValidationError
+//
+// Parameters:
+//   - c: The Gin context of the rejected request.
+//   - err: Why the request was rejected.
+//
+// Returns:
+//   - Aborts with a 400 Bad Request as JSON, protobuf or MessagePack, listing the rejected fields in "fields"
+//     when err is an *api.ValidationError.
+//     The ValidationErrorWriter of the v1 API.
+func WriteValidationError(c *gin.Context, err error) {
+	// A client that accepts none of the receipt formats still learns why its request was rejected
+	mediaType := c.NegotiateFormat(receiptFormats...)
+	if mediaType == "" {
+		mediaType = gin.MIMEJSON
+	}
+	render(c, netHttp.StatusBadRequest, mediaType, invalidPayloadRepresentation(err))
+	c.Abort()
+}
+
+// openAPIPath converts a gin route such as "/receipt/:id/points" into its OpenAPI form "/receipt/{id}/points"
+func openAPIPath(route string) string {
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"context"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/internal/ports/repository"
+	netHttp "net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds how long a single dependency check may take.
+const readinessTimeout = 2 * time.Second
+
+// ReadinessHandler manages HTTP requests asking whether the service can accept traffic.
+type ReadinessHandler struct {
+	Checkers map[string]repository.HealthChecker // Dependencies to check, keyed by name
+}
+
+// NewReadinessHandler
+//
+// Parameters:
+//   - checkers: The dependencies whose health decides readiness, keyed by the name reported in the response.
+//
+// Returns:
+//   - A new instance of ReadinessHandler with the provided checkers.
+func NewReadinessHandler(checkers map[string]repository.HealthChecker) *ReadinessHandler {
+	return &ReadinessHandler{Checkers: checkers}
+}
+
+// Ready
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with a 200 OK status if every dependency is healthy,
+//     or a 503 Service Unavailable listing the failing dependencies.
+func (h *ReadinessHandler) Ready(c *gin.Context) {
+	status := netHttp.StatusOK
+	body := response.ReadinessResponse{Status: "ready", Checks: map[string]response.DependencyCheck{}}
+
+	for name, checker := range h.Checkers {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		stats, err := checker.CheckHealth(ctx)
+		cancel()
+
+		check := response.DependencyCheck{Healthy: err == nil, Stats: stats}
+		if err != nil {
+			check.Error = err.Error()
+			status = netHttp.StatusServiceUnavailable
+			body.Status = "unavailable"
+		}
+		body.Checks[name] = check
+	}
+
+	c.JSON(status, body)
+}
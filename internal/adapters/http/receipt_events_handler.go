@@ -0,0 +1,118 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/internal/ports/repository"
+	netHttp "net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventStreamContentType is the media type produced by ReceiptEventsHandler.
+const EventStreamContentType = "text/event-stream"
+
+// ReceiptEventsHandler manages long-lived HTTP requests that follow newly scored receipts as server-sent events.
+type ReceiptEventsHandler struct {
+	Stream    repository.EventStream
+	Heartbeat time.Duration // Interval of the comments keeping idle connections open through proxies
+}
+
+// NewReceiptEventsHandler
+//
+// Parameters:
+//   - stream: The EventStream the receipt service publishes to.
+//   - heartbeat: How often an idle connection is sent a comment line; zero disables heartbeats.
+//
+// Returns:
+//   - A new instance of ReceiptEventsHandler with the provided stream.
+func NewReceiptEventsHandler(stream repository.EventStream, heartbeat time.Duration) *ReceiptEventsHandler {
+	return &ReceiptEventsHandler{Stream: stream, Heartbeat: heartbeat}
+}
+
+// StreamReceipts
+//
+// Each scored receipt is sent as a "receipt.scored" event whose ID can be passed back in the Last-Event-ID
+// header to resume after a disconnect. Repeated "retailer" query parameters restrict the stream to those
+// retailers, ignoring case and surrounding whitespace. A client that cannot keep up is disconnected rather
+// than slowing down processing, and catches up from the replay buffer when it reconnects.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A 200 OK status with a text/event-stream body that stays open until the client disconnects.
+func (h *ReceiptEventsHandler) StreamReceipts(c *gin.Context) {
+	retailers := c.QueryArray("retailer")
+	replay, events, cancel := h.Stream.Subscribe(c.GetHeader("Last-Event-ID"))
+	defer cancel()
+
+	c.Header("Content-Type", EventStreamContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(netHttp.StatusOK)
+
+	for _, event := range replay {
+		if wantsReceiptEvent(event, retailers) {
+			writeReceiptEvent(c, event)
+		}
+	}
+	c.Writer.Flush()
+
+	var heartbeat <-chan time.Time
+	if h.Heartbeat > 0 {
+		ticker := time.NewTicker(h.Heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if wantsReceiptEvent(event, retailers) {
+				writeReceiptEvent(c, event)
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// wantsReceiptEvent reports whether the event is a scored receipt from one of the requested retailers,
+// comparing names the way the retailer indexes do, ignoring case and surrounding whitespace on both sides
+func wantsReceiptEvent(event domain.ReceiptEvent, retailers []string) bool {
+	if event.Type != domain.EventReceiptScored {
+		return false
+	}
+	if len(retailers) == 0 {
+		return true
+	}
+	for _, retailer := range retailers {
+		if repository.NormalizeRetailer(retailer) == repository.NormalizeRetailer(event.Retailer) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeReceiptEvent writes one event in the server-sent events format
+func writeReceiptEvent(c *gin.Context, event domain.ReceiptEvent) {
+	data, _ := json.Marshal(response.ReceiptScoredEvent{
+		ReceiptID: event.ReceiptID,
+		Retailer:  event.Retailer,
+		Points:    event.Points,
+		Timestamp: event.OccurredAt,
+	})
+	fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
@@ -1,14 +1,20 @@
 package http
 
 import (
-	"go-receipt-processor/internal/domain"
+	"errors"
+	"go-receipt-processor/api"
 	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/grpc/receiptpb"
 	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/internal/ports/repository"
 	netHttp "net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
+// queueFullRetryAfter is the Retry-After header value, in seconds, sent when the processing queue is full.
+const queueFullRetryAfter = "1"
+
 // ReceiptProcessHandler manages HTTP requests for processing receipts.
 type ReceiptProcessHandler struct {
 	ReceiptService internalHttp.ReceiptService
@@ -27,27 +33,102 @@ func NewReceiptProcessHandler(service internalHttp.ReceiptService) *ReceiptProce
 
 // ProcessReceipt
 //
+// The receipt may be sent as JSON, as XML (application/xml or text/xml), as a single receipt in the CSV
+// layout (text/csv) described in the format package, as a receiptpb.Receipt message (application/x-protobuf)
+// or as MessagePack (application/msgpack); bodies of any other type are read as JSON. The response is JSON,
+// protobuf or MessagePack as the Accept header prefers.
+// When the deployment rejects duplicates, a resubmitted receipt gets a 409 Conflict whose body carries
+// the ID of the original receipt in "originalId".
+//
 // Parameters:
 //   - c: The Gin context, which contains the HTTP request and response data.
 //
 // Returns:
-//   - A JSON response with either a 200 OK status and the receipt ID, or a 400 Bad Request if input validation fails,
-//     or a 500 Internal Server Error if processing the receipt fails.
+//   - A response with either a 200 OK status and the receipt ID, or a 400 Bad Request if input validation fails,
+//     a 409 Conflict if the receipt duplicates a stored one, a 504 Gateway Timeout if the request deadline passes, or a 500 Internal Server Error if processing the receipt fails.
 func (h *ReceiptProcessHandler) ProcessReceipt(c *gin.Context) {
-	var receipt domain.Receipt
+	receipt, err := bindReceipt(c)
+	if err != nil {
+		negotiate(c, netHttp.StatusBadRequest, receiptFormats, invalidPayloadRepresentation(err))
+		return
+	}
 
-	if err := c.ShouldBindJSON(&receipt); err != nil {
-		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+	receiptID, err := h.ReceiptService.ProcessReceipt(c.Request.Context(), receipt)
+	var duplicate *internalHttp.DuplicateReceiptError
+	if errors.As(err, &duplicate) {
+		negotiate(c, netHttp.StatusConflict, receiptFormats, duplicateRepresentation(err, duplicate.OriginalID))
+		return
+	}
+	if err != nil {
+		negotiate(c, statusForError(err), receiptFormats, errorRepresentation(err.Error()))
+		return
+	}
+
+	negotiate(c, netHttp.StatusOK, receiptFormats, representation{
+		body:  response.ReceiptProcessResponse{ID: receiptID},
+		proto: &receiptpb.ProcessReceiptResponse{Id: receiptID},
+	})
+}
+
+// EnqueueReceipt
+//
+// Used instead of ProcessReceipt in asynchronous mode: the receipt is validated and queued, and its points
+// become available from GET /receipt/:id/points once a worker has processed it.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A response with either a 202 Accepted status, the receipt ID and status "pending", or a 400 Bad Request
+//     if input validation fails, a 409 Conflict if the receipt duplicates a stored one, a 429 Too Many Requests
+//     if the queue is full, or a 500 Internal Server Error if the receipt cannot be queued.
+func (h *ReceiptProcessHandler) EnqueueReceipt(c *gin.Context) {
+	receipt, err := bindReceipt(c)
+	if err != nil {
+		negotiate(c, netHttp.StatusBadRequest, receiptFormats, invalidPayloadRepresentation(err))
 		return
 	}
 
-	receiptID, err := h.ReceiptService.ProcessReceipt(receipt)
+	job, err := h.ReceiptService.EnqueueReceipt(c.Request.Context(), receipt)
+	var duplicate *internalHttp.DuplicateReceiptError
+	if errors.As(err, &duplicate) {
+		negotiate(c, netHttp.StatusConflict, receiptFormats, duplicateRepresentation(err, duplicate.OriginalID))
+		return
+	}
+	if errors.Is(err, repository.ErrQueueFull) {
+		c.Header("Retry-After", queueFullRetryAfter)
+	}
 	if err != nil {
-		c.JSON(netHttp.StatusInternalServerError, gin.H{"error": err.Error()})
+		negotiate(c, statusForError(err), receiptFormats, errorRepresentation(err.Error()))
 		return
 	}
 
-	c.JSON(netHttp.StatusOK, response.ReceiptProcessResponse{
-		ID: receiptID,
+	negotiate(c, netHttp.StatusAccepted, receiptFormats, representation{
+		body:  response.ReceiptStatusResponse{ID: job.ID, Status: string(job.Status)},
+		proto: &receiptpb.ReceiptStatus{Id: job.ID, Status: string(job.Status)},
 	})
 }
+
+// invalidPayloadRepresentation is the body of a request whose receipt cannot be decoded or does not match the
+// Receipt schema, listing the rejected fields in "fields" when err is an *api.ValidationError
+func invalidPayloadRepresentation(err error) representation {
+	body := gin.H{"error": "Invalid request payload", "details": err.Error()}
+	message := &receiptpb.Error{Error: "Invalid request payload", Details: err.Error()}
+	var invalid *api.ValidationError
+	if errors.As(err, &invalid) {
+		body["fields"] = invalid.Fields
+		for _, field := range invalid.Fields {
+			message.Fields = append(message.Fields, &receiptpb.FieldError{Field: field.Field, Message: field.Message})
+		}
+	}
+	return representation{body: body, text: err.Error(), proto: message}
+}
+
+// duplicateRepresentation is the body of a 409 Conflict, naming the receipt the submitted one duplicates
+func duplicateRepresentation(err error, originalID string) representation {
+	return representation{
+		body:  gin.H{"error": err.Error(), "originalId": originalID},
+		text:  err.Error(),
+		proto: &receiptpb.Error{Error: err.Error(), OriginalId: originalID},
+	}
+}
@@ -0,0 +1,238 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"go-receipt-processor/api"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/pkg/utils"
+	netHttp "net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReceiptV2Handler manages the v2 receipt endpoints, which report how a receipt's points were earned
+// and wrap every error in a response.ErrorResponse.
+type ReceiptV2Handler struct {
+	ReceiptService   internalHttp.ReceiptService
+	PointsCalculator internalHttp.PointsCalculator // Explains the points of stored receipts rule by rule
+}
+
+// NewReceiptV2Handler
+//
+// Parameters:
+//   - service: The ReceiptService responsible for processing and fetching receipts.
+//   - calculator: The PointsCalculator used to break a receipt's points down by rule.
+//
+// Returns:
+//   - A new instance of ReceiptV2Handler with the provided ReceiptService and PointsCalculator.
+func NewReceiptV2Handler(service internalHttp.ReceiptService, calculator internalHttp.PointsCalculator) *ReceiptV2Handler {
+	return &ReceiptV2Handler{ReceiptService: service, PointsCalculator: calculator}
+}
+
+// ProcessReceipt
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 201 Created status, the scored receipt with its breakdown and metadata, and
+//     a Location header for its points, or an error envelope with the status v1 would answer with.
+func (h *ReceiptV2Handler) ProcessReceipt(c *gin.Context) {
+	receipt, err := bindReceipt(c)
+	if err != nil {
+		WriteV2Error(c, netHttp.StatusBadRequest, err)
+		return
+	}
+
+	receiptID, err := h.ReceiptService.ProcessReceipt(c.Request.Context(), receipt)
+	if err != nil {
+		WriteV2Error(c, statusForError(err), err)
+		return
+	}
+
+	stored, err := h.ReceiptService.GetReceipt(c.Request.Context(), receiptID)
+	if err != nil {
+		WriteV2Error(c, statusForError(err), err)
+		return
+	}
+	body, err := h.newReceiptV2Response(c.Request.Context(), stored)
+	if err != nil {
+		WriteV2Error(c, netHttp.StatusInternalServerError, err)
+		return
+	}
+
+	c.Header("Location", pointsLocation(c, receiptID))
+	c.JSON(netHttp.StatusCreated, body)
+}
+
+// EnqueueReceipt
+//
+// Used instead of ProcessReceipt in asynchronous mode.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 202 Accepted status, the receipt ID and status "pending", and a Location header
+//     to poll for its points, or an error envelope with the status v1 would answer with.
+func (h *ReceiptV2Handler) EnqueueReceipt(c *gin.Context) {
+	receipt, err := bindReceipt(c)
+	if err != nil {
+		WriteV2Error(c, netHttp.StatusBadRequest, err)
+		return
+	}
+
+	job, err := h.ReceiptService.EnqueueReceipt(c.Request.Context(), receipt)
+	if errors.Is(err, repository.ErrQueueFull) {
+		c.Header("Retry-After", queueFullRetryAfter)
+	}
+	if err != nil {
+		WriteV2Error(c, statusForError(err), err)
+		return
+	}
+
+	c.Header("Location", pointsLocation(c, job.ID))
+	c.JSON(netHttp.StatusAccepted, response.ReceiptStatusResponse{ID: job.ID, Status: string(job.Status)})
+}
+
+// GetPoints
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and the receipt's points, breakdown and metadata, a 202 Accepted
+//     status and the processing status while it is queued, or an error envelope with the status v1 would answer with.
+func (h *ReceiptV2Handler) GetPoints(c *gin.Context) {
+	id := c.Param("id")
+
+	// GetPoints reports receipts that are still queued, which GetReceipt does not
+	_, err := h.ReceiptService.GetPoints(c.Request.Context(), id)
+	var pending *internalHttp.ReceiptPendingError
+	if errors.As(err, &pending) {
+		c.JSON(netHttp.StatusAccepted, response.ReceiptStatusResponse{ID: id, Status: string(pending.Status)})
+		return
+	}
+	if err != nil {
+		WriteV2Error(c, statusForError(err), err)
+		return
+	}
+
+	receipt, err := h.ReceiptService.GetReceipt(c.Request.Context(), id)
+	if err != nil {
+		WriteV2Error(c, statusForError(err), err)
+		return
+	}
+	body, err := h.newReceiptV2Response(c.Request.Context(), receipt)
+	if err != nil {
+		WriteV2Error(c, netHttp.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(netHttp.StatusOK, body)
+}
+
+// newReceiptV2Response maps a stored receipt onto its v2 response DTO, breaking its points down by rule
+func (h *ReceiptV2Handler) newReceiptV2Response(ctx context.Context, receipt domain.Receipt) (response.ReceiptV2Response, error) {
+	breakdown, err := h.PointsCalculator.Breakdown(ctx, receipt)
+	if err != nil {
+		return response.ReceiptV2Response{}, err
+	}
+
+	body := response.ReceiptV2Response{
+		ID:        receipt.ID,
+		Points:    receipt.Points,
+		Breakdown: make([]response.RulePoints, len(breakdown)),
+		Metadata: response.ReceiptMetadata{
+			ScoringVersion: receipt.ScoringVersion,
+			Revision:       receipt.Revision,
+			Fingerprint:    receipt.Fingerprint,
+			DuplicateOf:    receipt.DuplicateOf,
+		},
+	}
+	for i, rule := range breakdown {
+		body.Breakdown[i] = response.RulePoints{Rule: rule.Rule, Points: rule.Points}
+	}
+	if !receipt.ReceivedAt.IsZero() {
+		receivedAt := receipt.ReceivedAt
+		body.Metadata.ReceivedAt = &receivedAt
+	}
+	return body, nil
+}
+
+// pointsLocation builds the URL of a receipt's points next to the route that accepted it, e.g. /v2/receipt/{id}/points
+func pointsLocation(c *gin.Context, id string) string {
+	return strings.TrimSuffix(c.FullPath(), "/receipt/process") + "/receipt/" + id + "/points"
+}
+
+// WriteV2Error
+//
+// Parameters:
+//   - c: The Gin context of the failed request.
+//   - status: The HTTP status to answer with.
+//   - err: Why the request failed. Rejected fields of an *api.ValidationError and the original receipt of
+//     an *internalHttp.DuplicateReceiptError are reported in the envelope.
+//
+// Returns:
+//   - Aborts with the status and a response.ErrorResponse.
+func WriteV2Error(c *gin.Context, status int, err error) {
+	body := response.ErrorBody{
+		Code:      errorCode(status),
+		Message:   err.Error(),
+		RequestID: utils.RequestIDFrom(c.Request.Context()),
+	}
+
+	var invalid *api.ValidationError
+	if errors.As(err, &invalid) {
+		body.Message = "Invalid request payload"
+		body.Fields = make([]response.FieldError, len(invalid.Fields))
+		for i, field := range invalid.Fields {
+			body.Fields[i] = response.FieldError{Field: field.Field, Message: field.Message}
+		}
+	}
+	var duplicate *internalHttp.DuplicateReceiptError
+	if errors.As(err, &duplicate) {
+		body.OriginalID = duplicate.OriginalID
+	}
+
+	c.AbortWithStatusJSON(status, response.ErrorResponse{Error: body})
+}
+
+// WriteV2ValidationError
+//
+// Parameters:
+//   - c: The Gin context of the rejected request.
+//   - err: Why the request was rejected.
+//
+// Returns:
+//   - Aborts with a 400 Bad Request and a response.ErrorResponse listing the rejected fields.
+//     The ValidationErrorWriter of the v2 API.
+func WriteV2ValidationError(c *gin.Context, err error) {
+	WriteV2Error(c, netHttp.StatusBadRequest, err)
+}
+
+// errorCode names the reason behind an error status in the v2 error envelope
+func errorCode(status int) string {
+	switch status {
+	case netHttp.StatusBadRequest:
+		return "invalid_request"
+	case netHttp.StatusNotFound:
+		return "not_found"
+	case netHttp.StatusConflict:
+		return "duplicate_receipt"
+	case netHttp.StatusGone:
+		return "deleted"
+	case netHttp.StatusPreconditionFailed:
+		return "revision_conflict"
+	case netHttp.StatusTooManyRequests:
+		return "queue_full"
+	case netHttp.StatusGatewayTimeout:
+		return "timeout"
+	default:
+		return "internal"
+	}
+}
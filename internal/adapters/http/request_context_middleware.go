@@ -0,0 +1,48 @@
+package http
+
+import (
+	"context"
+	"go-receipt-processor/pkg/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Headers read and written by RequestContextMiddleware.
+const (
+	RequestIDHeader = "X-Request-ID"
+	TenantIDHeader  = "X-Tenant-ID"
+)
+
+// RequestContextMiddleware
+//
+// Parameters:
+//   - timeout: The deadline applied to every request's context; zero or negative leaves requests without a deadline.
+//
+// Returns:
+//   - A gin middleware that stores the request ID (taken from X-Request-ID or generated) and tenant ID
+//     (from X-Tenant-ID) in the request context, echoes the request ID in the response, and applies the deadline.
+func RequestContextMiddleware(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := utils.WithRequestID(c.Request.Context(), requestID)
+		if tenantID := c.GetHeader(TenantIDHeader); tenantID != "" {
+			ctx = utils.WithTenantID(ctx, tenantID)
+		}
+
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	"io"
+	"mime"
+	netHttp "net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NDJSONContentType is the media type accepted and produced by StreamProcessHandler.
+const NDJSONContentType = "application/x-ndjson"
+
+// maxStreamLineBytes bounds a single receipt line so one malformed line cannot exhaust memory.
+const maxStreamLineBytes = 1 << 20
+
+// StreamProcessHandler manages HTTP requests that upload receipts as newline-delimited JSON.
+//
+// Lines are decoded, processed and answered one at a time while the upload is still arriving,
+// so memory use does not grow with the size of the upload.
+type StreamProcessHandler struct {
+	ReceiptService internalHttp.ReceiptService
+	LineTimeout    time.Duration // Deadline applied to processing each line; zero disables it
+}
+
+// NewStreamProcessHandler
+//
+// Parameters:
+//   - service: The ReceiptService responsible for scoring and storing the receipts.
+//   - lineTimeout: The deadline applied to processing each line, since the upload as a whole may take much longer.
+//
+// Returns:
+//   - A new instance of StreamProcessHandler with the provided ReceiptService.
+func NewStreamProcessHandler(service internalHttp.ReceiptService, lineTimeout time.Duration) *StreamProcessHandler {
+	return &StreamProcessHandler{ReceiptService: service, LineTimeout: lineTimeout}
+}
+
+// ProcessStream
+//
+// The body holds one receipt per line, each in the same format accepted by ProcessReceipt; blank lines are skipped.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A 415 Unsupported Media Type if the body is not application/x-ndjson. Otherwise a 200 OK status
+//     whose NDJSON body carries one result per receipt line, written as soon as that line is processed.
+//     Failed lines report their line number and the status they would have received from ProcessReceipt.
+func (h *StreamProcessHandler) ProcessStream(c *gin.Context) {
+	if mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type")); mediaType != NDJSONContentType {
+		c.JSON(netHttp.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be " + NDJSONContentType})
+		return
+	}
+
+	// HTTP/1.x servers stop reading the request body once the response starts unless full duplex is enabled.
+	// Writers that do not support it (such as test recorders) already allow both.
+	controller := netHttp.NewResponseController(c.Writer)
+	_ = controller.EnableFullDuplex()
+
+	c.Header("Content-Type", NDJSONContentType)
+	c.Status(netHttp.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	reader := bufio.NewReader(c.Request.Body)
+	ctx := c.Request.Context()
+
+	for lineNumber := 1; ctx.Err() == nil; lineNumber++ {
+		line, err := readLine(reader)
+		if errors.Is(err, io.EOF) {
+			return
+		}
+
+		result := response.StreamLineResult{Line: lineNumber}
+		switch {
+		case errors.Is(err, errLineTooLong):
+			result.Error = &response.BatchError{Status: netHttp.StatusRequestEntityTooLarge, Message: err.Error()}
+		case err != nil:
+			// The upload itself failed, so there is nothing more to read
+			result.Error = &response.BatchError{Status: netHttp.StatusBadRequest, Message: err.Error()}
+			encoder.Encode(result)
+			return
+		case len(bytes.TrimSpace(line)) == 0:
+			continue
+		default:
+			h.processLine(ctx, line, &result)
+		}
+
+		if encoder.Encode(result) != nil {
+			// The client has gone away
+			return
+		}
+		controller.Flush()
+	}
+}
+
+// processLine decodes, validates and processes a single receipt line, recording the outcome in result
+func (h *StreamProcessHandler) processLine(ctx context.Context, line []byte, result *response.StreamLineResult) {
+	receipt, err := decodeReceipt(line)
+	if err != nil {
+		result.Error = &response.BatchError{Status: netHttp.StatusBadRequest, Message: err.Error()}
+		return
+	}
+
+	if h.LineTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.LineTimeout)
+		defer cancel()
+	}
+
+	receiptID, err := h.ReceiptService.ProcessReceipt(ctx, receipt)
+	if err != nil {
+		result.Error = &response.BatchError{Status: statusForError(err), Message: err.Error()}
+		return
+	}
+	result.ID = receiptID
+}
+
+// errLineTooLong is reported for lines longer than maxStreamLineBytes; the rest of the line is discarded.
+var errLineTooLong = fmt.Errorf("line exceeds %d bytes", maxStreamLineBytes)
+
+// readLine returns the next line without its terminator. Lines longer than maxStreamLineBytes are
+// consumed and reported as errLineTooLong, so reading can continue with the following line.
+func readLine(reader *bufio.Reader) ([]byte, error) {
+	var line []byte
+	tooLong := false
+	for {
+		chunk, isPrefix, err := reader.ReadLine()
+		if err != nil {
+			if tooLong {
+				return nil, errLineTooLong
+			}
+			if len(line) > 0 && errors.Is(err, io.EOF) {
+				return line, nil
+			}
+			return nil, err
+		}
+		if !tooLong {
+			line = append(line, chunk...)
+			if len(line) > maxStreamLineBytes {
+				tooLong, line = true, nil
+			}
+		}
+		if !isPrefix {
+			if tooLong {
+				return nil, errLineTooLong
+			}
+			return line, nil
+		}
+	}
+}
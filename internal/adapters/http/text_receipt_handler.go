@@ -0,0 +1,112 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"go-receipt-processor/api"
+	"go-receipt-processor/internal/adapters/plaintext"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	"io"
+	netHttp "net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxTextReceiptBytes bounds the text of a printed receipt, as maxStreamLineBytes bounds a receipt sent as an NDJSON line.
+const maxTextReceiptBytes = maxStreamLineBytes
+
+// TextReceiptHandler manages HTTP requests for processing the plain text of printed receipts.
+type TextReceiptHandler struct {
+	ReceiptService internalHttp.ReceiptService
+}
+
+// NewTextReceiptHandler
+//
+// Parameters:
+//   - service: The ReceiptService responsible for scoring and storing the extracted receipts.
+//
+// Returns:
+//   - A new instance of TextReceiptHandler with the provided ReceiptService.
+func NewTextReceiptHandler(service internalHttp.ReceiptService) *TextReceiptHandler {
+	return &TextReceiptHandler{ReceiptService: service}
+}
+
+// ProcessText
+//
+// The body is the text of a printed receipt, e.g. as produced by OCR. Its retailer, date, time, items and
+// total are extracted by plaintext.Parse and, if they make a valid receipt, it is scored and stored like
+// one sent to POST /receipt/process. Receipts are always processed synchronously.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with the extracted receipt and the confidence in each field, and either a 200 OK status and
+//     the receipt ID, a 400 Bad Request if the body is empty, a 413 Request Entity Too Large if it is longer than
+//     1 MiB, a 422 Unprocessable Entity listing the fields that could not be extracted, a 409 Conflict if the
+//     receipt duplicates a stored one, or the status POST /receipt/process would answer with if processing fails.
+func (h *TextReceiptHandler) ProcessText(c *gin.Context) {
+	data, err := io.ReadAll(netHttp.MaxBytesReader(c.Writer, c.Request.Body, maxTextReceiptBytes))
+	var tooLarge *netHttp.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(netHttp.StatusRequestEntityTooLarge, gin.H{
+			"error":   "Invalid request payload",
+			"details": fmt.Sprintf("receipt text exceeds %d bytes", tooLarge.Limit),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": "receipt text is empty"})
+		return
+	}
+
+	result := plaintext.Parse(string(data))
+	status, body := submitParsedReceipt(c, h.ReceiptService, result.Receipt)
+	body.Confidence = result.Confidence
+	c.JSON(status, body)
+}
+
+// submitParsedReceipt validates a receipt extracted from a document and, if it is valid, processes it.
+// It returns the status to answer with and the response describing the outcome.
+func submitParsedReceipt(c *gin.Context, service internalHttp.ReceiptService, receipt domain.Receipt) (int, response.ParsedReceiptResponse) {
+	body := response.ParsedReceiptResponse{Receipt: response.ParsedReceipt{
+		Retailer:     receipt.Retailer,
+		PurchaseDate: receipt.PurchaseDate,
+		PurchaseTime: receipt.PurchaseTime,
+		Items:        make([]response.ReceiptItem, len(receipt.Items)),
+		Total:        receipt.Total,
+	}}
+	for i, item := range receipt.Items {
+		body.Receipt.Items[i] = response.ReceiptItem{ShortDescription: item.ShortDescription, Price: item.Price}
+	}
+
+	if err := api.ValidateReceipt(receipt); err != nil {
+		body.Error = "Could not extract a valid receipt"
+		var invalid *api.ValidationError
+		if errors.As(err, &invalid) {
+			for _, field := range invalid.Fields {
+				body.Fields = append(body.Fields, response.FieldError{Field: field.Field, Message: field.Message})
+			}
+		}
+		return netHttp.StatusUnprocessableEntity, body
+	}
+
+	id, err := service.ProcessReceipt(c.Request.Context(), receipt)
+	var duplicate *internalHttp.DuplicateReceiptError
+	if errors.As(err, &duplicate) {
+		body.OriginalID = duplicate.OriginalID
+	}
+	if err != nil {
+		body.Error = err.Error()
+		return statusForError(err), body
+	}
+	body.ID = id
+	return netHttp.StatusOK, body
+}
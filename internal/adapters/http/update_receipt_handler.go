@@ -0,0 +1,178 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/pkg/utils"
+	"io"
+	netHttp "net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateReceiptHandler manages HTTP requests for correcting stored receipts and reading their history.
+//
+// Corrections use optimistic concurrency: GET /receipt/:id returns the receipt's revision as an ETag,
+// and PUT and PATCH must send it back in If-Match so that concurrent edits cannot overwrite each other.
+type UpdateReceiptHandler struct {
+	ReceiptService internalHttp.ReceiptService
+}
+
+// NewUpdateReceiptHandler
+//
+// Parameters:
+//   - service: The ReceiptService responsible for rescoring and storing corrected receipts.
+//
+// Returns:
+//   - A new instance of UpdateReceiptHandler with the provided ReceiptService.
+func NewUpdateReceiptHandler(service internalHttp.ReceiptService) *UpdateReceiptHandler {
+	return &UpdateReceiptHandler{ReceiptService: service}
+}
+
+// PutReceipt
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and the rescored receipt, a 400 Bad Request listing the rejected
+//     fields if the body does not match the OpenAPI Receipt schema,
+//     a 404 Not Found or 410 Gone if the receipt does not exist, a 428 Precondition Required if If-Match is missing,
+//     a 412 Precondition Failed if it does not match the current revision, or a 500 Internal Server Error.
+func (h *UpdateReceiptHandler) PutReceipt(c *gin.Context) {
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+	receipt, err := decodeReceipt(data)
+	if err != nil {
+		WriteValidationError(c, err)
+		return
+	}
+
+	current, ok := h.currentRevision(c)
+	if !ok {
+		return
+	}
+
+	h.update(c, receipt, current)
+}
+
+// PatchReceipt
+//
+// The body is a JSON Merge Patch (RFC 7386) applied to the current receipt, e.g. {"total": "35.35"}.
+// The patched receipt is validated exactly as a full replacement would be.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - The same responses as PutReceipt.
+func (h *UpdateReceiptHandler) PatchReceipt(c *gin.Context) {
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil || !json.Valid(patch) {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": "body must be a JSON merge patch"})
+		return
+	}
+
+	current, ok := h.currentRevision(c)
+	if !ok {
+		return
+	}
+
+	document, err := json.Marshal(current)
+	if err != nil {
+		c.JSON(netHttp.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	patched, err := utils.MergePatch(document, patch)
+	if err != nil {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	receipt, err := decodeReceipt(patched)
+	if err != nil {
+		WriteValidationError(c, err)
+		return
+	}
+
+	h.update(c, receipt, current)
+}
+
+// GetRevisions
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and the earlier revisions of the receipt, oldest first,
+//     a 404 Not Found or 410 Gone if the receipt does not exist, or a 500 Internal Server Error.
+func (h *UpdateReceiptHandler) GetRevisions(c *gin.Context) {
+	revisions, err := h.ReceiptService.ListRevisions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	body := response.ReceiptRevisionsResponse{Revisions: make([]response.GetReceiptResponse, len(revisions))}
+	for i, revision := range revisions {
+		body.Revisions[i] = newGetReceiptResponse(revision)
+	}
+	c.JSON(netHttp.StatusOK, body)
+}
+
+// currentRevision loads the receipt being corrected and checks the request's If-Match header against it,
+// writing the error response and returning false if the update must not go ahead
+func (h *UpdateReceiptHandler) currentRevision(c *gin.Context) (domain.Receipt, bool) {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		c.JSON(netHttp.StatusPreconditionRequired, gin.H{"error": "If-Match header is required"})
+		return domain.Receipt{}, false
+	}
+
+	current, err := h.ReceiptService.GetReceipt(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return domain.Receipt{}, false
+	}
+
+	if !etagMatches(ifMatch, receiptETag(current)) {
+		c.JSON(netHttp.StatusPreconditionFailed, gin.H{"error": "receipt has been modified by another request"})
+		return domain.Receipt{}, false
+	}
+	return current, true
+}
+
+// update rescores and stores the corrected receipt, replying with the new revision and its ETag
+func (h *UpdateReceiptHandler) update(c *gin.Context, receipt domain.Receipt, current domain.Receipt) {
+	updated, err := h.ReceiptService.UpdateReceipt(c.Request.Context(), current.ID, receipt, current.Revision)
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("ETag", receiptETag(updated))
+	c.JSON(netHttp.StatusOK, newGetReceiptResponse(updated))
+}
+
+// receiptETag returns the strong entity tag identifying a receipt's current revision
+func receiptETag(receipt domain.Receipt) string {
+	return fmt.Sprintf(`"%d"`, receipt.Revision)
+}
+
+// etagMatches reports whether an If-Match header value ("*" or a comma separated list of tags) matches etag
+func etagMatches(ifMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
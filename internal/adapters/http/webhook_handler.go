@@ -0,0 +1,179 @@
+package http
+
+import (
+	"fmt"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/internal/ports/repository"
+	netHttp "net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler manages HTTP requests for webhook subscriptions and their delivery logs.
+type WebhookHandler struct {
+	Store repository.WebhookStore
+}
+
+// createWebhookRequest is the body of a subscription request.
+type createWebhookRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events" binding:"required,min=1"`
+	Secret string   `json:"secret" binding:"required"`
+}
+
+// NewWebhookHandler
+//
+// Parameters:
+//   - store: The WebhookStore holding subscriptions and their delivery logs.
+//
+// Returns:
+//   - A new instance of WebhookHandler with the provided store.
+func NewWebhookHandler(store repository.WebhookStore) *WebhookHandler {
+	return &WebhookHandler{Store: store}
+}
+
+// CreateSubscription
+//
+// The body names the receiver's http(s) URL, the event types wanted and the secret deliveries are signed with.
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 201 Created status and the subscription, a 400 Bad Request if the URL,
+//     events or secret are missing or invalid, or a 500 Internal Server Error if an error occurs.
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var req createWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+	if err := validateWebhookRequest(req); err != nil {
+		c.JSON(netHttp.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	subscription, err := h.Store.CreateSubscription(c.Request.Context(), domain.WebhookSubscription{
+		URL:       req.URL,
+		Events:    req.Events,
+		Secret:    req.Secret,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(netHttp.StatusCreated, newWebhookSubscriptionResponse(subscription))
+}
+
+// ListSubscriptions
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and every subscription, or a 500 Internal Server Error if an error occurs.
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	subscriptions, err := h.Store.Subscriptions(c.Request.Context())
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	body := response.WebhookSubscriptionsResponse{
+		Subscriptions: make([]response.WebhookSubscriptionResponse, len(subscriptions)),
+		Count:         len(subscriptions),
+	}
+	for i, subscription := range subscriptions {
+		body.Subscriptions[i] = newWebhookSubscriptionResponse(subscription)
+	}
+	c.JSON(netHttp.StatusOK, body)
+}
+
+// GetSubscription
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and the subscription, a 404 Not Found if there is none with the ID,
+//     or a 500 Internal Server Error if an error occurs.
+func (h *WebhookHandler) GetSubscription(c *gin.Context) {
+	subscription, err := h.Store.Subscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(netHttp.StatusOK, newWebhookSubscriptionResponse(subscription))
+}
+
+// DeleteSubscription
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A 204 No Content status, a 404 Not Found if there is no subscription with the ID,
+//     or a 500 Internal Server Error if an error occurs.
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	if err := h.Store.DeleteSubscription(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(netHttp.StatusNoContent)
+}
+
+// ListDeliveries
+//
+// Parameters:
+//   - c: The Gin context, which contains the HTTP request and response data.
+//
+// Returns:
+//   - A JSON response with either a 200 OK status and the subscription's recent delivery attempts,
+//     a 404 Not Found if there is no subscription with the ID, or a 500 Internal Server Error if an error occurs.
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	deliveries, err := h.Store.Deliveries(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(statusForError(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(netHttp.StatusOK, response.WebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		Count:      len(deliveries),
+	})
+}
+
+// validateWebhookRequest checks the receiver URL is absolute http(s) and every event type is known
+func validateWebhookRequest(req createWebhookRequest) error {
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	for _, event := range req.Events {
+		known := false
+		for _, eventType := range domain.EventTypes {
+			known = known || event == eventType
+		}
+		if !known {
+			return fmt.Errorf("unknown event type '%s'", event)
+		}
+	}
+	return nil
+}
+
+// newWebhookSubscriptionResponse maps a subscription onto its response DTO, leaving out the secret
+func newWebhookSubscriptionResponse(subscription domain.WebhookSubscription) response.WebhookSubscriptionResponse {
+	return response.WebhookSubscriptionResponse{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    subscription.Events,
+		CreatedAt: subscription.CreatedAt,
+	}
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"sort"
+	"sync"
+)
+
+// DeadLetterStoreImpl keeps dead letters in an in-memory map, keyed by job ID.
+type DeadLetterStoreImpl struct {
+	mu      sync.RWMutex
+	letters map[string]domain.DeadLetter
+}
+
+// NewDeadLetterStore
+//
+// Returns:
+//   - A new, empty instance of DeadLetterStoreImpl.
+func NewDeadLetterStore() *DeadLetterStoreImpl {
+	return &DeadLetterStoreImpl{
+		letters: make(map[string]domain.DeadLetter),
+	}
+}
+
+// Add stores a dead letter, replacing any earlier one with the same ID
+func (s *DeadLetterStoreImpl) Add(ctx context.Context, letter domain.DeadLetter) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.letters[letter.ID] = letter
+	return nil
+}
+
+// List returns every dead letter, oldest failure first
+func (s *DeadLetterStoreImpl) List(ctx context.Context) ([]domain.DeadLetter, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	letters := make([]domain.DeadLetter, 0, len(s.letters))
+	for _, letter := range s.letters {
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool {
+		if !letters[i].FailedAt.Equal(letters[j].FailedAt) {
+			return letters[i].FailedAt.Before(letters[j].FailedAt)
+		}
+		return letters[i].ID < letters[j].ID
+	})
+	return letters, nil
+}
+
+// Get returns a dead letter by ID
+func (s *DeadLetterStoreImpl) Get(ctx context.Context, id string) (domain.DeadLetter, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.DeadLetter{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	letter, ok := s.letters[id]
+	if !ok {
+		return domain.DeadLetter{}, repository.ErrDeadLetterNotFound
+	}
+	return letter, nil
+}
+
+// Remove deletes a dead letter by ID
+func (s *DeadLetterStoreImpl) Remove(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.letters[id]; !ok {
+		return repository.ErrDeadLetterNotFound
+	}
+	delete(s.letters, id)
+	return nil
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"go-receipt-processor/internal/domain"
+	"sync"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before it is disconnected.
+const subscriberBuffer = 64
+
+// EventStreamImpl is an in-memory EventStream. It keeps the most recent events in a ring buffer
+// and gives each subscriber its own buffered channel, so a slow subscriber never delays Publish:
+// once its channel is full it is disconnected and can resume from the replay buffer.
+type EventStreamImpl struct {
+	mu          sync.Mutex
+	buffer      []domain.ReceiptEvent // Ring of the most recent events
+	next        int                   // Position in buffer the next event is written to
+	full        bool                  // Whether buffer has wrapped around
+	subscribers map[chan domain.ReceiptEvent]struct{}
+}
+
+// NewEventStream
+//
+// Parameters:
+//   - replaySize: How many of the most recent events are kept for subscribers that reconnect; at least one.
+//
+// Returns:
+//   - A new instance of EventStreamImpl with no subscribers.
+func NewEventStream(replaySize int) *EventStreamImpl {
+	if replaySize < 1 {
+		replaySize = 1
+	}
+	return &EventStreamImpl{
+		buffer:      make([]domain.ReceiptEvent, replaySize),
+		subscribers: make(map[chan domain.ReceiptEvent]struct{}),
+	}
+}
+
+// Publish buffers an event and passes it to every subscriber, disconnecting those whose channel is full
+func (s *EventStreamImpl) Publish(event domain.ReceiptEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buffer[s.next] = event
+	s.next = (s.next + 1) % len(s.buffer)
+	s.full = s.full || s.next == 0
+
+	for subscriber := range s.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			delete(s.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// Subscribe registers a subscriber and returns the buffered events published after lastEventID
+func (s *EventStreamImpl) Subscribe(lastEventID string) ([]domain.ReceiptEvent, <-chan domain.ReceiptEvent, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var replay []domain.ReceiptEvent
+	if lastEventID != "" {
+		buffered := s.buffered()
+		replay = buffered
+		for i, event := range buffered {
+			if event.ID == lastEventID {
+				replay = buffered[i+1:]
+				break
+			}
+		}
+	}
+
+	subscriber := make(chan domain.ReceiptEvent, subscriberBuffer)
+	s.subscribers[subscriber] = struct{}{}
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if _, ok := s.subscribers[subscriber]; ok {
+				delete(s.subscribers, subscriber)
+				close(subscriber)
+			}
+		})
+	}
+	return replay, subscriber, cancel
+}
+
+// buffered returns a copy of the buffered events, oldest first; callers must hold mu
+func (s *EventStreamImpl) buffered() []domain.ReceiptEvent {
+	if !s.full {
+		return append([]domain.ReceiptEvent{}, s.buffer[:s.next]...)
+	}
+	return append(append([]domain.ReceiptEvent{}, s.buffer[s.next:]...), s.buffer[:s.next]...)
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"go-receipt-processor/internal/domain"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often Reserve also drops every expired record, bounding memory use.
+const sweepInterval = time.Minute
+
+// IdempotencyStoreImpl keeps idempotency records in an in-memory map, keyed by idempotency key.
+type IdempotencyStoreImpl struct {
+	mu        sync.Mutex
+	records   map[string]domain.IdempotencyRecord
+	lastSweep time.Time
+}
+
+// NewIdempotencyStore
+//
+// Returns:
+//   - A new, empty instance of IdempotencyStoreImpl.
+func NewIdempotencyStore() *IdempotencyStoreImpl {
+	return &IdempotencyStoreImpl{
+		records: make(map[string]domain.IdempotencyRecord),
+	}
+}
+
+// Reserve claims the record's key unless an unexpired record already holds it
+func (s *IdempotencyStoreImpl) Reserve(ctx context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for key, existing := range s.records {
+			if !now.Before(existing.ExpiresAt) {
+				delete(s.records, key)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	if existing, ok := s.records[record.Key]; ok && now.Before(existing.ExpiresAt) {
+		return existing, false, nil
+	}
+
+	record.Completed = false
+	s.records[record.Key] = record
+	return record, true, nil
+}
+
+// Complete stores the response of a reserved request
+func (s *IdempotencyStoreImpl) Complete(ctx context.Context, record domain.IdempotencyRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record.Completed = true
+	s.records[record.Key] = record
+	return nil
+}
+
+// Release drops a reservation so the key can be used again
+func (s *IdempotencyStoreImpl) Release(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
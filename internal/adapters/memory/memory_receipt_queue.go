@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"sync"
+	"time"
+)
+
+// ReceiptQueueImpl is a bounded in-memory ReceiptQueue. Pending jobs wait in a buffered channel
+// whose size is the queue's capacity, and every tracked job is kept in a map keyed by ID.
+type ReceiptQueueImpl struct {
+	pending chan string
+	mu      sync.Mutex
+	jobs    map[string]domain.ReceiptJob
+}
+
+// NewReceiptQueue
+//
+// Parameters:
+//   - capacity: The most jobs that may wait in the queue at once.
+//
+// Returns:
+//   - A new, empty instance of ReceiptQueueImpl.
+func NewReceiptQueue(capacity int) *ReceiptQueueImpl {
+	return &ReceiptQueueImpl{
+		pending: make(chan string, capacity),
+		jobs:    make(map[string]domain.ReceiptJob),
+	}
+}
+
+// Enqueue adds a pending job, or returns ErrQueueFull if capacity jobs are already waiting
+func (q *ReceiptQueueImpl) Enqueue(ctx context.Context, job domain.ReceiptJob) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	select {
+	case q.pending <- job.ID:
+	default:
+		return repository.ErrQueueFull
+	}
+
+	job.Status = domain.JobStatusPending
+	job.UpdatedAt = time.Now().UTC()
+	q.jobs[job.ID] = job
+	return nil
+}
+
+// Dequeue waits for the next pending job and marks it as processing
+func (q *ReceiptQueueImpl) Dequeue(ctx context.Context) (domain.ReceiptJob, error) {
+	select {
+	case <-ctx.Done():
+		return domain.ReceiptJob{}, ctx.Err()
+	case id := <-q.pending:
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		job := q.jobs[id]
+		job.Status = domain.JobStatusProcessing
+		job.UpdatedAt = time.Now().UTC()
+		q.jobs[id] = job
+		return job, nil
+	}
+}
+
+// Finish stops tracking a successful job, or marks a failed one with the failure
+func (q *ReceiptQueueImpl) Finish(ctx context.Context, id string, failure error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return repository.ErrJobNotFound
+	}
+	if failure == nil {
+		delete(q.jobs, id)
+		return nil
+	}
+
+	job.Status = domain.JobStatusFailed
+	job.Error = failure.Error()
+	job.UpdatedAt = time.Now().UTC()
+	q.jobs[id] = job
+	return nil
+}
+
+// Remove stops tracking a job
+func (q *ReceiptQueueImpl) Remove(ctx context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.jobs[id]; !ok {
+		return repository.ErrJobNotFound
+	}
+	delete(q.jobs, id)
+	return nil
+}
+
+// Job returns a tracked job by ID
+func (q *ReceiptQueueImpl) Job(ctx context.Context, id string) (domain.ReceiptJob, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.ReceiptJob{}, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return domain.ReceiptJob{}, repository.ErrJobNotFound
+	}
+	return job, nil
+}
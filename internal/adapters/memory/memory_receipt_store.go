@@ -1,15 +1,26 @@
 package memory
 
 import (
-	"github.com/google/uuid"
+	"context"
 	"go-receipt-processor/internal/domain"
 	"go-receipt-processor/internal/ports/repository"
+	"sort"
 	"sync"
 )
 
 // ReceiptStoreImpl stores receipts in an in-memory map, keyed by unique IDs.
+//
+// Two indexes narrow queries before filtering: receipt IDs grouped by normalized retailer,
+// and all receipt IDs ordered by purchase date so date ranges can be found by binary search.
+// A third groups receipt IDs by canonical fingerprint for duplicate detection.
 type ReceiptStoreImpl struct {
-	receipts map[string]domain.Receipt
+	mu            sync.RWMutex
+	receipts      map[string]domain.Receipt
+	revisions     map[string][]domain.Receipt
+	tombstones    map[string]domain.Tombstone
+	byRetailer    map[string]map[string]struct{}
+	byFingerprint map[string]map[string]struct{}
+	byDate        []string
 }
 
 // Declare a private variable to hold the singleton instance
@@ -21,21 +32,296 @@ func NewReceiptStore() repository.ReceiptStore {
 	once.Do(func() {
 		// Only create the instance once
 		instance = &ReceiptStoreImpl{
-			receipts: make(map[string]domain.Receipt),
+			receipts:      make(map[string]domain.Receipt),
+			revisions:     make(map[string][]domain.Receipt),
+			tombstones:    make(map[string]domain.Tombstone),
+			byRetailer:    make(map[string]map[string]struct{}),
+			byFingerprint: make(map[string]map[string]struct{}),
 		}
 	})
 	return instance
 }
 
 // Save stores a receipt in memory and returns its ID
-func (r *ReceiptStoreImpl) Save(receipt domain.Receipt) (string, error) {
-	receiptID := uuid.New().String()
+func (r *ReceiptStoreImpl) Save(ctx context.Context, receipt domain.Receipt) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	receiptID := repository.AssignReceiptID(receipt)
+	receipt.ID = receiptID
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	r.receipts[receiptID] = receipt
+	r.index(receipt)
+
 	return receiptID, nil
 }
 
+// SaveUnique stores a receipt unless another with its fingerprint is already stored, checking under the same lock as the insert
+func (r *ReceiptStoreImpl) SaveUnique(ctx context.Context, receipt domain.Receipt) (string, string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", "", err
+	}
+
+	receiptID := repository.AssignReceiptID(receipt)
+	receipt.ID = receiptID
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, saved := r.receipts[receiptID]; saved {
+		return receiptID, "", nil
+	}
+	if original, found := repository.EarliestReceipt(r.withFingerprint(receipt.Fingerprint)); found {
+		return "", original.ID, nil
+	}
+
+	r.receipts[receiptID] = receipt
+	r.index(receipt)
+
+	return receiptID, "", nil
+}
+
 // Find retrieves a receipt by ID
-func (r *ReceiptStoreImpl) Find(id string) (domain.Receipt, error) {
-	foundReceipt := r.receipts[id]
+func (r *ReceiptStoreImpl) Find(ctx context.Context, id string) (domain.Receipt, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Receipt{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	foundReceipt, ok := r.receipts[id]
+	if !ok {
+		if _, deleted := r.tombstones[id]; deleted {
+			return domain.Receipt{}, repository.ErrReceiptDeleted
+		}
+		return domain.Receipt{}, repository.ErrReceiptNotFound
+	}
 	return foundReceipt, nil
 }
+
+// Update replaces a receipt with a new revision, moving the replaced version into its history
+func (r *ReceiptStoreImpl) Update(ctx context.Context, receipt domain.Receipt, expectedRevision int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.receipts[receipt.ID]
+	if !ok {
+		if _, deleted := r.tombstones[receipt.ID]; deleted {
+			return repository.ErrReceiptDeleted
+		}
+		return repository.ErrReceiptNotFound
+	}
+	if current.Revision != expectedRevision {
+		return repository.ErrRevisionConflict
+	}
+
+	r.unindex(current)
+	r.revisions[receipt.ID] = append(r.revisions[receipt.ID], current)
+	r.receipts[receipt.ID] = receipt
+	r.index(receipt)
+	return nil
+}
+
+// Revisions returns the earlier revisions of a receipt, oldest first
+func (r *ReceiptStoreImpl) Revisions(ctx context.Context, id string) ([]domain.Receipt, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.receipts[id]; !ok {
+		if _, deleted := r.tombstones[id]; deleted {
+			return nil, repository.ErrReceiptDeleted
+		}
+		return nil, repository.ErrReceiptNotFound
+	}
+	return append([]domain.Receipt{}, r.revisions[id]...), nil
+}
+
+// Query returns a page of receipts matching the query, using the retailer or date index to pick candidates
+func (r *ReceiptStoreImpl) Query(ctx context.Context, query repository.ReceiptQuery) (repository.ReceiptPage, error) {
+	if err := ctx.Err(); err != nil {
+		return repository.ReceiptPage{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return query.Paginate(r.candidates(query))
+}
+
+// FindByFingerprint returns all receipts with the given canonical fingerprint
+func (r *ReceiptStoreImpl) FindByFingerprint(ctx context.Context, fingerprint string) ([]domain.Receipt, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.withFingerprint(fingerprint), nil
+}
+
+// withFingerprint returns the receipts indexed under fingerprint; callers hold the lock
+func (r *ReceiptStoreImpl) withFingerprint(fingerprint string) []domain.Receipt {
+	ids := r.byFingerprint[fingerprint]
+	receipts := make([]domain.Receipt, 0, len(ids))
+	for id := range ids {
+		receipts = append(receipts, r.receipts[id])
+	}
+	return receipts
+}
+
+// Delete removes a receipt and its index entries, leaving the tombstone in its place
+func (r *ReceiptStoreImpl) Delete(ctx context.Context, id string, tombstone domain.Tombstone) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, deleted := r.tombstones[id]; deleted {
+		return repository.ErrReceiptDeleted
+	}
+	if _, ok := r.receipts[id]; !ok {
+		return repository.ErrReceiptNotFound
+	}
+
+	tombstone.ReceiptID = id
+	r.remove(id, tombstone)
+	return nil
+}
+
+// Erase deletes every receipt matching the selector, using the retailer index when erasing by retailer
+func (r *ReceiptStoreImpl) Erase(ctx context.Context, selector repository.ErasureSelector, audit domain.Tombstone) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []string
+	if selector.Retailer != "" {
+		for id := range r.byRetailer[repository.NormalizeRetailer(selector.Retailer)] {
+			ids = append(ids, id)
+		}
+	} else {
+		for id, receipt := range r.receipts {
+			if selector.Matches(receipt) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		tombstone := audit
+		tombstone.ReceiptID = id
+		r.remove(id, tombstone)
+	}
+	return ids, nil
+}
+
+// remove drops a receipt and its history from the map and the indexes and records its tombstone;
+// callers hold the write lock
+func (r *ReceiptStoreImpl) remove(id string, tombstone domain.Tombstone) {
+	r.unindex(r.receipts[id])
+	delete(r.receipts, id)
+	delete(r.revisions, id)
+	r.tombstones[id] = tombstone
+}
+
+// index adds a stored receipt to the indexes; callers hold the write lock
+func (r *ReceiptStoreImpl) index(receipt domain.Receipt) {
+	retailer := repository.NormalizeRetailer(receipt.Retailer)
+	if r.byRetailer[retailer] == nil {
+		r.byRetailer[retailer] = make(map[string]struct{})
+	}
+	r.byRetailer[retailer][receipt.ID] = struct{}{}
+
+	if receipt.Fingerprint != "" {
+		if r.byFingerprint[receipt.Fingerprint] == nil {
+			r.byFingerprint[receipt.Fingerprint] = make(map[string]struct{})
+		}
+		r.byFingerprint[receipt.Fingerprint][receipt.ID] = struct{}{}
+	}
+
+	i := sort.Search(len(r.byDate), func(i int) bool {
+		return r.receipts[r.byDate[i]].PurchaseDate > receipt.PurchaseDate
+	})
+	r.byDate = append(r.byDate, "")
+	copy(r.byDate[i+1:], r.byDate[i:])
+	r.byDate[i] = receipt.ID
+}
+
+// unindex removes a receipt from the indexes; it must still be the version held in the map,
+// since the date index is searched by the stored purchase dates. Callers hold the write lock.
+func (r *ReceiptStoreImpl) unindex(receipt domain.Receipt) {
+	id := receipt.ID
+	retailer := repository.NormalizeRetailer(receipt.Retailer)
+	delete(r.byRetailer[retailer], id)
+	if len(r.byRetailer[retailer]) == 0 {
+		delete(r.byRetailer, retailer)
+	}
+
+	delete(r.byFingerprint[receipt.Fingerprint], id)
+	if len(r.byFingerprint[receipt.Fingerprint]) == 0 {
+		delete(r.byFingerprint, receipt.Fingerprint)
+	}
+
+	start := sort.Search(len(r.byDate), func(i int) bool {
+		return r.receipts[r.byDate[i]].PurchaseDate >= receipt.PurchaseDate
+	})
+	for i := start; i < len(r.byDate); i++ {
+		if r.byDate[i] == id {
+			r.byDate = append(r.byDate[:i], r.byDate[i+1:]...)
+			break
+		}
+	}
+}
+
+// candidates returns the smallest set of receipts the indexes can guarantee contains every match
+func (r *ReceiptStoreImpl) candidates(query repository.ReceiptQuery) []domain.Receipt {
+	if query.Retailer != "" {
+		ids := r.byRetailer[repository.NormalizeRetailer(query.Retailer)]
+		receipts := make([]domain.Receipt, 0, len(ids))
+		for id := range ids {
+			receipts = append(receipts, r.receipts[id])
+		}
+		return receipts
+	}
+
+	start, end := 0, len(r.byDate)
+	if query.PurchaseDateFrom != "" {
+		start = sort.Search(len(r.byDate), func(i int) bool {
+			return r.receipts[r.byDate[i]].PurchaseDate >= query.PurchaseDateFrom
+		})
+	}
+	if query.PurchaseDateTo != "" {
+		end = sort.Search(len(r.byDate), func(i int) bool {
+			return r.receipts[r.byDate[i]].PurchaseDate > query.PurchaseDateTo
+		})
+	}
+	if start >= end {
+		return nil
+	}
+
+	receipts := make([]domain.Receipt, 0, end-start)
+	for _, id := range r.byDate[start:end] {
+		receipts = append(receipts, r.receipts[id])
+	}
+	return receipts
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// maxDeliveriesPerSubscription bounds each subscription's delivery log; older attempts are dropped.
+const maxDeliveriesPerSubscription = 100
+
+// WebhookStoreImpl keeps webhook subscriptions and their delivery logs in memory.
+type WebhookStoreImpl struct {
+	mu            sync.RWMutex
+	subscriptions map[string]domain.WebhookSubscription
+	deliveries    map[string][]domain.WebhookDelivery // Oldest first
+}
+
+// NewWebhookStore
+//
+// Returns:
+//   - A new, empty instance of WebhookStoreImpl.
+func NewWebhookStore() *WebhookStoreImpl {
+	return &WebhookStoreImpl{
+		subscriptions: make(map[string]domain.WebhookSubscription),
+		deliveries:    make(map[string][]domain.WebhookDelivery),
+	}
+}
+
+// CreateSubscription stores a new subscription under a generated ID
+func (s *WebhookStoreImpl) CreateSubscription(ctx context.Context, subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscription.ID = uuid.New().String()
+	subscription.Events = append([]string{}, subscription.Events...)
+	s.subscriptions[subscription.ID] = subscription
+	return subscription, nil
+}
+
+// Subscriptions returns every subscription, oldest first
+func (s *WebhookStoreImpl) Subscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subscriptions := make([]domain.WebhookSubscription, 0, len(s.subscriptions))
+	for _, subscription := range s.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if !subscriptions[i].CreatedAt.Equal(subscriptions[j].CreatedAt) {
+			return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+		}
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+	return subscriptions, nil
+}
+
+// Subscription returns a subscription by ID
+func (s *WebhookStoreImpl) Subscription(ctx context.Context, id string) (domain.WebhookSubscription, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subscription, ok := s.subscriptions[id]
+	if !ok {
+		return domain.WebhookSubscription{}, repository.ErrSubscriptionNotFound
+	}
+	return subscription, nil
+}
+
+// DeleteSubscription removes a subscription and its delivery log
+func (s *WebhookStoreImpl) DeleteSubscription(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscriptions[id]; !ok {
+		return repository.ErrSubscriptionNotFound
+	}
+	delete(s.subscriptions, id)
+	delete(s.deliveries, id)
+	return nil
+}
+
+// RecordDelivery appends an attempt to its subscription's log, keeping the most recent maxDeliveriesPerSubscription.
+// Attempts for subscriptions deleted while the event was being delivered are dropped.
+func (s *WebhookStoreImpl) RecordDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscriptions[delivery.SubscriptionID]; !ok {
+		return repository.ErrSubscriptionNotFound
+	}
+	log := append(s.deliveries[delivery.SubscriptionID], delivery)
+	if len(log) > maxDeliveriesPerSubscription {
+		log = append([]domain.WebhookDelivery{}, log[len(log)-maxDeliveriesPerSubscription:]...)
+	}
+	s.deliveries[delivery.SubscriptionID] = log
+	return nil
+}
+
+// Deliveries returns a subscription's delivery log, most recent attempt first
+func (s *WebhookStoreImpl) Deliveries(ctx context.Context, subscriptionID string) ([]domain.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.subscriptions[subscriptionID]; !ok {
+		return nil, repository.ErrSubscriptionNotFound
+	}
+	log := s.deliveries[subscriptionID]
+	deliveries := make([]domain.WebhookDelivery, len(log))
+	for i, delivery := range log {
+		deliveries[len(log)-1-i] = delivery
+	}
+	return deliveries, nil
+}
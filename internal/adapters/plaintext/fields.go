@@ -0,0 +1,215 @@
+package plaintext
+
+import (
+	"fmt"
+	"go-receipt-processor/internal/domain"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// amountPattern splits a line ending in an amount, e.g. "MILK 2% 3.49 F", "TOTAL: $18.74" or "COUPON 1.00-",
+	// into its label, a leading minus, the amount and a trailing minus. A tax flag after the amount is ignored.
+	amountPattern = regexp.MustCompile(`^(.*?)[\s:]*(-)?[$£€]?\s?(\d{1,3}(?:,\d{3})+\.\d{2}|\d+[.,]\d{2})(-)?(?:\s+[A-Z]{1,2})?$`)
+
+	isoDatePattern     = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	numericDatePattern = regexp.MustCompile(`\b(\d{1,2})[/.\-](\d{1,2})[/.\-](\d{4}|\d{2})\b`)
+	monthFirstPattern  = regexp.MustCompile(`(?i)\b(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?\s+(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})\b`)
+	dayFirstPattern    = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)?\s+(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?,?\s+(\d{4})\b`)
+
+	clock12Pattern = regexp.MustCompile(`(?i)\b(\d{1,2}):([0-5]\d)(?::[0-5]\d)?\s*([ap])\.?\s?m\b\.?`)
+	clock24Pattern = regexp.MustCompile(`\b([01]?\d|2[0-3]):([0-5]\d)(?::[0-5]\d)?\b`)
+
+	// quantityPattern matches the detail line of an item sold by weight or in multiples, e.g. "2 @ 1.25" or "1.32 lb @ 0.69 /lb"
+	quantityPattern = regexp.MustCompile(`(?i)@|^\d+(\.\d+)?\s*(x|lb|lbs|kg|ea)\b`)
+	// skuPattern matches the article numbers printed before or after an item's description
+	skuPattern = regexp.MustCompile(`^\d{5,}\s+|\s+\d{5,}$`)
+	// storeNumberPattern matches the branch number after a retailer's name, e.g. "WALMART #1234"
+	storeNumberPattern = regexp.MustCompile(`\s*(#|no\.?|store)\s*\d+$`)
+	// headerNoisePattern matches header lines that do not name the retailer
+	headerNoisePattern = regexp.MustCompile(`(?i)^(thank|thanks|receipt|invoice|store\s*#|st#|tel|phone|ph|fax|www\.|http|cashier|register|reg|trans|order|customer|server|table|guest|open|hours)\b`)
+	welcomePattern     = regexp.MustCompile(`(?i)^welcome\s+to\s+`)
+	// addressPattern matches a street address, e.g. "1234 Main St"
+	addressPattern = regexp.MustCompile(`^\d+\s+\w`)
+	// retailerDisallowedPattern and descriptionDisallowedPattern match the characters the Receipt schema
+	// does not allow in a retailer's name and an item's description
+	retailerDisallowedPattern    = regexp.MustCompile(`[^\w\s\-&]`)
+	descriptionDisallowedPattern = regexp.MustCompile(`[^\w\s\-]`)
+	letterPattern                = regexp.MustCompile(`[A-Za-z]`)
+)
+
+var months = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April, "may": time.May, "jun": time.June,
+	"jul": time.July, "aug": time.August, "sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+// findDate finds the first date in text and formats it as YYYY-MM-DD. Numeric dates are read month first,
+// as printed in the US, unless that is impossible; a date that could be read either way is less certain.
+func findDate(text string) (string, float64, bool) {
+	if m := isoDatePattern.FindStringSubmatch(text); m != nil {
+		if date, ok := formatDate(m[1], m[2], m[3]); ok {
+			return date, 0.95, true
+		}
+	}
+	if m := monthFirstPattern.FindStringSubmatch(text); m != nil {
+		if date, ok := formatDate(m[3], monthNumber(m[1]), m[2]); ok {
+			return date, 0.9, true
+		}
+	}
+	if m := dayFirstPattern.FindStringSubmatch(text); m != nil {
+		if date, ok := formatDate(m[3], monthNumber(m[2]), m[1]); ok {
+			return date, 0.9, true
+		}
+	}
+	if m := numericDatePattern.FindStringSubmatch(text); m != nil {
+		first, _ := strconv.Atoi(m[1])
+		second, _ := strconv.Atoi(m[2])
+		year, confidence := m[3], 0.9
+		if len(year) == 2 {
+			year, confidence = "20"+year, confidence-0.1
+		}
+		month, day := m[1], m[2]
+		switch {
+		case first > 12:
+			month, day = m[2], m[1]
+			confidence -= 0.05
+		case second <= 12 && first != second:
+			// Ambiguous, e.g. 03/04: 4 March in most of the world
+			confidence -= 0.2
+		}
+		if date, ok := formatDate(year, month, day); ok {
+			return date, confidence, true
+		}
+	}
+	return "", 0, false
+}
+
+// formatDate formats a date as YYYY-MM-DD if it exists
+func formatDate(year, month, day string) (string, bool) {
+	y, _ := strconv.Atoi(year)
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	date := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	if date.Year() != y || int(date.Month()) != m || date.Day() != d {
+		return "", false
+	}
+	return date.Format(time.DateOnly), true
+}
+
+// monthNumber converts the name of a month to its number, e.g. "Mar" to "3"
+func monthNumber(name string) string {
+	return strconv.Itoa(int(months[strings.ToLower(name[:3])]))
+}
+
+// findTime finds the time of purchase as HH:MM, preferring the line the date was found on
+func findTime(lines []line, dateLine int) (string, float64) {
+	if dateLine >= 0 {
+		if clock, ok := parseClock(lines[dateLine].text); ok {
+			return clock, 0.95
+		}
+	}
+	for _, l := range lines {
+		if clock, ok := parseClock(l.text); ok {
+			return clock, 0.8
+		}
+	}
+	return "", 0
+}
+
+// parseClock reads a 12 or 24-hour time from text as a 24-hour HH:MM
+func parseClock(text string) (string, bool) {
+	if m := clock12Pattern.FindStringSubmatch(text); m != nil {
+		hour, _ := strconv.Atoi(m[1])
+		if hour < 1 || hour > 12 {
+			return "", false
+		}
+		hour %= 12
+		if strings.EqualFold(m[3], "p") {
+			hour += 12
+		}
+		return fmt.Sprintf("%02d:%s", hour, m[2]), true
+	}
+	if m := clock24Pattern.FindStringSubmatch(text); m != nil {
+		hour, _ := strconv.Atoi(m[1])
+		return fmt.Sprintf("%02d:%s", hour, m[2]), true
+	}
+	return "", false
+}
+
+// findItems reads the items from the lines above the receipt's summary. A description printed on a line
+// of its own takes the amount of the quantity line below it.
+func findItems(lines []line) []domain.Item {
+	var items []domain.Item
+	pending := ""
+	for _, l := range lines {
+		if l.hasDate {
+			pending = ""
+			continue
+		}
+		if l.amount == "" {
+			pending = cleanText(skuPattern.ReplaceAllString(l.label, ""), descriptionDisallowedPattern)
+			continue
+		}
+
+		description := pending
+		pending = ""
+		if !quantityPattern.MatchString(l.label) {
+			description = cleanText(skuPattern.ReplaceAllString(l.label, ""), descriptionDisallowedPattern)
+		}
+		if l.negative || description == "" || !letterPattern.MatchString(description) || summaryPattern.MatchString(l.label) {
+			continue
+		}
+		items = append(items, domain.Item{ShortDescription: description, Price: l.amount})
+	}
+	return items
+}
+
+// findRetailer takes the retailer's name from the first header line that is not an address, a phone number
+// or a greeting. A name that had to be cleaned up to match the Receipt schema, or that is not on the first
+// line, is less certain.
+func findRetailer(header []line) (string, float64) {
+	for i, l := range header {
+		text := welcomePattern.ReplaceAllString(l.text, "")
+		if !letterPattern.MatchString(text) || headerNoisePattern.MatchString(text) || addressPattern.MatchString(text) {
+			continue
+		}
+
+		text = storeNumberPattern.ReplaceAllString(text, "")
+		name := cleanText(text, retailerDisallowedPattern)
+		if name == "" {
+			continue
+		}
+		confidence := 0.9
+		if i > 0 {
+			confidence -= 0.2
+		}
+		if retailerDisallowedPattern.MatchString(text) {
+			confidence -= 0.1
+		}
+		return name, confidence
+	}
+	return "", 0
+}
+
+// cleanText drops apostrophes, replaces the other disallowed characters with spaces, and collapses runs of spaces
+func cleanText(text string, disallowed *regexp.Regexp) string {
+	text = strings.NewReplacer("'", "", "’", "").Replace(text)
+	text = disallowed.ReplaceAllString(text, " ")
+	return strings.Trim(strings.Join(strings.Fields(text), " "), "-& ")
+}
+
+// normalizeAmount rewrites an amount such as "1,234.50" or "6,49" as "1234.50" or "6.49"
+func normalizeAmount(amount string) string {
+	if strings.Count(amount, ",") == 1 && !strings.Contains(amount, ".") {
+		return strings.Replace(amount, ",", ".", 1)
+	}
+	return strings.ReplaceAll(amount, ",", "")
+}
+
+// parseAmount converts a normalized amount to a number, or 0 if it is empty
+func parseAmount(amount string) float64 {
+	value, _ := strconv.ParseFloat(amount, 64)
+	return value
+}
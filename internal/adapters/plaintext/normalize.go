@@ -0,0 +1,63 @@
+package plaintext
+
+// ParseDate
+//
+// Parameters:
+//   - text: Text holding a date in one of the forms Parse understands, e.g. "01/13/2022" or "Jan 13, 2022".
+//
+// Returns:
+//   - The first date in text as YYYY-MM-DD, and whether one was found.
+func ParseDate(text string) (string, bool) {
+	date, _, ok := findDate(text)
+	return date, ok
+}
+
+// ParseTime
+//
+// Parameters:
+//   - text: Text holding a 12 or 24-hour time, e.g. "1:01 PM" or "13:01".
+//
+// Returns:
+//   - The first time in text as a 24-hour HH:MM, and whether one was found.
+func ParseTime(text string) (string, bool) {
+	return parseClock(text)
+}
+
+// ParseAmount
+//
+// Parameters:
+//   - text: An amount as printed, e.g. "$1,234.50", "6,49" or "6.49".
+//
+// Returns:
+//   - The amount with two decimals and no currency or thousands separators, e.g. "1234.50", and whether
+//     text holds one.
+func ParseAmount(text string) (string, bool) {
+	m := amountPattern.FindStringSubmatch(text)
+	if m == nil || m[2] != "" || m[4] != "" {
+		return "", false
+	}
+	return normalizeAmount(m[3]), true
+}
+
+// CleanDescription
+//
+// Parameters:
+//   - text: An item's description as printed.
+//
+// Returns:
+//   - The description without the characters the Receipt schema does not allow in it, e.g. "Trader Joes Salsa"
+//     for "Trader Joe's Salsa®".
+func CleanDescription(text string) string {
+	return cleanText(text, descriptionDisallowedPattern)
+}
+
+// CleanRetailer
+//
+// Parameters:
+//   - text: A retailer's name as printed.
+//
+// Returns:
+//   - The name without the characters the Receipt schema does not allow in it.
+func CleanRetailer(text string) string {
+	return cleanText(text, retailerDisallowedPattern)
+}
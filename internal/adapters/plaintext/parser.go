@@ -0,0 +1,228 @@
+// Package plaintext extracts receipts from their printed text, as produced by OCR. Receipts are laid
+// out in many different ways, so the parser works from heuristics and reports how sure it is of every
+// field it fills in rather than failing on text it does not fully understand.
+package plaintext
+
+import (
+	"go-receipt-processor/internal/domain"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Fields whose confidence Parse reports, named as in the JSON encoding of a receipt.
+const (
+	FieldRetailer     = "retailer"
+	FieldPurchaseDate = "purchaseDate"
+	FieldPurchaseTime = "purchaseTime"
+	FieldItems        = "items"
+	FieldTotal        = "total"
+)
+
+// Result is a receipt extracted from text.
+type Result struct {
+	Receipt    domain.Receipt     // Fields that could not be found are left empty
+	Confidence map[string]float64 // How sure Parse is of each field, from 0 (not found) to 1
+}
+
+var (
+	// separatorPattern matches decorative lines such as "------" or "* * *"
+	separatorPattern = regexp.MustCompile(`^[\s\-=*_#~.:]*$`)
+	subtotalPattern  = regexp.MustCompile(`(?i)\bsub\s*-?\s*total\b`)
+	taxPattern       = regexp.MustCompile(`(?i)\b(tax|vat|gst|hst|pst)\b`)
+	totalPattern     = regexp.MustCompile(`(?i)\btotal\b`)
+	// notTotalPattern matches totals of something other than the amount paid, e.g. "TOTAL SAVINGS"
+	notTotalPattern   = regexp.MustCompile(`(?i)\b(savings|saved|discount|items?|qty|quantity|tax|tip)\b`)
+	grandTotalPattern = regexp.MustCompile(`(?i)\bgrand\s+total\b`)
+	amountDuePattern  = regexp.MustCompile(`(?i)\b(balance\s+due|amount\s+due|balance|to\s+pay|charged)\b`)
+	// paymentPattern matches how the receipt was paid, which is never an item or the total
+	paymentPattern = regexp.MustCompile(`(?i)\b(cash|change|tender(ed)?|visa|mastercard|amex|discover|debit|credit|card|payment|paid)\b`)
+	// endOfItemsPattern matches the first line after the items
+	endOfItemsPattern = regexp.MustCompile(`(?i)\b(sub\s*-?\s*total|total|tax|vat|gst|hst|pst|balance|amount\s+due)\b`)
+	// summaryPattern matches every amount that is not an item
+	summaryPattern = regexp.MustCompile(`(?i)\b(sub\s*-?\s*total|total|tax|vat|gst|hst|pst|cash|change|tender(ed)?|visa|mastercard|amex|discover|debit|credit|card|balance|amount|due|paid|payment|savings?|saved|discount|coupon|tip|gratuity|rounding)\b`)
+)
+
+// line is one non-blank line of a receipt, split into its label and the amount it ends with, if any
+type line struct {
+	text     string
+	label    string // The text before the amount, or the whole line
+	amount   string // Normalized to two decimals, e.g. "6.49"; empty if the line has no amount
+	negative bool   // The amount is a discount or refund, e.g. "COUPON 1.00-"
+	hasDate  bool
+}
+
+// Parse
+//
+// Parameters:
+//   - text: The text of a printed receipt, one printed line per line.
+//
+// Returns:
+//   - The receipt that could be extracted and the confidence in each of its fields. The receipt is not
+//     validated: fields that were not found are empty and have a confidence of 0.
+func Parse(text string) Result {
+	lines := receiptLines(text)
+	result := Result{Confidence: map[string]float64{
+		FieldRetailer:     0,
+		FieldPurchaseDate: 0,
+		FieldPurchaseTime: 0,
+		FieldItems:        0,
+		FieldTotal:        0,
+	}}
+	receipt := &result.Receipt
+
+	dateLine := -1
+	for i, l := range lines {
+		if date, confidence, ok := findDate(l.text); ok {
+			receipt.PurchaseDate, result.Confidence[FieldPurchaseDate], dateLine = date, confidence, i
+			break
+		}
+	}
+	receipt.PurchaseTime, result.Confidence[FieldPurchaseTime] = findTime(lines, dateLine)
+
+	// Items are listed before the first subtotal, tax or total; everything after them is about payment
+	end := len(lines)
+	for i, l := range lines {
+		if l.amount != "" && endOfItemsPattern.MatchString(l.label) {
+			end = i
+			break
+		}
+	}
+	receipt.Items = findItems(lines[:end])
+
+	subtotal, tax := summaryAmounts(lines[end:])
+	itemsSum := 0.0
+	for _, item := range receipt.Items {
+		itemsSum += parseAmount(item.Price)
+	}
+
+	receipt.Total, result.Confidence[FieldTotal] = findTotal(lines[end:])
+	if receipt.Total != "" {
+		total := parseAmount(receipt.Total)
+		if sameAmount(total, subtotal+tax) || sameAmount(total, itemsSum+tax) {
+			result.Confidence[FieldTotal] += 0.1
+		}
+		result.Confidence[FieldItems] = itemsConfidence(len(receipt.Items), itemsSum, subtotal, tax, total)
+	} else {
+		// A guessed total may be one of the items, so the items cannot be checked against it
+		receipt.Total, result.Confidence[FieldTotal] = largestAmount(lines)
+		result.Confidence[FieldItems] = itemsConfidence(len(receipt.Items), itemsSum, subtotal, tax, 0)
+	}
+
+	firstContent := end
+	for i, l := range lines {
+		if l.amount != "" || l.hasDate {
+			firstContent = min(firstContent, i)
+			break
+		}
+	}
+	receipt.Retailer, result.Confidence[FieldRetailer] = findRetailer(lines[:firstContent])
+
+	for field, confidence := range result.Confidence {
+		result.Confidence[field] = math.Round(confidence*100) / 100
+	}
+	return result
+}
+
+// receiptLines splits text into its lines, dropping blank and decorative ones
+func receiptLines(text string) []line {
+	var lines []line
+	for _, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		text := strings.Join(strings.Fields(raw), " ")
+		if separatorPattern.MatchString(text) {
+			continue
+		}
+
+		l := line{text: text, label: text}
+		if _, _, ok := findDate(text); ok {
+			// Dates such as 01.02.22 end with what looks like an amount
+			l.hasDate = true
+			lines = append(lines, l)
+			continue
+		}
+		if m := amountPattern.FindStringSubmatch(text); m != nil {
+			l.label = strings.TrimSpace(m[1])
+			l.negative = m[2] != "" || m[4] != ""
+			l.amount = normalizeAmount(m[3])
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+// summaryAmounts adds up the subtotal and tax lines of the summary after the items
+func summaryAmounts(lines []line) (subtotal, tax float64) {
+	for _, l := range lines {
+		switch {
+		case l.amount == "" || l.negative:
+		case subtotalPattern.MatchString(l.label):
+			subtotal += parseAmount(l.amount)
+		case taxPattern.MatchString(l.label) && !totalPattern.MatchString(l.label):
+			tax += parseAmount(l.amount)
+		}
+	}
+	return subtotal, tax
+}
+
+// findTotal looks for the amount paid among the summary lines: a grand total, then a total, then an amount due
+func findTotal(lines []line) (string, float64) {
+	var total, due string
+	for _, l := range lines {
+		switch {
+		case l.amount == "" || l.negative:
+		case grandTotalPattern.MatchString(l.label):
+			return l.amount, 0.85
+		case total == "" && totalPattern.MatchString(l.label) && !subtotalPattern.MatchString(l.label) && !notTotalPattern.MatchString(l.label):
+			total = l.amount
+		case due == "" && amountDuePattern.MatchString(l.label):
+			due = l.amount
+		}
+	}
+	if total != "" {
+		return total, 0.85
+	}
+	if due != "" {
+		return due, 0.7
+	}
+	return "", 0
+}
+
+// largestAmount guesses the total of a receipt without a total line as its largest amount that is not a payment
+func largestAmount(lines []line) (string, float64) {
+	var largest string
+	for _, l := range lines {
+		if l.amount == "" || l.negative || paymentPattern.MatchString(l.label) {
+			continue
+		}
+		if largest == "" || parseAmount(l.amount) > parseAmount(largest) {
+			largest = l.amount
+		}
+	}
+	if largest == "" {
+		return "", 0
+	}
+	return largest, 0.4
+}
+
+// itemsConfidence rates the extracted items by how well they add up to the subtotal or total printed below them
+func itemsConfidence(count int, sum, subtotal, tax, total float64) float64 {
+	switch {
+	case count == 0:
+		return 0
+	case subtotal > 0 && sameAmount(sum, subtotal):
+		return 0.95
+	case subtotal > 0:
+		return 0.5
+	case total > 0 && (sameAmount(sum, total) || sameAmount(sum+tax, total)):
+		return 0.9
+	case total > 0:
+		return 0.6
+	default:
+		return 0.5
+	}
+}
+
+// sameAmount reports whether two amounts are equal to the cent
+func sameAmount(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
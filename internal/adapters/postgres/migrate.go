@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrate applies every embedded migration that has not yet been recorded in schema_migrations.
+//
+// Migrations are named "<version>_<description>.sql" and run in version order, each in its own
+// transaction together with the insert that records it, so a failed migration leaves no trace.
+//
+// Parameters:
+//   - ctx: Context bounding the whole migration run.
+//   - pool: The connection pool to migrate.
+//
+// Returns:
+//   - err: An error if a migration cannot be read or applied.
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	_, err := pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("unable to create schema_migrations: %v", err)
+	}
+
+	names, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version, err := migrationVersion(name)
+		if err != nil {
+			return err
+		}
+		if err := applyMigration(ctx, pool, name, version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyMigration runs a single migration file unless its version has already been applied
+func applyMigration(ctx context.Context, pool *pgxpool.Pool, name string, version int) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize concurrent migrators (e.g. several replicas starting at once)
+	if _, err := tx.Exec(ctx, `LOCK TABLE schema_migrations IN EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+
+	var applied bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, version).Scan(&applied)
+	if err != nil {
+		return err
+	}
+	if applied {
+		return nil
+	}
+
+	sql, err := migrationFiles.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, string(sql)); err != nil {
+		return fmt.Errorf("unable to apply migration '%s': %v", name, err)
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// migrationVersion extracts the numeric prefix from a migration file name
+func migrationVersion(name string) (int, error) {
+	base := strings.TrimPrefix(name, "migrations/")
+	prefix, _, found := strings.Cut(base, "_")
+	if !found {
+		return 0, fmt.Errorf("migration '%s' is not named <version>_<description>.sql", name)
+	}
+	version, err := strconv.Atoi(prefix)
+	if err != nil {
+		return 0, fmt.Errorf("migration '%s' has an invalid version: %v", name, err)
+	}
+	return version, nil
+}
@@ -0,0 +1,20 @@
+CREATE TABLE receipts (
+    id            TEXT PRIMARY KEY,
+    retailer      TEXT NOT NULL,
+    purchase_date TEXT NOT NULL,
+    purchase_time TEXT NOT NULL,
+    total         TEXT NOT NULL,
+    points        INTEGER NOT NULL DEFAULT 0,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX receipts_retailer_idx ON receipts (lower(trim(retailer)));
+CREATE INDEX receipts_purchase_date_idx ON receipts (purchase_date);
+
+CREATE TABLE receipt_items (
+    receipt_id        TEXT NOT NULL REFERENCES receipts (id) ON DELETE CASCADE,
+    position          INTEGER NOT NULL,
+    short_description TEXT NOT NULL,
+    price             TEXT NOT NULL,
+    PRIMARY KEY (receipt_id, position)
+);
@@ -0,0 +1 @@
+CREATE INDEX receipts_points_idx ON receipts (points, id);
@@ -0,0 +1,3 @@
+ALTER TABLE receipts
+    ADD COLUMN received_at     TIMESTAMPTZ,
+    ADD COLUMN scoring_version TEXT NOT NULL DEFAULT '';
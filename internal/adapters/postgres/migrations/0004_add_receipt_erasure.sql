@@ -0,0 +1,11 @@
+ALTER TABLE receipts
+    ADD COLUMN customer_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX receipts_customer_id_idx ON receipts (customer_id) WHERE customer_id <> '';
+
+CREATE TABLE receipt_tombstones (
+    receipt_id TEXT PRIMARY KEY,
+    deleted_at TIMESTAMPTZ NOT NULL,
+    deleted_by TEXT NOT NULL,
+    reason     TEXT NOT NULL
+);
@@ -0,0 +1,11 @@
+ALTER TABLE receipts
+    ADD COLUMN revision     INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN points_delta INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN revised_at   TIMESTAMPTZ;
+
+CREATE TABLE receipt_revisions (
+    receipt_id TEXT NOT NULL REFERENCES receipts (id) ON DELETE CASCADE,
+    revision   INTEGER NOT NULL,
+    snapshot   JSONB NOT NULL,
+    PRIMARY KEY (receipt_id, revision)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE receipts
+    ADD COLUMN fingerprint  TEXT NOT NULL DEFAULT '',
+    ADD COLUMN duplicate_of TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX receipts_fingerprint_idx ON receipts (fingerprint) WHERE fingerprint <> '';
@@ -0,0 +1,613 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// dateSortedSet is the key suffix of the sorted set holding every receipt ID scored by purchase date.
+const dateSortedSet = "idx:by-date"
+
+// maxWatchAttempts bounds how often Erase and SaveUnique start over after a key they watch changed underneath them.
+const maxWatchAttempts = 5
+
+// pruneScript removes each ID in ARGV from the by-date set in KEYS[1] if its receipt key, KEYS[i+1], no longer exists.
+// Checking in the script, rather than trusting an earlier read, keeps a receipt saved again since that read indexed.
+var pruneScript = goredis.NewScript(`
+for i, id in ipairs(ARGV) do
+	if redis.call("EXISTS", KEYS[i + 1]) == 0 then
+		redis.call("ZREM", KEYS[1], id)
+	end
+end
+return 0
+`)
+
+// Encoding selects how receipts are serialized into Redis values.
+type Encoding string
+
+// Supported values for Options.Encoding.
+const (
+	EncodingJSON    Encoding = "json"
+	EncodingMsgpack Encoding = "msgpack"
+)
+
+// Options configures how the Redis store lays out and expires its keys.
+type Options struct {
+	KeyPrefix string        // Prepended to every key written by the store, e.g. "receipts:"
+	TTL       time.Duration // Expiry applied to receipts and index sets; zero keeps them forever
+	Encoding  Encoding      // Value encoding; defaults to EncodingJSON
+}
+
+// ReceiptStoreImpl stores receipts in Redis so they can be shared across replicas.
+//
+// Each receipt is kept under "<prefix>receipt:<id>" (replaced by a JSON "<prefix>tombstone:<id>"
+// once deleted), its replaced revisions in the list "<prefix>revisions:<id>", and its ID is added to the index sets
+// "<prefix>idx:retailer:<retailer>", "<prefix>idx:date:<purchaseDate>" and "<prefix>idx:fingerprint:<fingerprint>", and to the sorted set
+// "<prefix>idx:by-date" scored by purchase date for range queries. The sorted set holds every receipt and so never
+// expires; IDs whose receipt has expired are removed from it whenever a query or lookup comes across them.
+type ReceiptStoreImpl struct {
+	client goredis.UniversalClient
+	opts   Options
+}
+
+// NewReceiptStore
+//
+// Parameters:
+//   - client: The Redis client used for all commands. The store takes ownership and closes it in Close.
+//   - opts: Key prefix, TTL and encoding settings.
+//
+// Returns:
+//   - A new instance of ReceiptStoreImpl using the provided client.
+//   - err: An error if the configured encoding is not supported.
+func NewReceiptStore(client goredis.UniversalClient, opts Options) (*ReceiptStoreImpl, error) {
+	if opts.Encoding == "" {
+		opts.Encoding = EncodingJSON
+	}
+	if opts.Encoding != EncodingJSON && opts.Encoding != EncodingMsgpack {
+		return nil, fmt.Errorf("unsupported redis encoding '%s'", opts.Encoding)
+	}
+
+	return &ReceiptStoreImpl{client: client, opts: opts}, nil
+}
+
+// Save writes the receipt and its index entries in a single pipelined transaction and returns its ID
+func (r *ReceiptStoreImpl) Save(ctx context.Context, receipt domain.Receipt) (string, error) {
+	receiptID := repository.AssignReceiptID(receipt)
+	receipt.ID = receiptID
+
+	value, err := r.encode(receipt)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Set(ctx, r.receiptKey(receiptID), value, r.opts.TTL)
+		r.queueIndex(ctx, pipe, receipt)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to save receipt: %w", markTransient(err))
+	}
+
+	return receiptID, nil
+}
+
+// SaveUnique writes the receipt and its index entries unless another receipt with its fingerprint is stored.
+// The fingerprint index and the receipt key are watched while they are read, so a receipt with the same fingerprint
+// saved in the meantime aborts the write, which is then retried from a fresh read.
+func (r *ReceiptStoreImpl) SaveUnique(ctx context.Context, receipt domain.Receipt) (string, string, error) {
+	receiptID := repository.AssignReceiptID(receipt)
+	receipt.ID = receiptID
+
+	value, err := r.encode(receipt)
+	if err != nil {
+		return "", "", err
+	}
+	key := r.receiptKey(receiptID)
+	indexKey := r.key("idx:fingerprint:" + receipt.Fingerprint)
+
+	var originalID string
+	save := func(tx *goredis.Tx) error {
+		originalID = ""
+
+		exists, err := tx.Exists(ctx, key).Result()
+		if err != nil {
+			return markTransient(err)
+		}
+		if exists > 0 {
+			return nil
+		}
+
+		ids, err := tx.SMembers(ctx, indexKey).Result()
+		if err != nil {
+			return markTransient(err)
+		}
+		// Expired receipts stay in the index until it expires itself, and do not count as originals
+		matches, _, err := r.loadReceipts(ctx, tx, ids)
+		if err != nil {
+			return err
+		}
+		if original, found := repository.EarliestReceipt(matches); found {
+			originalID = original.ID
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.Set(ctx, key, value, r.opts.TTL)
+			r.queueIndex(ctx, pipe, receipt)
+			return nil
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < maxWatchAttempts; attempt++ {
+		if err = r.client.Watch(ctx, save, indexKey, key); !errors.Is(err, goredis.TxFailedErr) {
+			break
+		}
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("unable to save receipt: %w", markTransient(err))
+	}
+	if originalID != "" {
+		return "", originalID, nil
+	}
+
+	return receiptID, "", nil
+}
+
+// Find retrieves a receipt by ID
+func (r *ReceiptStoreImpl) Find(ctx context.Context, id string) (domain.Receipt, error) {
+	value, err := r.client.Get(ctx, r.receiptKey(id)).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return domain.Receipt{}, r.missingReceiptError(ctx, id)
+	}
+	if err != nil {
+		return domain.Receipt{}, fmt.Errorf("unable to load receipt: %w", err)
+	}
+
+	return r.decode(value)
+}
+
+// Update replaces a receipt with a new revision, pushing the replaced version onto its revisions list.
+// The receipt key is watched so a concurrent update fails with ErrRevisionConflict instead of being lost.
+func (r *ReceiptStoreImpl) Update(ctx context.Context, receipt domain.Receipt, expectedRevision int) error {
+	value, err := r.encode(receipt)
+	if err != nil {
+		return err
+	}
+	key := r.receiptKey(receipt.ID)
+
+	return r.client.Watch(ctx, func(tx *goredis.Tx) error {
+		currentValue, err := tx.Get(ctx, key).Bytes()
+		if errors.Is(err, goredis.Nil) {
+			return r.missingReceiptError(ctx, receipt.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to load receipt: %w", err)
+		}
+		current, err := r.decode(currentValue)
+		if err != nil {
+			return err
+		}
+		if current.Revision != expectedRevision {
+			return repository.ErrRevisionConflict
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.RPush(ctx, r.revisionsKey(receipt.ID), currentValue)
+			if r.opts.TTL > 0 {
+				pipe.Expire(ctx, r.revisionsKey(receipt.ID), r.opts.TTL)
+			}
+			r.queueUnindex(ctx, pipe, current)
+			pipe.Set(ctx, key, value, r.opts.TTL)
+			r.queueIndex(ctx, pipe, receipt)
+			return nil
+		})
+		if errors.Is(err, goredis.TxFailedErr) {
+			// The receipt changed between the read and the write
+			return repository.ErrRevisionConflict
+		}
+		if err != nil {
+			return fmt.Errorf("unable to update receipt: %w", err)
+		}
+		return nil
+	}, key)
+}
+
+// Revisions returns the earlier revisions of a receipt, oldest first
+func (r *ReceiptStoreImpl) Revisions(ctx context.Context, id string) ([]domain.Receipt, error) {
+	exists, err := r.client.Exists(ctx, r.receiptKey(id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load receipt: %w", err)
+	}
+	if exists == 0 {
+		return nil, r.missingReceiptError(ctx, id)
+	}
+
+	values, err := r.client.LRange(ctx, r.revisionsKey(id), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load revisions: %w", err)
+	}
+
+	revisions := make([]domain.Receipt, 0, len(values))
+	for _, value := range values {
+		revision, err := r.decode([]byte(value))
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+// FindByRetailer returns all receipts whose retailer matches, ignoring case and surrounding whitespace
+func (r *ReceiptStoreImpl) FindByRetailer(ctx context.Context, retailer string) ([]domain.Receipt, error) {
+	return r.findByIndex(ctx, r.key("idx:retailer:"+repository.NormalizeRetailer(retailer)))
+}
+
+// FindByPurchaseDate returns all receipts with the given purchase date ("YYYY-MM-DD")
+func (r *ReceiptStoreImpl) FindByPurchaseDate(ctx context.Context, purchaseDate string) ([]domain.Receipt, error) {
+	return r.findByIndex(ctx, r.key("idx:date:"+purchaseDate))
+}
+
+// FindByFingerprint returns all receipts with the given canonical fingerprint
+func (r *ReceiptStoreImpl) FindByFingerprint(ctx context.Context, fingerprint string) ([]domain.Receipt, error) {
+	return r.findByIndex(ctx, r.key("idx:fingerprint:"+fingerprint))
+}
+
+// Delete removes a receipt and its index entries and writes its tombstone.
+// The receipt key is watched so a concurrent delete of the same receipt cannot write two tombstones.
+func (r *ReceiptStoreImpl) Delete(ctx context.Context, id string, tombstone domain.Tombstone) error {
+	tombstone.ReceiptID = id
+	key := r.receiptKey(id)
+
+	return r.client.Watch(ctx, func(tx *goredis.Tx) error {
+		value, err := tx.Get(ctx, key).Bytes()
+		if errors.Is(err, goredis.Nil) {
+			return r.missingReceiptError(ctx, id)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to load receipt: %w", err)
+		}
+		receipt, err := r.decode(value)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			return r.queueRemoval(ctx, pipe, receipt, tombstone)
+		})
+		if err != nil {
+			return fmt.Errorf("unable to delete receipt: %w", err)
+		}
+		return nil
+	}, key)
+}
+
+// Erase deletes every receipt matching the selector in one pipelined transaction,
+// using the retailer index set when erasing by retailer and the by-date set otherwise.
+// The index and every candidate receipt are watched, so a receipt saved or changed while the
+// candidates are being read is not missed; the erasure is then retried from a fresh read.
+func (r *ReceiptStoreImpl) Erase(ctx context.Context, selector repository.ErasureSelector, audit domain.Tombstone) ([]string, error) {
+	indexKey := r.key(dateSortedSet)
+	if selector.Retailer != "" {
+		indexKey = r.key("idx:retailer:" + repository.NormalizeRetailer(selector.Retailer))
+	}
+
+	var erased []string
+	erase := func(tx *goredis.Tx) error {
+		var ids []string
+		var err error
+		if selector.Retailer != "" {
+			ids, err = tx.SMembers(ctx, indexKey).Result()
+		} else {
+			ids, err = tx.ZRange(ctx, indexKey, 0, -1).Result()
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read receipt index: %w", markTransient(err))
+		}
+		if len(ids) > 0 {
+			if err := tx.Watch(ctx, r.receiptKeys(ids)...).Err(); err != nil {
+				return fmt.Errorf("unable to read receipt index: %w", markTransient(err))
+			}
+		}
+
+		candidates, expired, err := r.loadReceipts(ctx, tx, ids)
+		if err != nil {
+			return err
+		}
+
+		erased = []string{}
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			if len(expired) > 0 {
+				pipe.ZRem(ctx, r.key(dateSortedSet), expired)
+			}
+			for _, receipt := range candidates {
+				if !selector.Matches(receipt) {
+					continue
+				}
+				tombstone := audit
+				tombstone.ReceiptID = receipt.ID
+				if err := r.queueRemoval(ctx, pipe, receipt, tombstone); err != nil {
+					return err
+				}
+				erased = append(erased, receipt.ID)
+			}
+			return nil
+		})
+		return err
+	}
+
+	var err error
+	for attempt := 0; attempt < maxWatchAttempts; attempt++ {
+		if err = r.client.Watch(ctx, erase, indexKey); !errors.Is(err, goredis.TxFailedErr) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to erase receipts: %w", err)
+	}
+
+	sort.Strings(erased)
+	return erased, nil
+}
+
+// Query returns a page of receipts matching the query.
+//
+// Candidates come from the retailer index set when a retailer is given, and otherwise from a
+// score range of the by-date sorted set, which covers every receipt when no dates are given.
+func (r *ReceiptStoreImpl) Query(ctx context.Context, query repository.ReceiptQuery) (repository.ReceiptPage, error) {
+	var ids []string
+	var err error
+	if query.Retailer != "" {
+		ids, err = r.client.SMembers(ctx, r.key("idx:retailer:"+repository.NormalizeRetailer(query.Retailer))).Result()
+	} else {
+		scoreRange := &goredis.ZRangeBy{Min: "-inf", Max: "+inf"}
+		if query.PurchaseDateFrom != "" {
+			scoreRange.Min = strconv.FormatFloat(dateScore(query.PurchaseDateFrom), 'f', 0, 64)
+		}
+		if query.PurchaseDateTo != "" {
+			scoreRange.Max = strconv.FormatFloat(dateScore(query.PurchaseDateTo), 'f', 0, 64)
+		}
+		ids, err = r.client.ZRangeByScore(ctx, r.key(dateSortedSet), scoreRange).Result()
+	}
+	if err != nil {
+		return repository.ReceiptPage{}, fmt.Errorf("unable to read receipt index: %w", err)
+	}
+
+	candidates, expired, err := r.loadReceipts(ctx, r.client, ids)
+	if err != nil {
+		return repository.ReceiptPage{}, err
+	}
+	r.pruneExpired(ctx, expired)
+
+	return query.Paginate(candidates)
+}
+
+// Close closes the underlying Redis client
+func (r *ReceiptStoreImpl) Close() error {
+	return r.client.Close()
+}
+
+// queueRemoval queues the commands that delete a receipt, drop its index entries and write its tombstone
+func (r *ReceiptStoreImpl) queueRemoval(ctx context.Context, pipe goredis.Pipeliner, receipt domain.Receipt, tombstone domain.Tombstone) error {
+	value, err := json.Marshal(tombstone)
+	if err != nil {
+		return fmt.Errorf("unable to encode tombstone: %w", err)
+	}
+
+	pipe.Del(ctx, r.receiptKey(receipt.ID), r.revisionsKey(receipt.ID))
+	r.queueUnindex(ctx, pipe, receipt)
+	pipe.Set(ctx, r.tombstoneKey(receipt.ID), value, 0)
+	return nil
+}
+
+// queueIndex queues the commands that add a receipt to the retailer, date, fingerprint and by-date indexes
+func (r *ReceiptStoreImpl) queueIndex(ctx context.Context, pipe goredis.Pipeliner, receipt domain.Receipt) {
+	pipe.ZAdd(ctx, r.key(dateSortedSet), goredis.Z{Score: dateScore(receipt.PurchaseDate), Member: receipt.ID})
+	for _, indexKey := range r.indexKeys(receipt) {
+		pipe.SAdd(ctx, indexKey, receipt.ID)
+		if r.opts.TTL > 0 {
+			pipe.Expire(ctx, indexKey, r.opts.TTL)
+		}
+	}
+}
+
+// queueUnindex queues the commands that remove a receipt from every index
+func (r *ReceiptStoreImpl) queueUnindex(ctx context.Context, pipe goredis.Pipeliner, receipt domain.Receipt) {
+	for _, indexKey := range r.indexKeys(receipt) {
+		pipe.SRem(ctx, indexKey, receipt.ID)
+	}
+	pipe.ZRem(ctx, r.key(dateSortedSet), receipt.ID)
+}
+
+// indexKeys returns the index sets a receipt belongs to
+func (r *ReceiptStoreImpl) indexKeys(receipt domain.Receipt) []string {
+	keys := []string{
+		r.key("idx:retailer:" + repository.NormalizeRetailer(receipt.Retailer)),
+		r.key("idx:date:" + receipt.PurchaseDate),
+	}
+	if receipt.Fingerprint != "" {
+		keys = append(keys, r.key("idx:fingerprint:"+receipt.Fingerprint))
+	}
+	return keys
+}
+
+// missingReceiptError distinguishes a deleted receipt from one that never existed (or has expired)
+func (r *ReceiptStoreImpl) missingReceiptError(ctx context.Context, id string) error {
+	exists, err := r.client.Exists(ctx, r.tombstoneKey(id)).Result()
+	if err != nil {
+		return fmt.Errorf("unable to load tombstone: %w", err)
+	}
+	if exists > 0 {
+		return repository.ErrReceiptDeleted
+	}
+	return repository.ErrReceiptNotFound
+}
+
+// findByIndex loads every receipt referenced by an index set, skipping IDs whose receipt has expired
+func (r *ReceiptStoreImpl) findByIndex(ctx context.Context, indexKey string) ([]domain.Receipt, error) {
+	ids, err := r.client.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read index '%s': %w", indexKey, markTransient(err))
+	}
+
+	receipts, expired, err := r.loadReceipts(ctx, r.client, ids)
+	if err != nil {
+		return nil, err
+	}
+	r.pruneExpired(ctx, expired)
+	return receipts, nil
+}
+
+// loadReceipts fetches receipts by ID in a single round trip, skipping IDs whose receipt has expired,
+// which are returned separately so they can be pruned from the by-date set
+func (r *ReceiptStoreImpl) loadReceipts(ctx context.Context, cmd goredis.Cmdable, ids []string) (receipts []domain.Receipt, expired []string, err error) {
+	receipts = []domain.Receipt{}
+	if len(ids) == 0 {
+		return receipts, nil, nil
+	}
+
+	values, err := cmd.MGet(ctx, r.receiptKeys(ids)...).Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to load receipts: %w", markTransient(err))
+	}
+
+	for i, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			expired = append(expired, ids[i])
+			continue
+		}
+		receipt, err := r.decode([]byte(s))
+		if err != nil {
+			return nil, nil, err
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, expired, nil
+}
+
+// pruneExpired drops expired receipts from the by-date set, which unlike the other indexes has no TTL
+// of its own. It is best effort: a failure only leaves the IDs to be pruned by a later read.
+func (r *ReceiptStoreImpl) pruneExpired(ctx context.Context, expired []string) {
+	if len(expired) == 0 {
+		return
+	}
+	keys := append([]string{r.key(dateSortedSet)}, r.receiptKeys(expired)...)
+	args := make([]interface{}, len(expired))
+	for i, id := range expired {
+		args[i] = id
+	}
+	pruneScript.Run(ctx, r.client, keys, args...)
+}
+
+func (r *ReceiptStoreImpl) encode(receipt domain.Receipt) ([]byte, error) {
+	var value []byte
+	var err error
+	if r.opts.Encoding == EncodingMsgpack {
+		value, err = marshalMsgpack(receipt)
+	} else {
+		value, err = json.Marshal(receipt)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode receipt: %v", err)
+	}
+	return value, nil
+}
+
+func (r *ReceiptStoreImpl) decode(value []byte) (domain.Receipt, error) {
+	var receipt domain.Receipt
+	var err error
+	if r.opts.Encoding == EncodingMsgpack {
+		err = unmarshalMsgpack(value, &receipt)
+	} else {
+		err = json.Unmarshal(value, &receipt)
+	}
+	if err != nil {
+		return receipt, fmt.Errorf("unable to decode receipt: %v", err)
+	}
+	return receipt, nil
+}
+
+func (r *ReceiptStoreImpl) key(suffix string) string {
+	return r.opts.KeyPrefix + suffix
+}
+
+func (r *ReceiptStoreImpl) receiptKey(id string) string {
+	return r.key("receipt:" + id)
+}
+
+func (r *ReceiptStoreImpl) receiptKeys(ids []string) []string {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = r.receiptKey(id)
+	}
+	return keys
+}
+
+func (r *ReceiptStoreImpl) tombstoneKey(id string) string {
+	return r.key("tombstone:" + id)
+}
+
+func (r *ReceiptStoreImpl) revisionsKey(id string) string {
+	return r.key("revisions:" + id)
+}
+
+// marshalMsgpack encodes v using its json struct tags so field names match the JSON encoding
+func marshalMsgpack(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshalMsgpack decodes data produced by marshalMsgpack into v
+func unmarshalMsgpack(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// markTransient wraps err in a repository.TransientError when Redis may accept the command if it is retried:
+// a dropped or timed-out connection, or a server that is loading, failing over or out of client slots
+func markTransient(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return &repository.TransientError{Err: err}
+	}
+	message := err.Error()
+	for _, prefix := range []string{"LOADING ", "READONLY ", "TRYAGAIN ", "CLUSTERDOWN ", "MASTERDOWN ", "ERR max number of clients"} {
+		if strings.HasPrefix(message, prefix) {
+			return &repository.TransientError{Err: err}
+		}
+	}
+	return err
+}
+
+// dateScore converts a "YYYY-MM-DD" purchase date into a sortable sorted-set score (YYYYMMDD)
+func dateScore(purchaseDate string) float64 {
+	score, err := strconv.ParseFloat(strings.ReplaceAll(purchaseDate, "-", ""), 64)
+	if err != nil {
+		return 0
+	}
+	return score
+}
@@ -0,0 +1,257 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/pkg/utils"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers sent with every delivery.
+const (
+	HeaderSignature = "X-Webhook-Signature" // "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>"
+	HeaderTimestamp = "X-Webhook-Timestamp" // Unix seconds at which the attempt was signed
+	HeaderEvent     = "X-Webhook-Event"     // Type of the delivered event
+	HeaderEventID   = "X-Webhook-Event-Id"  // ID of the delivered event, identical across retries
+)
+
+// Options tunes how a Dispatcher delivers events.
+type Options struct {
+	Workers        int           // Deliveries made concurrently
+	BufferSize     int           // Events waiting for a worker before new ones are dropped
+	MaxAttempts    int           // Attempts per subscription before an event is given up on
+	InitialBackoff time.Duration // Wait before the first retry; each later wait doubles
+	MaxBackoff     time.Duration // Upper bound on the wait between retries
+	Timeout        time.Duration // Upper bound on each HTTP attempt
+}
+
+// DefaultOptions are used for any Options field left at zero.
+var DefaultOptions = Options{
+	Workers:        4,
+	BufferSize:     1000,
+	MaxAttempts:    5,
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+	Timeout:        5 * time.Second,
+}
+
+// Dispatcher is a repository.EventPublisher that POSTs each published ReceiptEvent to the
+// subscriptions that asked for it, signing every delivery and recording every attempt.
+// Failed deliveries wait for their retry on a timer rather than in a worker, so a receiver that keeps
+// failing never delays the events of the others.
+type Dispatcher struct {
+	store   repository.WebhookStore
+	client  *http.Client
+	options Options
+	events  chan domain.ReceiptEvent
+	retries chan pendingDelivery // Deliveries whose backoff has elapsed, waiting for a worker
+}
+
+// pendingDelivery is the next attempt at delivering an event to one subscription
+type pendingDelivery struct {
+	subscription domain.WebhookSubscription
+	event        domain.ReceiptEvent
+	body         []byte
+	attempt      int // 1 for the first attempt
+}
+
+// NewDispatcher
+//
+// Parameters:
+//   - store: The WebhookStore holding subscriptions and receiving the delivery log.
+//   - client: The HTTP client used for deliveries; nil uses a client with options.Timeout.
+//   - options: Delivery settings; zero fields fall back to DefaultOptions.
+//
+// Returns:
+//   - A new instance of Dispatcher. Nothing is delivered until Run is called.
+func NewDispatcher(store repository.WebhookStore, client *http.Client, options Options) *Dispatcher {
+	if options.Workers <= 0 {
+		options.Workers = DefaultOptions.Workers
+	}
+	if options.BufferSize <= 0 {
+		options.BufferSize = DefaultOptions.BufferSize
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultOptions.MaxAttempts
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = DefaultOptions.InitialBackoff
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = DefaultOptions.MaxBackoff
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultOptions.Timeout
+	}
+	if client == nil {
+		client = &http.Client{Timeout: options.Timeout}
+	}
+	return &Dispatcher{
+		store:   store,
+		client:  client,
+		options: options,
+		events:  make(chan domain.ReceiptEvent, options.BufferSize),
+		retries: make(chan pendingDelivery),
+	}
+}
+
+// Publish queues an event for delivery without waiting. If the buffer is full the event is dropped and logged,
+// so a slow or unreachable receiver can never hold up receipt processing.
+func (d *Dispatcher) Publish(event domain.ReceiptEvent) {
+	select {
+	case d.events <- event:
+	default:
+		log.Printf("webhook dispatcher: buffer full, dropping %s event %s", event.Type, event.ID)
+	}
+}
+
+// Run
+//
+// Parameters:
+//   - ctx: Cancelling it stops the workers; deliveries in progress and retries waiting for their backoff are abandoned.
+//
+// Returns once every worker has stopped.
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for w := 0; w < d.options.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event := <-d.events:
+					d.dispatch(ctx, event)
+				case pending := <-d.retries:
+					d.deliver(ctx, pending)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// dispatch delivers an event to every subscription that asked for its type
+func (d *Dispatcher) dispatch(ctx context.Context, event domain.ReceiptEvent) {
+	subscriptions, err := d.store.Subscriptions(ctx)
+	if err != nil {
+		log.Printf("webhook dispatcher: listing subscriptions for event %s: %v", event.ID, err)
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("webhook dispatcher: encoding event %s: %v", event.ID, err)
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		if subscription.Wants(event.Type) {
+			d.deliver(ctx, pendingDelivery{subscription: subscription, event: event, body: body, attempt: 1})
+		}
+	}
+}
+
+// deliver makes one attempt at a delivery and records it. A failed attempt is retried after an
+// exponential backoff until it is accepted or MaxAttempts is reached.
+func (d *Dispatcher) deliver(ctx context.Context, pending pendingDelivery) {
+	delivery := d.attempt(ctx, pending.subscription, pending.event, pending.body)
+	delivery.Attempt = pending.attempt
+	delivery.DurationMs = time.Since(delivery.AttemptedAt).Milliseconds()
+	if err := d.store.RecordDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		// The subscription was deleted mid-delivery; stop retrying it
+		return
+	}
+	if delivery.Succeeded {
+		return
+	}
+	if pending.attempt >= d.options.MaxAttempts {
+		log.Printf("webhook dispatcher: giving up on event %s for subscription %s after %d attempts",
+			pending.event.ID, pending.subscription.ID, d.options.MaxAttempts)
+		return
+	}
+	d.retryLater(ctx, pending)
+}
+
+// retryLater hands the next attempt at a failed delivery back to the workers once its backoff has elapsed,
+// leaving the worker that made the failed attempt free for other deliveries in the meantime
+func (d *Dispatcher) retryLater(ctx context.Context, failed pendingDelivery) {
+	next := failed
+	next.attempt++
+	time.AfterFunc(utils.ExponentialBackoff(d.options.InitialBackoff, d.options.MaxBackoff, failed.attempt), func() {
+		select {
+		case d.retries <- next:
+		case <-ctx.Done():
+		}
+	})
+}
+
+// attempt makes a single signed POST and describes its outcome
+func (d *Dispatcher) attempt(ctx context.Context, subscription domain.WebhookSubscription, event domain.ReceiptEvent, body []byte) domain.WebhookDelivery {
+	delivery := domain.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		AttemptedAt:    time.Now().UTC(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	timestamp := strconv.FormatInt(delivery.AttemptedAt.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, body))
+	req.Header.Set(HeaderEvent, event.Type)
+	req.Header.Set(HeaderEventID, event.ID)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	delivery.StatusCode = resp.StatusCode
+	delivery.Succeeded = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !delivery.Succeeded {
+		delivery.Error = fmt.Sprintf("receiver responded %s", resp.Status)
+	}
+	return delivery
+}
+
+// Sign
+//
+// Receivers verify a delivery by recomputing this value from the X-Webhook-Timestamp header and the raw
+// request body, comparing it to X-Webhook-Signature in constant time, and rejecting stale timestamps.
+//
+// Parameters:
+//   - secret: The subscription's secret.
+//   - timestamp: The value of the X-Webhook-Timestamp header.
+//   - body: The raw request body.
+//
+// Returns:
+//   - The X-Webhook-Signature header value: "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>".
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
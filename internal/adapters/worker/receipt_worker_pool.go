@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"context"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"log"
+	"sync"
+)
+
+// ReceiptWorkerPool drives asynchronous processing: each of its workers repeatedly asks the
+// ReceiptService to take the next queued receipt, score it and store it.
+type ReceiptWorkerPool struct {
+	ReceiptService internalHttp.ReceiptService
+	Workers        int // Receipts processed concurrently
+}
+
+// NewReceiptWorkerPool
+//
+// Parameters:
+//   - service: The ReceiptService whose queue the workers drain.
+//   - workers: How many receipts to process concurrently; values below one are treated as one.
+//
+// Returns:
+//   - A new instance of ReceiptWorkerPool.
+func NewReceiptWorkerPool(service internalHttp.ReceiptService, workers int) *ReceiptWorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+	return &ReceiptWorkerPool{ReceiptService: service, Workers: workers}
+}
+
+// Run
+//
+// Failed jobs are logged and recorded in the queue; they do not stop the worker.
+//
+// Parameters:
+//   - ctx: Cancelling it stops the workers once their current receipt is done.
+//
+// Returns once every worker has stopped.
+func (p *ReceiptWorkerPool) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for w := 0; w < p.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				if err := p.ReceiptService.ProcessNextJob(ctx); err != nil && ctx.Err() == nil {
+					log.Printf("receipt worker: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
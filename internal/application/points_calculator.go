@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"go-receipt-processor/internal/domain"
 	"go-receipt-processor/internal/ports/core"
 	"go-receipt-processor/pkg/utils"
@@ -21,51 +22,76 @@ func NewPointsCalculator(helpers http.PointsCalculatorRules) http.PointsCalculat
 // CalculatePoints
 //
 // Parameters:
+//   - ctx: The request context; scoring stops early if it is cancelled.
 //   - receipt: The domain.Receipt object containing receipt details.
 //
-// Returns: A time.TIme value representing the the exact time of the purchase
+// Returns:
 //   - points: The total points awarded for the receipt.
 //   - err: An error if calculating points fails
-func (c *PointsCalculatorImpl) CalculatePoints(receipt domain.Receipt) (int, error) {
+func (c *PointsCalculatorImpl) CalculatePoints(ctx context.Context, receipt domain.Receipt) (int, error) {
+	breakdown, err := c.Breakdown(ctx, receipt)
+	if err != nil {
+		return 0, err
+	}
+
 	points := 0
+	for _, rule := range breakdown {
+		points += rule.Points
+	}
+	return points, nil
+}
+
+// Breakdown
+//
+// Parameters:
+//   - ctx: The request context; scoring stops early if it is cancelled.
+//   - receipt: The domain.Receipt object containing receipt details.
+//
+// Returns:
+//   - breakdown: The points awarded by each rule, in the order the rules are applied, including rules awarding none.
+//   - err: An error if calculating points fails
+func (c *PointsCalculatorImpl) Breakdown(ctx context.Context, receipt domain.Receipt) ([]domain.RulePoints, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	parsedDateAndTime, err := utils.ParseReceiptDateTime(receipt)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	// Rule 1.
-	points += c.helpers.AddPointsForRetailerName(receipt)
+	breakdown := []domain.RulePoints{{Rule: domain.RuleRetailerName, Points: c.helpers.AddPointsForRetailerName(receipt)}}
 
 	// Rule 2.
 	roundDollarPoints, err := c.helpers.AddPointsForRoundDollarTotal(receipt)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	points += roundDollarPoints
+	breakdown = append(breakdown, domain.RulePoints{Rule: domain.RuleRoundDollarTotal, Points: roundDollarPoints})
 
 	// Rule 3.
 	multipleOfQuarterPoints, err := c.helpers.AddPointsForMultipleOfQuarter(receipt)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	points += multipleOfQuarterPoints
+	breakdown = append(breakdown, domain.RulePoints{Rule: domain.RuleMultipleOfQuarter, Points: multipleOfQuarterPoints})
 
 	// Rule 4.
-	points += c.helpers.AddPointsForItemCount(receipt)
+	breakdown = append(breakdown, domain.RulePoints{Rule: domain.RuleItemCount, Points: c.helpers.AddPointsForItemCount(receipt)})
 
 	// Rule 5.
 	itemPoints, err := c.helpers.AddPointsForItemDescriptions(receipt)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	points += itemPoints
+	breakdown = append(breakdown, domain.RulePoints{Rule: domain.RuleItemDescriptions, Points: itemPoints})
 
 	// Rule 6.
-	points += c.helpers.AddPointsForOddDay(parsedDateAndTime)
+	breakdown = append(breakdown, domain.RulePoints{Rule: domain.RuleOddDay, Points: c.helpers.AddPointsForOddDay(parsedDateAndTime)})
 
 	// Rule 7.
-	points += c.helpers.AddPointsForAfternoonPurchaseTime(parsedDateAndTime)
+	breakdown = append(breakdown, domain.RulePoints{Rule: domain.RuleAfternoonPurchaseTime, Points: c.helpers.AddPointsForAfternoonPurchaseTime(parsedDateAndTime)})
 
-	return points, nil
+	return breakdown, nil
 }
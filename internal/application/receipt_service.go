@@ -1,16 +1,94 @@
 package application
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"go-receipt-processor/internal/domain"
 	http "go-receipt-processor/internal/ports/core"
 	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/pkg/utils"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScoringVersion identifies the set of points rules applied by PointsCalculatorImpl.
+// It is stored with every receipt so points can be traced back to the rules that produced them.
+const ScoringVersion = "v1"
+
+// DuplicatePolicy decides what happens to a receipt whose fingerprint matches one already stored.
+type DuplicatePolicy string
+
+// Supported values for DuplicatePolicy.
+const (
+	DuplicatePolicyOff    DuplicatePolicy = "off"    // Duplicates are stored like any other receipt
+	DuplicatePolicyFlag   DuplicatePolicy = "flag"   // Duplicates are stored with DuplicateOf set to the original's ID
+	DuplicatePolicyReject DuplicatePolicy = "reject" // Duplicates are refused with a DuplicateReceiptError
 )
 
 // ReceiptServiceImpl is an implementation of the ReceiptService interface.
 type ReceiptServiceImpl struct {
 	PointsCalculator http.PointsCalculator
 	ReceiptStore     repository.ReceiptStore
+	BatchWorkers     int                     // Receipts of a batch processed concurrently
+	DuplicatePolicy  DuplicatePolicy         // How resubmissions of a stored receipt are handled
+	Queue            repository.ReceiptQueue // Receipts waiting for asynchronous processing; nil disables it
+	RetryPolicy      RetryPolicy             // Retries of queued receipts after transient store failures
+	DeadLetters      repository.DeadLetterStore
+	Publishers       []repository.EventPublisher // Notified after each receipt is stored, corrected or deleted
+}
+
+// ReceiptServiceOption customizes a ReceiptServiceImpl built by NewReceiptService.
+type ReceiptServiceOption func(*ReceiptServiceImpl)
+
+// WithBatchWorkers bounds how many receipts of a batch are scored and stored concurrently.
+// Zero or negative values keep the default of one worker per CPU.
+func WithBatchWorkers(workers int) ReceiptServiceOption {
+	return func(s *ReceiptServiceImpl) {
+		if workers > 0 {
+			s.BatchWorkers = workers
+		}
+	}
+}
+
+// WithDuplicatePolicy sets how receipts matching the fingerprint of a stored receipt are handled.
+// The default is DuplicatePolicyOff.
+func WithDuplicatePolicy(policy DuplicatePolicy) ReceiptServiceOption {
+	return func(s *ReceiptServiceImpl) {
+		s.DuplicatePolicy = policy
+	}
+}
+
+// WithReceiptQueue enables asynchronous processing through EnqueueReceipt and ProcessNextJob.
+func WithReceiptQueue(queue repository.ReceiptQueue) ReceiptServiceOption {
+	return func(s *ReceiptServiceImpl) {
+		s.Queue = queue
+	}
+}
+
+// WithRetryPolicy sets how queued receipts are retried after transient store failures.
+func WithRetryPolicy(policy RetryPolicy) ReceiptServiceOption {
+	return func(s *ReceiptServiceImpl) {
+		s.RetryPolicy = policy
+	}
+}
+
+// WithDeadLetterStore keeps queued receipts that fail permanently, or run out of retries, in store
+// so they can be inspected and requeued.
+func WithDeadLetterStore(store repository.DeadLetterStore) ReceiptServiceOption {
+	return func(s *ReceiptServiceImpl) {
+		s.DeadLetters = store
+	}
+}
+
+// WithEventPublisher adds a publisher notified of every ReceiptEvent. It may be given more than once.
+func WithEventPublisher(publisher repository.EventPublisher) ReceiptServiceOption {
+	return func(s *ReceiptServiceImpl) {
+		s.Publishers = append(s.Publishers, publisher)
+	}
 }
 
 // NewReceiptService
@@ -18,55 +96,611 @@ type ReceiptServiceImpl struct {
 // Parameters:
 //   - c: The PointsCalculator used to calculate points for a receipt.
 //   - rs: The ReceiptStore used to store and retrieve receipts.
+//   - opts: Optional settings such as WithBatchWorkers, WithDuplicatePolicy and WithReceiptQueue.
 //
 // Returns:
 //   - A new instance of ReceiptServiceImpl with the provided dependencies.
-func NewReceiptService(c http.PointsCalculator, rs repository.ReceiptStore) http.ReceiptService {
-	return &ReceiptServiceImpl{
+func NewReceiptService(c http.PointsCalculator, rs repository.ReceiptStore, opts ...ReceiptServiceOption) http.ReceiptService {
+	s := &ReceiptServiceImpl{
 		PointsCalculator: c,
 		ReceiptStore:     rs,
+		BatchWorkers:     runtime.NumCPU(),
+		DuplicatePolicy:  DuplicatePolicyOff,
+		RetryPolicy:      DefaultRetryPolicy,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // ProcessReceipt
 //
+// Every receipt is stored with its canonical fingerprint. Unless DuplicatePolicy is off, the fingerprint
+// is first looked up to find an earlier submission of the same receipt, which is then flagged or rejected.
+// A receipt with no earlier submission is saved with ReceiptStore.SaveUnique, so of several identical
+// receipts submitted at once, only one is stored as the original.
+//
 // Parameters:
+//   - ctx: The request context; cancelling it abandons the store call.
 //   - receipt: The domain.Receipt object containing receipt details.
 //
 // Returns:
 //   - receiptID: A unique identifier for the processed receipt.
-//   - err: An error if processing or saving fails.
-func (s *ReceiptServiceImpl) ProcessReceipt(receipt domain.Receipt) (string, error) {
-	points, err := s.PointsCalculator.CalculatePoints(receipt)
+//   - err: An error wrapping a DuplicateReceiptError if the receipt is rejected as a duplicate,
+//     or an error if processing or saving fails.
+func (s *ReceiptServiceImpl) ProcessReceipt(ctx context.Context, receipt domain.Receipt) (string, error) {
+	receipt.ID = ""
+	return s.scoreAndSave(ctx, receipt)
+}
+
+// scoreAndSave checks a receipt for duplicates, scores it and saves it under receipt.ID, or a new ID if it has none
+func (s *ReceiptServiceImpl) scoreAndSave(ctx context.Context, receipt domain.Receipt) (string, error) {
+	fingerprint := receipt.CanonicalFingerprint()
+	duplicateOf, err := s.checkDuplicate(ctx, receipt.ID, fingerprint)
+	if err != nil {
+		return "", err
+	}
+
+	points, err := s.PointsCalculator.CalculatePoints(ctx, receipt)
 	if err != nil {
-		return "", fmt.Errorf("unable to process receipt: %v", err)
+		return "", fmt.Errorf("unable to process receipt: %w", err)
 	}
 
 	receipt.Points = points
+	receipt.Fingerprint = fingerprint
+	receipt.DuplicateOf = duplicateOf
+	receipt.ReceivedAt = time.Now().UTC()
+	receipt.ScoringVersion = ScoringVersion
+	receipt.Revision = 1
+	receipt.PointsDelta = 0
+	receipt.RevisedAt = time.Time{}
 
-	receiptID, err := s.ReceiptStore.Save(receipt)
+	receiptID, originalID, err := s.save(ctx, receipt)
 	if err != nil {
-		return "", fmt.Errorf("failed to insert receipt: %v", err)
+		return "", fmt.Errorf("failed to insert receipt: %w", err)
+	}
+	if originalID != "" {
+		return "", fmt.Errorf("unable to process receipt: %w", &http.DuplicateReceiptError{OriginalID: originalID})
 	}
 
+	s.publish(domain.EventReceiptScored, receiptID, receipt.Retailer, points)
 	return receiptID, nil
 }
 
+// save stores a scored receipt. Unless duplicates are allowed or it is already flagged as one, the receipt is
+// saved only if no receipt with its fingerprint was stored since checkDuplicate looked. When one was, a rejected
+// receipt is not saved and the original's ID is returned instead, while a flagged one is saved as its duplicate.
+func (s *ReceiptServiceImpl) save(ctx context.Context, receipt domain.Receipt) (receiptID, originalID string, err error) {
+	if receipt.DuplicateOf != "" || (s.DuplicatePolicy != DuplicatePolicyFlag && s.DuplicatePolicy != DuplicatePolicyReject) {
+		receiptID, err = s.ReceiptStore.Save(ctx, receipt)
+		return receiptID, "", err
+	}
+
+	receiptID, originalID, err = s.ReceiptStore.SaveUnique(ctx, receipt)
+	if err != nil || originalID == "" || s.DuplicatePolicy == DuplicatePolicyReject {
+		return receiptID, originalID, err
+	}
+
+	receipt.DuplicateOf = originalID
+	receiptID, err = s.ReceiptStore.Save(ctx, receipt)
+	return receiptID, "", err
+}
+
+// checkDuplicate applies DuplicatePolicy to a receipt's fingerprint, returning the ID of the receipt it
+// duplicates when duplicates are flagged and a DuplicateReceiptError when they are rejected. A receipt
+// stored under receiptID is the receipt itself, saved by an earlier attempt, and not an original it duplicates.
+func (s *ReceiptServiceImpl) checkDuplicate(ctx context.Context, receiptID, fingerprint string) (string, error) {
+	if s.DuplicatePolicy != DuplicatePolicyFlag && s.DuplicatePolicy != DuplicatePolicyReject {
+		return "", nil
+	}
+
+	originalID, err := s.findOriginal(ctx, receiptID, fingerprint)
+	if err != nil {
+		return "", fmt.Errorf("unable to check for duplicates: %w", err)
+	}
+	if originalID != "" && s.DuplicatePolicy == DuplicatePolicyReject {
+		return "", fmt.Errorf("unable to process receipt: %w", &http.DuplicateReceiptError{OriginalID: originalID})
+	}
+	return originalID, nil
+}
+
+// EnqueueReceipt
+//
+// Rejected duplicates are refused here, before queueing, when the original is already stored.
+//
+// Parameters:
+//   - ctx: The request context; cancelling it abandons the queue call.
+//   - receipt: The domain.Receipt object containing receipt details.
+//
+// Returns:
+//   - job: The pending job; its ID is the ID the receipt will be stored under.
+//   - err: An error wrapping ErrQueueFull if the queue is at capacity, a DuplicateReceiptError
+//     if the receipt is rejected as a duplicate, or an error if asynchronous processing is not enabled.
+func (s *ReceiptServiceImpl) EnqueueReceipt(ctx context.Context, receipt domain.Receipt) (domain.ReceiptJob, error) {
+	if s.Queue == nil {
+		return domain.ReceiptJob{}, fmt.Errorf("asynchronous processing is not enabled")
+	}
+	if _, err := s.checkDuplicate(ctx, "", receipt.CanonicalFingerprint()); err != nil {
+		return domain.ReceiptJob{}, err
+	}
+
+	now := time.Now().UTC()
+	job := domain.ReceiptJob{
+		ID:         uuid.New().String(),
+		Receipt:    receipt,
+		Status:     domain.JobStatusPending,
+		EnqueuedAt: now,
+		UpdatedAt:  now,
+	}
+	job.Receipt.ID = ""
+
+	if err := s.Queue.Enqueue(ctx, job); err != nil {
+		return domain.ReceiptJob{}, fmt.Errorf("unable to enqueue receipt: %w", err)
+	}
+
+	return job, nil
+}
+
+// ProcessNextJob
+//
+// Store failures marked as a repository.TransientError are retried with exponential backoff according to RetryPolicy.
+// Receipts that cannot be processed, or are still failing after the last attempt, are moved to the dead-letter store if there is one.
+//
+// Parameters:
+//   - ctx: Bounds the wait for a job and its processing; workers pass a context that lives until shutdown.
+//
+// Returns:
+//   - err: The context's error if it is done before a job arrives, or the reason the job failed.
+//     Either way the job's outcome has been recorded in the queue.
+func (s *ReceiptServiceImpl) ProcessNextJob(ctx context.Context) error {
+	if s.Queue == nil {
+		return fmt.Errorf("asynchronous processing is not enabled")
+	}
+
+	job, err := s.Queue.Dequeue(ctx)
+	if err != nil {
+		return err
+	}
+
+	receipt := job.Receipt
+	receipt.ID = job.ID
+
+	var processErr error
+	attempts := 1
+	for ; ; attempts++ {
+		_, processErr = s.scoreAndSave(ctx, receipt)
+		if processErr == nil || !isTransient(processErr) || attempts >= s.RetryPolicy.MaxAttempts {
+			break
+		}
+		if utils.SleepContext(ctx, s.RetryPolicy.Backoff(attempts)) != nil {
+			break
+		}
+	}
+
+	// Record the outcome even if ctx was cancelled mid-job, so the job does not stay "processing"
+	finishCtx := context.WithoutCancel(ctx)
+	var deadLetterErr error
+	if processErr != nil && s.DeadLetters != nil {
+		deadLetterErr = s.DeadLetters.Add(finishCtx, domain.DeadLetter{
+			ID:         job.ID,
+			Receipt:    job.Receipt,
+			Reason:     processErr.Error(),
+			Attempts:   attempts,
+			EnqueuedAt: job.EnqueuedAt,
+			FailedAt:   time.Now().UTC(),
+		})
+	}
+	// A dead-lettered job is no longer tracked by the queue, which would otherwise keep every failure forever;
+	// its dead letter reports the failure until it is requeued
+	var finishErr error
+	if processErr != nil && s.DeadLetters != nil && deadLetterErr == nil {
+		finishErr = s.Queue.Remove(finishCtx, job.ID)
+	} else {
+		finishErr = s.Queue.Finish(finishCtx, job.ID, processErr)
+	}
+	if finishErr != nil {
+		return fmt.Errorf("unable to finish job %s: %w", job.ID, finishErr)
+	}
+	if deadLetterErr != nil {
+		return fmt.Errorf("job %s failed (%v) and could not be dead-lettered: %w", job.ID, processErr, deadLetterErr)
+	}
+	if processErr != nil {
+		return fmt.Errorf("job %s failed after %d attempt(s): %w", job.ID, attempts, processErr)
+	}
+	return nil
+}
+
+// ListDeadLetters
+//
+// Parameters:
+//   - ctx: The request context; cancelling it abandons the store call.
+//
+// Returns:
+//   - letters: Every queued receipt that could not be processed, oldest failure first.
+//   - err: An error if dead-letter handling is not enabled or the store fails.
+func (s *ReceiptServiceImpl) ListDeadLetters(ctx context.Context) ([]domain.DeadLetter, error) {
+	if s.DeadLetters == nil {
+		return nil, fmt.Errorf("dead-letter handling is not enabled")
+	}
+
+	letters, err := s.DeadLetters.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	return letters, nil
+}
+
+// GetDeadLetter
+//
+// Parameters:
+//   - ctx: The request context; cancelling it abandons the store call.
+//   - id: The ID of the failed job.
+//
+// Returns:
+//   - letter: The receipt that could not be processed and why.
+//   - err: An error wrapping ErrDeadLetterNotFound for unknown IDs, or any other failure.
+func (s *ReceiptServiceImpl) GetDeadLetter(ctx context.Context, id string) (domain.DeadLetter, error) {
+	if s.DeadLetters == nil {
+		return domain.DeadLetter{}, fmt.Errorf("dead-letter handling is not enabled")
+	}
+
+	letter, err := s.DeadLetters.Get(ctx, id)
+	if err != nil {
+		return domain.DeadLetter{}, fmt.Errorf("failed to find dead letter: %w", err)
+	}
+
+	return letter, nil
+}
+
+// RequeueDeadLetter
+//
+// The dead letter is removed before the receipt is queued, so concurrent requeues of the same ID
+// queue it only once; it is put back if the receipt cannot be queued.
+//
+// Parameters:
+//   - ctx: The request context; cancelling it abandons the store and queue calls.
+//   - id: The ID of the failed job, which the receipt keeps.
+//
+// Returns:
+//   - job: The new pending job.
+//   - err: An error wrapping ErrDeadLetterNotFound for unknown IDs, ErrQueueFull if the queue is at capacity,
+//     or any other failure.
+func (s *ReceiptServiceImpl) RequeueDeadLetter(ctx context.Context, id string) (domain.ReceiptJob, error) {
+	if s.DeadLetters == nil || s.Queue == nil {
+		return domain.ReceiptJob{}, fmt.Errorf("dead-letter handling is not enabled")
+	}
+
+	letter, err := s.DeadLetters.Get(ctx, id)
+	if err != nil {
+		return domain.ReceiptJob{}, fmt.Errorf("failed to find dead letter: %w", err)
+	}
+	if err := s.DeadLetters.Remove(ctx, id); err != nil {
+		return domain.ReceiptJob{}, fmt.Errorf("failed to remove dead letter: %w", err)
+	}
+
+	now := time.Now().UTC()
+	job := domain.ReceiptJob{
+		ID:         letter.ID,
+		Receipt:    letter.Receipt,
+		Status:     domain.JobStatusPending,
+		EnqueuedAt: now,
+		UpdatedAt:  now,
+	}
+	if err := s.Queue.Enqueue(ctx, job); err != nil {
+		if restoreErr := s.DeadLetters.Add(context.WithoutCancel(ctx), letter); restoreErr != nil {
+			return domain.ReceiptJob{}, fmt.Errorf("unable to enqueue receipt: %v; restoring dead letter failed: %w", err, restoreErr)
+		}
+		return domain.ReceiptJob{}, fmt.Errorf("unable to enqueue receipt: %w", err)
+	}
+
+	return job, nil
+}
+
+// findOriginal returns the ID of the earliest stored receipt with the fingerprint other than receiptID,
+// or "" if there is none
+func (s *ReceiptServiceImpl) findOriginal(ctx context.Context, receiptID, fingerprint string) (string, error) {
+	matches, err := s.ReceiptStore.FindByFingerprint(ctx, fingerprint)
+	if err != nil {
+		return "", err
+	}
+
+	var others []domain.Receipt
+	for _, match := range matches {
+		if match.ID != receiptID {
+			others = append(others, match)
+		}
+	}
+	original, _ := repository.EarliestReceipt(others)
+	return original.ID, nil
+}
+
+// ProcessReceipts
+//
+// Receipts are processed independently by a pool of at most BatchWorkers goroutines,
+// so one failing receipt does not affect the others.
+//
+// Parameters:
+//   - ctx: The request context; once it is done, receipts not yet started fail with its error.
+//   - receipts: The receipts to score and store.
+//
+// Returns:
+//   - results: One result per receipt, in the same order as receipts.
+func (s *ReceiptServiceImpl) ProcessReceipts(ctx context.Context, receipts []domain.Receipt) []http.ProcessResult {
+	results := make([]http.ProcessResult, len(receipts))
+
+	workers := s.BatchWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(receipts) {
+		workers = len(receipts)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+				results[i].ReceiptID, results[i].Err = s.ProcessReceipt(ctx, receipts[i])
+			}
+		}()
+	}
+
+	for i := range receipts {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
 // GetPoints
 //
 // Parameters:
+//   - ctx: The request context; cancelling it abandons the store call.
 //   - id: The unique ID of the receipt whose points are being retrieved.
 //
 // Returns:
 //   - points: The points associated with the receipt.
-//   - err: An error if the receipt cannot be found or any other issue arises.
-func (s *ReceiptServiceImpl) GetPoints(id string) (int, error) {
-	receipt, err := s.ReceiptStore.Find(id)
+//   - err: A ReceiptPendingError if the receipt is still queued or being processed, an error if its
+//     processing failed, or an error if the receipt cannot be found or any other issue arises.
+func (s *ReceiptServiceImpl) GetPoints(ctx context.Context, id string) (int, error) {
+	if err := s.checkJob(ctx, id); err != nil {
+		return 0, err
+	}
+
+	receipt, err := s.ReceiptStore.Find(ctx, id)
 	if err != nil {
-		return 0, fmt.Errorf("failed to find receipt: %v", err)
+		return 0, fmt.Errorf("failed to find receipt: %w", err)
 	}
 
 	points := receipt.Points
 
 	return points, nil
 }
+
+// checkJob reports receipts that are still queued for asynchronous processing, or whose processing failed.
+// The queue is checked before the store because a job stops being tracked only after its receipt is saved,
+// or once it has been dead-lettered, in which case its dead letter holds the failure.
+func (s *ReceiptServiceImpl) checkJob(ctx context.Context, id string) error {
+	if s.Queue == nil {
+		return nil
+	}
+
+	job, err := s.Queue.Job(ctx, id)
+	switch {
+	case errors.Is(err, repository.ErrJobNotFound):
+		return s.checkDeadLetter(ctx, id)
+	case err != nil:
+		return fmt.Errorf("failed to find job: %w", err)
+	case job.Status == domain.JobStatusFailed:
+		return fmt.Errorf("receipt processing failed: %s", job.Error)
+	default:
+		return &http.ReceiptPendingError{Status: job.Status}
+	}
+}
+
+// checkDeadLetter reports receipts whose queued processing failed and was dead-lettered
+func (s *ReceiptServiceImpl) checkDeadLetter(ctx context.Context, id string) error {
+	if s.DeadLetters == nil {
+		return nil
+	}
+
+	letter, err := s.DeadLetters.Get(ctx, id)
+	switch {
+	case errors.Is(err, repository.ErrDeadLetterNotFound):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to find dead letter: %w", err)
+	default:
+		return fmt.Errorf("receipt processing failed: %s", letter.Reason)
+	}
+}
+
+// GetReceipt
+//
+// Parameters:
+//   - ctx: The request context; cancelling it abandons the store call.
+//   - id: The unique ID of the receipt to retrieve.
+//
+// Returns:
+//   - receipt: The receipt exactly as stored, including points and processing metadata.
+//   - err: An error if the receipt cannot be found or any other issue arises.
+func (s *ReceiptServiceImpl) GetReceipt(ctx context.Context, id string) (domain.Receipt, error) {
+	receipt, err := s.ReceiptStore.Find(ctx, id)
+	if err != nil {
+		return domain.Receipt{}, fmt.Errorf("failed to find receipt: %w", err)
+	}
+
+	return receipt, nil
+}
+
+// ListReceipts
+//
+// Parameters:
+//   - ctx: The request context; cancelling it abandons the store call.
+//   - query: Filters, sort order and pagination for the listing.
+//
+// Returns:
+//   - page: The matching receipts and the cursor for the next page.
+//   - err: An error if the query is invalid or the store fails.
+func (s *ReceiptServiceImpl) ListReceipts(ctx context.Context, query repository.ReceiptQuery) (repository.ReceiptPage, error) {
+	page, err := s.ReceiptStore.Query(ctx, query)
+	if err != nil {
+		return repository.ReceiptPage{}, fmt.Errorf("failed to list receipts: %w", err)
+	}
+
+	return page, nil
+}
+
+// UpdateReceipt
+//
+// The receipt replaces the stored one in full and is rescored; the stored version is kept as an earlier revision.
+//
+// Parameters:
+//   - ctx: The request context; cancelling it abandons the store call.
+//   - id: The unique ID of the receipt to correct.
+//   - receipt: The corrected receipt. Its ID and processing metadata are ignored.
+//   - expectedRevision: The revision the correction was based on.
+//
+// Returns:
+//   - updated: The new revision as stored, including its points and the change from the previous revision.
+//   - err: An error wrapping ErrRevisionConflict if the receipt is no longer at expectedRevision,
+//     or any error from finding, scoring or storing the receipt.
+func (s *ReceiptServiceImpl) UpdateReceipt(ctx context.Context, id string, receipt domain.Receipt, expectedRevision int) (domain.Receipt, error) {
+	current, err := s.ReceiptStore.Find(ctx, id)
+	if err != nil {
+		return domain.Receipt{}, fmt.Errorf("failed to find receipt: %w", err)
+	}
+	if current.Revision != expectedRevision {
+		return domain.Receipt{}, fmt.Errorf("unable to update receipt: %w", repository.ErrRevisionConflict)
+	}
+
+	points, err := s.PointsCalculator.CalculatePoints(ctx, receipt)
+	if err != nil {
+		return domain.Receipt{}, fmt.Errorf("unable to process receipt: %w", err)
+	}
+
+	receipt.ID = id
+	receipt.Fingerprint = receipt.CanonicalFingerprint()
+	receipt.DuplicateOf = current.DuplicateOf
+	receipt.Points = points
+	receipt.PointsDelta = points - current.Points
+	receipt.ReceivedAt = current.ReceivedAt
+	receipt.ScoringVersion = ScoringVersion
+	receipt.Revision = current.Revision + 1
+	receipt.RevisedAt = time.Now().UTC()
+
+	if err := s.ReceiptStore.Update(ctx, receipt, expectedRevision); err != nil {
+		return domain.Receipt{}, fmt.Errorf("failed to update receipt: %w", err)
+	}
+
+	s.publish(domain.EventReceiptCorrected, id, receipt.Retailer, points)
+	return receipt, nil
+}
+
+// ListRevisions
+//
+// Parameters:
+//   - ctx: The request context; cancelling it abandons the store call.
+//   - id: The unique ID of the receipt whose history is requested.
+//
+// Returns:
+//   - revisions: Every revision replaced by a correction, oldest first; empty if the receipt was never corrected.
+//   - err: An error if the receipt cannot be found or any other issue arises.
+func (s *ReceiptServiceImpl) ListRevisions(ctx context.Context, id string) ([]domain.Receipt, error) {
+	revisions, err := s.ReceiptStore.Revisions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// DeleteReceipt
+//
+// Parameters:
+//   - ctx: The request context; cancelling it abandons the store call.
+//   - id: The unique ID of the receipt to delete.
+//   - actor: Who requested the deletion, recorded in the tombstone.
+//   - reason: Why the receipt was deleted, recorded in the tombstone.
+//
+// Returns:
+//   - tombstone: The audit record left in place of the receipt.
+//   - err: An error if the receipt does not exist, was already deleted, or the store fails.
+func (s *ReceiptServiceImpl) DeleteReceipt(ctx context.Context, id string, actor string, reason string) (domain.Tombstone, error) {
+	tombstone := domain.Tombstone{
+		ReceiptID: id,
+		DeletedAt: time.Now().UTC(),
+		DeletedBy: actor,
+		Reason:    reason,
+	}
+
+	if err := s.ReceiptStore.Delete(ctx, id, tombstone); err != nil {
+		return domain.Tombstone{}, fmt.Errorf("failed to delete receipt: %w", err)
+	}
+
+	s.publish(domain.EventReceiptDeleted, id, "", 0)
+	return tombstone, nil
+}
+
+// EraseReceipts
+//
+// Parameters:
+//   - ctx: The request context; cancelling it abandons the store call.
+//   - selector: Which receipts to erase; exactly one of its fields must be set.
+//   - actor: Who requested the erasure, recorded in every tombstone.
+//   - reason: Why the receipts were erased, recorded in every tombstone.
+//
+// Returns:
+//   - erasedIDs: The IDs of the erased receipts, sorted; empty if nothing matched.
+//   - err: An error if the selector is invalid or the store fails.
+func (s *ReceiptServiceImpl) EraseReceipts(ctx context.Context, selector repository.ErasureSelector, actor string, reason string) ([]string, error) {
+	if (selector.CustomerID == "") == (selector.Retailer == "") {
+		return nil, fmt.Errorf("erasure requires exactly one of customerId or retailer")
+	}
+
+	audit := domain.Tombstone{
+		DeletedAt: time.Now().UTC(),
+		DeletedBy: actor,
+		Reason:    reason,
+	}
+
+	erased, err := s.ReceiptStore.Erase(ctx, selector, audit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to erase receipts: %w", err)
+	}
+
+	for _, id := range erased {
+		s.publish(domain.EventReceiptDeleted, id, selector.Retailer, 0)
+	}
+	return erased, nil
+}
+
+// publish hands a ReceiptEvent to every configured publisher; publishers never block
+func (s *ReceiptServiceImpl) publish(eventType string, receiptID string, retailer string, points int) {
+	if len(s.Publishers) == 0 {
+		return
+	}
+
+	event := domain.ReceiptEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		ReceiptID:  receiptID,
+		Retailer:   retailer,
+		Points:     points,
+		OccurredAt: time.Now().UTC(),
+	}
+	for _, publisher := range s.Publishers {
+		publisher.Publish(event)
+	}
+}
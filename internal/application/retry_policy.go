@@ -0,0 +1,31 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/pkg/utils"
+	"time"
+)
+
+// RetryPolicy bounds how often, and how patiently, a queued receipt is retried after a transient failure.
+type RetryPolicy struct {
+	MaxAttempts    int           // Total attempts, including the first; values below one mean a single attempt
+	InitialBackoff time.Duration // Wait before the second attempt; each later wait doubles
+	MaxBackoff     time.Duration // Upper bound on any single wait; zero leaves it unbounded
+}
+
+// DefaultRetryPolicy is used by NewReceiptService unless WithRetryPolicy is given.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 10 * time.Second}
+
+// Backoff returns how long to wait after the given failed attempt (1 for the first) before trying again.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	return utils.ExponentialBackoff(p.InitialBackoff, p.MaxBackoff, attempt)
+}
+
+// isTransient reports whether err is worth retrying: a failure the store marked as transient,
+// unless it was caused by the job's context being cancelled or running out of time
+func isTransient(err error) bool {
+	var transient *repository.TransientError
+	return errors.As(err, &transient) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
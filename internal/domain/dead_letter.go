@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// DeadLetter is a queued receipt that could not be processed, kept with the reason so it can be
+// inspected and requeued once the cause has been fixed.
+type DeadLetter struct {
+	ID         string    `json:"id"` // ID of the job, and of the receipt once it is stored
+	Receipt    Receipt   `json:"receipt"`
+	Reason     string    `json:"reason"`   // The error from the last attempt
+	Attempts   int       `json:"attempts"` // How many times processing was tried
+	EnqueuedAt time.Time `json:"enqueuedAt"`
+	FailedAt   time.Time `json:"failedAt"`
+}
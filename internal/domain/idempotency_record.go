@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord remembers the first request made with an idempotency key so that retries
+// of the same request can be answered with the original response instead of being processed again.
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string // Fingerprint of the request the key was first used with
+	Completed   bool   // False while the first request is still being processed
+	StatusCode  int    // Response status of the first request, once completed
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time // After this the key may be reused for a new request
+}
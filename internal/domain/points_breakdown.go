@@ -0,0 +1,18 @@
+package domain
+
+// Names of the points rules, as reported in a points breakdown.
+const (
+	RuleRetailerName          = "retailerName"          // One point per alphanumeric character in the retailer name
+	RuleRoundDollarTotal      = "roundDollarTotal"      // 50 points if the total has no cents
+	RuleMultipleOfQuarter     = "multipleOfQuarter"     // 25 points if the total is a multiple of 0.25
+	RuleItemCount             = "itemCount"             // 5 points for every two items
+	RuleItemDescriptions      = "itemDescriptions"      // Price-based points for descriptions whose trimmed length is a multiple of 3
+	RuleOddDay                = "oddDay"                // 6 points if the purchase day is odd
+	RuleAfternoonPurchaseTime = "afternoonPurchaseTime" // 10 points for purchases after 2:00pm and before 4:00pm
+)
+
+// RulePoints is the number of points one rule awarded to a receipt.
+type RulePoints struct {
+	Rule   string `json:"rule"`
+	Points int    `json:"points"`
+}
@@ -1,16 +1,34 @@
 package domain
 
+import "time"
+
 type Item struct {
-	ShortDescription string `json:"shortDescription" binding:"required"`
-	Price            string `json:"price" binding:"required"`
+	ShortDescription string `json:"shortDescription"`
+	Price            string `json:"price"`
 }
 
+// Receipt has no validation tags: every transport checks submissions against the Receipt schema of api/openapi.yaml
+// with api.ValidateReceipt.
 type Receipt struct {
 	ID           string `json:"id"`
-	Retailer     string `json:"retailer" binding:"required"`
-	PurchaseDate string `json:"purchaseDate" binding:"required"`
-	PurchaseTime string `json:"purchaseTime" binding:"required"`
-	Items        []Item `json:"items" binding:"required,dive,required"` // Ensure `items` is not empty and each item is validated
-	Total        string `json:"total" binding:"required"`
+	Retailer     string `json:"retailer"`
+	PurchaseDate string `json:"purchaseDate"`
+	PurchaseTime string `json:"purchaseTime"`
+	Items        []Item `json:"items"`
+	Total        string `json:"total"`
 	Points       int    `json:"points"`
+	CustomerID   string `json:"customerId,omitempty"` // Optional loyalty/customer identifier, used for data erasure requests
+
+	// Processing metadata, set by the service when the receipt is scored
+	ReceivedAt     time.Time `json:"receivedAt"`
+	ScoringVersion string    `json:"scoringVersion"`
+
+	// Revision metadata, set by the service when the receipt is created or corrected
+	Revision    int       `json:"revision"`    // Starts at 1 and increases with every correction
+	PointsDelta int       `json:"pointsDelta"` // Change in points relative to the previous revision
+	RevisedAt   time.Time `json:"revisedAt"`   // When this revision replaced the previous one; zero for the first
+
+	// Duplicate detection metadata, set by the service from CanonicalFingerprint
+	Fingerprint string `json:"fingerprint"`
+	DuplicateOf string `json:"duplicateOf,omitempty"` // ID of the earlier receipt this one duplicates, when duplicates are flagged
 }
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Types of ReceiptEvent.
+const (
+	EventReceiptScored    = "receipt.scored"    // A new receipt was scored and stored
+	EventReceiptCorrected = "receipt.corrected" // A stored receipt was replaced by a corrected revision
+	EventReceiptDeleted   = "receipt.deleted"   // A stored receipt was deleted or erased
+)
+
+// EventTypes lists every ReceiptEvent type, in the order they are documented.
+var EventTypes = []string{EventReceiptScored, EventReceiptCorrected, EventReceiptDeleted}
+
+// ReceiptEvent records something that happened to a receipt, for delivery to subscribers.
+type ReceiptEvent struct {
+	ID         string    `json:"id"` // Unique per event, so receivers can discard redelivered events
+	Type       string    `json:"type"`
+	ReceiptID  string    `json:"receiptId"`
+	Retailer   string    `json:"retailer,omitempty"` // Empty for deletions, unless erased by retailer
+	Points     int       `json:"points"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fingerprintVersion is mixed into every fingerprint so the canonical form can change without
+// new fingerprints colliding with ones computed under the old rules.
+const fingerprintVersion = "fp1"
+
+// CanonicalFingerprint identifies the physical receipt a submission describes.
+//
+// Two submissions get the same fingerprint when they differ only in retailer and item description
+// casing or whitespace, in how amounts are written ("6.5" and "6.50"), or in the order of their items.
+// Processing metadata and the customer ID are not part of the fingerprint.
+func (r Receipt) CanonicalFingerprint() string {
+	items := make([]string, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = canonicalText(item.ShortDescription) + "|" + canonicalAmount(item.Price)
+	}
+	sort.Strings(items)
+
+	canonical := strings.Join([]string{
+		fingerprintVersion,
+		canonicalText(r.Retailer),
+		strings.TrimSpace(r.PurchaseDate),
+		strings.TrimSpace(r.PurchaseTime),
+		canonicalAmount(r.Total),
+		strings.Join(items, "\n"),
+	}, "\n")
+
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalText lower-cases text and collapses every run of whitespace into a single space
+func canonicalText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// canonicalAmount writes amounts with exactly two decimals, leaving unparseable values as they are
+func canonicalAmount(amount string) string {
+	amount = strings.TrimSpace(amount)
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return amount
+	}
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
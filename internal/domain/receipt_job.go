@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// JobStatus is the progress of a receipt submitted for asynchronous processing.
+type JobStatus string
+
+// Supported values for JobStatus.
+const (
+	JobStatusPending    JobStatus = "pending"    // Waiting in the queue
+	JobStatusProcessing JobStatus = "processing" // Taken by a worker and being scored and stored
+	JobStatusFailed     JobStatus = "failed"     // Could not be processed; Error says why
+)
+
+// ReceiptJob is a receipt waiting to be scored and stored by a background worker.
+// Its ID becomes the ID of the stored receipt.
+type ReceiptJob struct {
+	ID         string    `json:"id"`
+	Receipt    Receipt   `json:"receipt"`
+	Status     JobStatus `json:"status"`
+	Error      string    `json:"error,omitempty"` // Why processing failed, once Status is failed
+	EnqueuedAt time.Time `json:"enqueuedAt"`
+	UpdatedAt  time.Time `json:"updatedAt"` // When Status last changed
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Tombstone is left in place of a deleted receipt. It doubles as the audit record of the
+// deletion: which receipt was removed, when, by whom and why.
+type Tombstone struct {
+	ReceiptID string    `json:"receiptId"`
+	DeletedAt time.Time `json:"deletedAt"`
+	DeletedBy string    `json:"deletedBy"`
+	Reason    string    `json:"reason"`
+}
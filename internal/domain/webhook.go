@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// WebhookSubscription asks for ReceiptEvents of the listed types to be POSTed to URL,
+// signed with Secret.
+type WebhookSubscription struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"-"` // Key of the HMAC-SHA256 signature sent with every delivery; never returned
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Wants reports whether the subscription asked for events of the given type.
+func (s WebhookSubscription) Wants(eventType string) bool {
+	for _, event := range s.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery records one attempt at delivering an event to a subscription.
+type WebhookDelivery struct {
+	SubscriptionID string    `json:"subscriptionId"`
+	EventID        string    `json:"eventId"`
+	EventType      string    `json:"eventType"`
+	Attempt        int       `json:"attempt"` // 1 for the first attempt at this event
+	StatusCode     int       `json:"statusCode,omitempty"`
+	Error          string    `json:"error,omitempty"` // Why the attempt failed, if it did
+	Succeeded      bool      `json:"succeeded"`       // The receiver answered with a 2xx status
+	AttemptedAt    time.Time `json:"attemptedAt"`
+	DurationMs     int64     `json:"durationMs"` // How long the receiver took to answer
+}
@@ -1,10 +1,14 @@
 package http
 
 import (
+	"context"
 	"go-receipt-processor/internal/domain"
 )
 
 // PointsCalculator defines the methods required for calculating points based on a receipt.
 type PointsCalculator interface {
-	CalculatePoints(receipt domain.Receipt) (int, error)
+	CalculatePoints(ctx context.Context, receipt domain.Receipt) (int, error)
+
+	// Breakdown reports the points each rule awards to a receipt; they add up to CalculatePoints.
+	Breakdown(ctx context.Context, receipt domain.Receipt) (breakdown []domain.RulePoints, err error)
 }
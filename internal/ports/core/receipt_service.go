@@ -1,9 +1,74 @@
 package http
 
-import "go-receipt-processor/internal/domain"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+)
 
 // ReceiptService defines the interface for processing receipts and managing points.
 type ReceiptService interface {
-	ProcessReceipt(receipt domain.Receipt) (receiptID string, err error)
-	GetPoints(id string) (points int, err error)
+	ProcessReceipt(ctx context.Context, receipt domain.Receipt) (receiptID string, err error)
+	ProcessReceipts(ctx context.Context, receipts []domain.Receipt) (results []ProcessResult)
+	GetPoints(ctx context.Context, id string) (points int, err error)
+	GetReceipt(ctx context.Context, id string) (receipt domain.Receipt, err error)
+	ListReceipts(ctx context.Context, query repository.ReceiptQuery) (page repository.ReceiptPage, err error)
+	UpdateReceipt(ctx context.Context, id string, receipt domain.Receipt, expectedRevision int) (updated domain.Receipt, err error)
+	ListRevisions(ctx context.Context, id string) (revisions []domain.Receipt, err error)
+	DeleteReceipt(ctx context.Context, id string, actor string, reason string) (tombstone domain.Tombstone, err error)
+	EraseReceipts(ctx context.Context, selector repository.ErasureSelector, actor string, reason string) (erasedIDs []string, err error)
+
+	// EnqueueReceipt queues a receipt for asynchronous processing by ProcessNextJob.
+	EnqueueReceipt(ctx context.Context, receipt domain.Receipt) (job domain.ReceiptJob, err error)
+	// ProcessNextJob waits for a queued receipt, then scores and stores it.
+	ProcessNextJob(ctx context.Context) (err error)
+
+	// ListDeadLetters, GetDeadLetter and RequeueDeadLetter manage queued receipts that could not be processed.
+	ListDeadLetters(ctx context.Context) (letters []domain.DeadLetter, err error)
+	GetDeadLetter(ctx context.Context, id string) (letter domain.DeadLetter, err error)
+	RequeueDeadLetter(ctx context.Context, id string) (job domain.ReceiptJob, err error)
+}
+
+// ProcessResult is the outcome of processing one receipt of a batch.
+type ProcessResult struct {
+	ReceiptID string // ID of the stored receipt; empty if processing failed
+	Err       error  // Why the receipt could not be processed; nil on success
+}
+
+// ErrDuplicateReceipt is matched by the error returned when a receipt is rejected as a resubmission of a stored one.
+var ErrDuplicateReceipt = errors.New("duplicate receipt")
+
+// DuplicateReceiptError identifies the stored receipt a rejected submission duplicates.
+// It matches ErrDuplicateReceipt with errors.Is.
+type DuplicateReceiptError struct {
+	OriginalID string // ID of the earliest stored receipt with the same fingerprint
+}
+
+func (e *DuplicateReceiptError) Error() string {
+	return fmt.Sprintf("duplicate of receipt %s", e.OriginalID)
+}
+
+// Is reports whether target is ErrDuplicateReceipt.
+func (e *DuplicateReceiptError) Is(target error) bool {
+	return target == ErrDuplicateReceipt
+}
+
+// ErrReceiptPending is matched by the error returned when a queued receipt has not been processed yet.
+var ErrReceiptPending = errors.New("receipt is still being processed")
+
+// ReceiptPendingError reports the progress of a receipt that has been queued but not yet stored.
+// It matches ErrReceiptPending with errors.Is.
+type ReceiptPendingError struct {
+	Status domain.JobStatus // Pending or processing
+}
+
+func (e *ReceiptPendingError) Error() string {
+	return fmt.Sprintf("receipt is %s", e.Status)
+}
+
+// Is reports whether target is ErrReceiptPending.
+func (e *ReceiptPendingError) Is(target error) bool {
+	return target == ErrReceiptPending
 }
@@ -0,0 +1,7 @@
+package response
+
+// BackupResponse represents the response data for an online store backup.
+type BackupResponse struct {
+	Path  string `json:"path"`  // Location of the written backup file
+	Bytes int64  `json:"bytes"` // Size of the backup in bytes
+}
@@ -0,0 +1,21 @@
+package response
+
+// BatchProcessResponse represents the per-receipt results of a batch processing request.
+type BatchProcessResponse struct {
+	Results   []BatchItemResult `json:"results"`   // One entry per submitted receipt, in submission order
+	Succeeded int               `json:"succeeded"` // Receipts that were stored
+	Failed    int               `json:"failed"`    // Receipts that were rejected or could not be processed
+}
+
+// BatchItemResult is the outcome of a single receipt in a batch; exactly one of ID and Error is set.
+type BatchItemResult struct {
+	Index int         `json:"index"`
+	ID    string      `json:"id,omitempty"`
+	Error *BatchError `json:"error,omitempty"`
+}
+
+// BatchError describes why a receipt in a batch was not stored.
+type BatchError struct {
+	Status  int    `json:"status"` // The HTTP status the receipt would have received from POST /receipt/process
+	Message string `json:"message"`
+}
@@ -0,0 +1,29 @@
+package response
+
+import "time"
+
+// DeadLetterResponse represents a queued receipt that could not be processed.
+type DeadLetterResponse struct {
+	ID         string           `json:"id"` // The receipt keeps this ID when requeued
+	Receipt    SubmittedReceipt `json:"receipt"`
+	Reason     string           `json:"reason"` // The error from the last attempt
+	Attempts   int              `json:"attempts"`
+	EnqueuedAt time.Time        `json:"enqueuedAt"`
+	FailedAt   time.Time        `json:"failedAt"`
+}
+
+// DeadLettersResponse represents every dead-lettered receipt.
+type DeadLettersResponse struct {
+	DeadLetters []DeadLetterResponse `json:"deadLetters"` // Oldest failure first
+	Count       int                  `json:"count"`
+}
+
+// SubmittedReceipt is a receipt exactly as it was submitted, before it was scored.
+type SubmittedReceipt struct {
+	Retailer     string        `json:"retailer"`
+	PurchaseDate string        `json:"purchaseDate"`
+	PurchaseTime string        `json:"purchaseTime"`
+	Items        []ReceiptItem `json:"items"`
+	Total        string        `json:"total"`
+	CustomerID   string        `json:"customerId,omitempty"`
+}
@@ -0,0 +1,17 @@
+package response
+
+import "time"
+
+// DeleteReceiptResponse represents the tombstone left behind after deleting a receipt.
+type DeleteReceiptResponse struct {
+	ID        string    `json:"id"`
+	DeletedAt time.Time `json:"deletedAt"`
+	DeletedBy string    `json:"deletedBy"` // Actor taken from the X-Actor header
+	Reason    string    `json:"reason"`
+}
+
+// EraseReceiptsResponse represents the result of a bulk erasure request.
+type EraseReceiptsResponse struct {
+	ErasedIDs []string `json:"erasedIds"` // IDs of every erased receipt, sorted
+	Count     int      `json:"count"`
+}
@@ -0,0 +1,21 @@
+package response
+
+// ErrorResponse is the envelope every v2 endpoint wraps its errors in.
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes why a v2 request failed.
+type ErrorBody struct {
+	Code       string       `json:"code"`                 // Stable, machine readable reason, e.g. "not_found"
+	Message    string       `json:"message"`              // Human readable description
+	RequestID  string       `json:"requestId,omitempty"`  // X-Request-ID of the failed request
+	Fields     []FieldError `json:"fields,omitempty"`     // Every rejected value, when the request failed validation
+	OriginalID string       `json:"originalId,omitempty"` // ID of the stored receipt a rejected duplicate matches
+}
+
+// FieldError describes one rejected value of a request.
+type FieldError struct {
+	Field   string `json:"field"` // Path to the value, e.g. "items[0].price"
+	Message string `json:"message"`
+}
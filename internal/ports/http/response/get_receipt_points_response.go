@@ -1,6 +1,9 @@
 package response
 
-// ReceiptProcessResponse represents the response data for processing a receipt.
+import "encoding/xml"
+
+// GetReceiptPointsResponse represents the points awarded to a receipt, e.g. {"points": 100} or <receipt><points>100</points></receipt>.
 type GetReceiptPointsResponse struct {
-	Points int `json:"points"` // JSON binding Points to lowercase points
+	XMLName xml.Name `json:"-" xml:"receipt"`
+	Points  int      `json:"points" xml:"points"` // JSON binding Points to lowercase points
 }
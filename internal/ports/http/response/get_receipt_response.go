@@ -0,0 +1,33 @@
+package response
+
+import "time"
+
+// GetReceiptResponse represents the response data for retrieving a stored receipt.
+type GetReceiptResponse struct {
+	ID             string        `json:"id"`
+	Retailer       string        `json:"retailer"`
+	PurchaseDate   string        `json:"purchaseDate"`
+	PurchaseTime   string        `json:"purchaseTime"`
+	Items          []ReceiptItem `json:"items"`
+	Total          string        `json:"total"`
+	Points         int           `json:"points"`
+	CustomerID     string        `json:"customerId,omitempty"`     // Customer the receipt was submitted for, if any
+	ReceivedAt     *time.Time    `json:"receivedAt,omitempty"`     // When the receipt was processed; omitted if unknown
+	ScoringVersion string        `json:"scoringVersion,omitempty"` // Version of the points rules that scored the receipt
+	Revision       int           `json:"revision"`                 // Increases with every correction; also sent as the ETag
+	PointsDelta    int           `json:"pointsDelta"`              // Change in points from the previous revision
+	RevisedAt      *time.Time    `json:"revisedAt,omitempty"`      // When this revision was stored; omitted for the first
+	Fingerprint    string        `json:"fingerprint,omitempty"`    // Canonical fingerprint used for duplicate detection
+	DuplicateOf    string        `json:"duplicateOf,omitempty"`    // ID of the earlier receipt this one duplicates, if flagged
+}
+
+// ReceiptRevisionsResponse represents the earlier revisions of a corrected receipt.
+type ReceiptRevisionsResponse struct {
+	Revisions []GetReceiptResponse `json:"revisions"` // Oldest first
+}
+
+// ReceiptItem represents a single line item of a stored receipt.
+type ReceiptItem struct {
+	ShortDescription string `json:"shortDescription"`
+	Price            string `json:"price"`
+}
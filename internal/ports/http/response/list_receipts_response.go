@@ -0,0 +1,18 @@
+package response
+
+// ListReceiptsResponse represents the response data for a page of listed receipts.
+type ListReceiptsResponse struct {
+	Receipts   []ReceiptSummary `json:"receipts"`
+	NextCursor string           `json:"nextCursor,omitempty"` // Pass as ?cursor= to fetch the next page; omitted on the last page
+}
+
+// ReceiptSummary represents a single receipt in a listing.
+type ReceiptSummary struct {
+	ID           string `json:"id"`
+	Retailer     string `json:"retailer"`
+	PurchaseDate string `json:"purchaseDate"`
+	PurchaseTime string `json:"purchaseTime"`
+	Total        string `json:"total"`
+	Points       int    `json:"points"`
+	ItemCount    int    `json:"itemCount"`
+}
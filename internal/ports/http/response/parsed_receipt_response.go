@@ -0,0 +1,22 @@
+package response
+
+// ParsedReceiptResponse represents a receipt extracted from a document that was not submitted as structured data,
+// such as the text of a printed receipt or an e-receipt email, and the ID it was stored under.
+type ParsedReceiptResponse struct {
+	ID         string             `json:"id,omitempty"` // Omitted when the extracted receipt could not be stored
+	Receipt    ParsedReceipt      `json:"receipt"`
+	Template   string             `json:"template,omitempty"`   // Which email template the receipt was extracted with
+	Confidence map[string]float64 `json:"confidence,omitempty"` // How sure the parser is of each field, from 0 (not found) to 1
+	Error      string             `json:"error,omitempty"`      // Why the receipt could not be stored
+	Fields     []FieldError       `json:"fields,omitempty"`     // Extracted values that do not match the Receipt schema
+	OriginalID string             `json:"originalId,omitempty"` // ID of the stored receipt a rejected duplicate matches
+}
+
+// ParsedReceipt represents the fields extracted from a document.
+type ParsedReceipt struct {
+	Retailer     string        `json:"retailer"`
+	PurchaseDate string        `json:"purchaseDate"`
+	PurchaseTime string        `json:"purchaseTime"`
+	Items        []ReceiptItem `json:"items"`
+	Total        string        `json:"total"`
+}
@@ -0,0 +1,14 @@
+package response
+
+// ReadinessResponse represents the response data for the readiness check.
+type ReadinessResponse struct {
+	Status string                     `json:"status"`           // "ready" or "unavailable"
+	Checks map[string]DependencyCheck `json:"checks,omitempty"` // Result per checked dependency
+}
+
+// DependencyCheck represents the health of a single dependency in a readiness check.
+type DependencyCheck struct {
+	Healthy bool             `json:"healthy"`
+	Error   string           `json:"error,omitempty"`
+	Stats   map[string]int64 `json:"stats,omitempty"` // Dependency specific figures, e.g. pool connections
+}
@@ -0,0 +1,11 @@
+package response
+
+import "time"
+
+// ReceiptScoredEvent is the data of a server-sent event announcing a newly scored receipt.
+type ReceiptScoredEvent struct {
+	ReceiptID string    `json:"receiptId"`
+	Retailer  string    `json:"retailer"`
+	Points    int       `json:"points"`
+	Timestamp time.Time `json:"timestamp"` // When the receipt was stored
+}
@@ -0,0 +1,10 @@
+package response
+
+import "encoding/xml"
+
+// ReceiptStatusResponse represents a receipt accepted for asynchronous processing but not yet stored.
+type ReceiptStatusResponse struct {
+	XMLName xml.Name `json:"-" xml:"receipt"`
+	ID      string   `json:"id" xml:"id"`
+	Status  string   `json:"status" xml:"status"` // "pending" while queued, "processing" once a worker has taken it
+}
@@ -0,0 +1,27 @@
+package response
+
+import "time"
+
+// ReceiptV2Response represents a scored receipt in the v2 API: its points, how each rule contributed to them
+// and how the receipt was processed.
+type ReceiptV2Response struct {
+	ID        string          `json:"id"`
+	Points    int             `json:"points"`
+	Breakdown []RulePoints    `json:"breakdown"` // Points awarded by each rule; they add up to Points
+	Metadata  ReceiptMetadata `json:"metadata"`
+}
+
+// RulePoints represents the points one rule awarded to a receipt.
+type RulePoints struct {
+	Rule   string `json:"rule"`
+	Points int    `json:"points"`
+}
+
+// ReceiptMetadata represents how and when a receipt was processed.
+type ReceiptMetadata struct {
+	ReceivedAt     *time.Time `json:"receivedAt,omitempty"`     // When the receipt was processed; omitted if unknown
+	ScoringVersion string     `json:"scoringVersion,omitempty"` // Version of the points rules that scored the receipt
+	Revision       int        `json:"revision"`                 // Increases with every correction
+	Fingerprint    string     `json:"fingerprint,omitempty"`    // Canonical fingerprint used for duplicate detection
+	DuplicateOf    string     `json:"duplicateOf,omitempty"`    // ID of the earlier receipt this one duplicates, if flagged
+}
@@ -0,0 +1,9 @@
+package response
+
+// StreamLineResult is written as one NDJSON line for every non-empty line of a streamed upload;
+// exactly one of ID and Error is set.
+type StreamLineResult struct {
+	Line  int         `json:"line"` // 1-based line number in the upload
+	ID    string      `json:"id,omitempty"`
+	Error *BatchError `json:"error,omitempty"`
+}
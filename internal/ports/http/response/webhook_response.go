@@ -0,0 +1,26 @@
+package response
+
+import (
+	"go-receipt-processor/internal/domain"
+	"time"
+)
+
+// WebhookSubscriptionResponse represents a webhook subscription. Its secret is never returned.
+type WebhookSubscriptionResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// WebhookSubscriptionsResponse represents every webhook subscription.
+type WebhookSubscriptionsResponse struct {
+	Subscriptions []WebhookSubscriptionResponse `json:"subscriptions"` // Oldest first
+	Count         int                           `json:"count"`
+}
+
+// WebhookDeliveriesResponse represents the delivery log of one webhook subscription.
+type WebhookDeliveriesResponse struct {
+	Deliveries []domain.WebhookDelivery `json:"deliveries"` // Most recent attempt first
+	Count      int                      `json:"count"`
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-receipt-processor/internal/domain"
+)
+
+// ErrDeadLetterNotFound is returned by dead-letter stores when no dead letter exists for the requested ID.
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetterStore keeps queued receipts whose processing failed permanently.
+type DeadLetterStore interface {
+	// Add stores a dead letter, replacing any earlier one with the same ID.
+	Add(ctx context.Context, letter domain.DeadLetter) error
+
+	// List returns every dead letter, oldest failure first.
+	List(ctx context.Context) (letters []domain.DeadLetter, err error)
+
+	// Get returns a dead letter by ID, or ErrDeadLetterNotFound.
+	Get(ctx context.Context, id string) (letter domain.DeadLetter, err error)
+
+	// Remove deletes a dead letter, returning ErrDeadLetterNotFound if there is none with the ID.
+	Remove(ctx context.Context, id string) error
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "go-receipt-processor/internal/domain"
+
+// EventPublisher is notified of every ReceiptEvent. Publish must not block: the service calls it
+// on the request path, so implementations hand events off to their own workers.
+type EventPublisher interface {
+	Publish(event domain.ReceiptEvent)
+}
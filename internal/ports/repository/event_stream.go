@@ -0,0 +1,14 @@
+package repository
+
+import "go-receipt-processor/internal/domain"
+
+// EventStream is an EventPublisher that fans published events out to live subscribers and keeps
+// the most recent ones so that reconnecting subscribers can catch up.
+type EventStream interface {
+	EventPublisher
+
+	// Subscribe registers a subscriber. replay holds the buffered events published after lastEventID, oldest first:
+	// none if lastEventID is empty, and every buffered event if lastEventID is no longer buffered. Later events
+	// arrive on events, which is closed when cancel is called or the subscriber falls too far behind to keep up.
+	Subscribe(lastEventID string) (replay []domain.ReceiptEvent, events <-chan domain.ReceiptEvent, cancel func())
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"go-receipt-processor/internal/domain"
+)
+
+// IdempotencyStore keeps the idempotency records used to deduplicate retried requests.
+type IdempotencyStore interface {
+	// Reserve claims record.Key for a new request. If an unexpired record already holds the key,
+	// nothing is written and that record is returned with reserved set to false.
+	Reserve(ctx context.Context, record domain.IdempotencyRecord) (existing domain.IdempotencyRecord, reserved bool, err error)
+
+	// Complete stores the response of a reserved request so that later retries can replay it.
+	Complete(ctx context.Context, record domain.IdempotencyRecord) error
+
+	// Release drops a reservation whose request did not complete, so the key can be retried.
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"go-receipt-processor/internal/domain"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor is malformed or was issued for a different sort.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Fields receipts can be sorted by in a ReceiptQuery.
+const (
+	SortByPurchaseDate = "purchaseDate" // Purchase date, then purchase time
+	SortByRetailer     = "retailer"     // Retailer name, ignoring case and surrounding whitespace
+	SortByPoints       = "points"
+	SortByTotal        = "total"
+)
+
+// ReceiptQuery describes which receipts to list and in what order.
+//
+// Zero values mean "no filter"; ranges are inclusive. Results are always ordered by SortBy and then by ID,
+// so that pages are stable even when several receipts share a sort value.
+type ReceiptQuery struct {
+	Retailer         string   // Exact retailer match, ignoring case and surrounding whitespace
+	PurchaseDateFrom string   // Earliest purchase date ("YYYY-MM-DD")
+	PurchaseDateTo   string   // Latest purchase date ("YYYY-MM-DD")
+	MinPoints        *int     // Lowest points value
+	MaxPoints        *int     // Highest points value
+	MinTotal         *float64 // Lowest receipt total
+	MaxTotal         *float64 // Highest receipt total
+
+	SortBy         string // One of the SortBy constants; defaults to SortByPurchaseDate
+	SortDescending bool
+	Limit          int    // Maximum receipts per page; zero or negative returns every match
+	Cursor         string // NextCursor from the previous page, or "" for the first page
+}
+
+// ReceiptPage is a single page of receipts returned by a query.
+type ReceiptPage struct {
+	Receipts   []domain.Receipt
+	NextCursor string // Cursor for the following page, or "" if this is the last page
+}
+
+// cursor is the decoded form of a pagination cursor: the sort position of the last receipt on a page.
+type cursor struct {
+	SortBy string `json:"s"`
+	Value  string `json:"v"`
+	ID     string `json:"id"`
+}
+
+// SortField returns the effective sort field, applying the default.
+func (q ReceiptQuery) SortField() string {
+	if q.SortBy == "" {
+		return SortByPurchaseDate
+	}
+	return q.SortBy
+}
+
+// Matches reports whether a receipt satisfies every filter in the query.
+func (q ReceiptQuery) Matches(receipt domain.Receipt) bool {
+	if q.Retailer != "" && NormalizeRetailer(receipt.Retailer) != NormalizeRetailer(q.Retailer) {
+		return false
+	}
+	if q.PurchaseDateFrom != "" && receipt.PurchaseDate < q.PurchaseDateFrom {
+		return false
+	}
+	if q.PurchaseDateTo != "" && receipt.PurchaseDate > q.PurchaseDateTo {
+		return false
+	}
+	if q.MinPoints != nil && receipt.Points < *q.MinPoints {
+		return false
+	}
+	if q.MaxPoints != nil && receipt.Points > *q.MaxPoints {
+		return false
+	}
+	if q.MinTotal != nil || q.MaxTotal != nil {
+		total, err := strconv.ParseFloat(receipt.Total, 64)
+		if err != nil {
+			return false
+		}
+		if q.MinTotal != nil && total < *q.MinTotal {
+			return false
+		}
+		if q.MaxTotal != nil && total > *q.MaxTotal {
+			return false
+		}
+	}
+	return true
+}
+
+// SortValue returns the value a receipt is ordered by for the query's sort field.
+func (q ReceiptQuery) SortValue(receipt domain.Receipt) string {
+	switch q.SortField() {
+	case SortByRetailer:
+		return NormalizeRetailer(receipt.Retailer)
+	case SortByPoints:
+		return strconv.Itoa(receipt.Points)
+	case SortByTotal:
+		return receipt.Total
+	default:
+		return receipt.PurchaseDate + " " + receipt.PurchaseTime
+	}
+}
+
+// Paginate filters, sorts and pages an unordered set of candidate receipts according to the query.
+//
+// Adapters that cannot express the whole query natively narrow the candidates using their indexes
+// and hand the rest to Paginate, so every store pages identically.
+//
+// Parameters:
+//   - candidates: Receipts that may match the query; non-matching receipts are dropped.
+//
+// Returns:
+//   - The requested page of receipts and the cursor for the next one.
+//   - err: ErrInvalidCursor if the query's cursor cannot be used.
+func (q ReceiptQuery) Paginate(candidates []domain.Receipt) (ReceiptPage, error) {
+	after, err := q.decodeCursor()
+	if err != nil {
+		return ReceiptPage{}, err
+	}
+
+	matches := make([]domain.Receipt, 0, len(candidates))
+	for _, receipt := range candidates {
+		if !q.Matches(receipt) {
+			continue
+		}
+		if after != nil && q.compare(q.SortValue(receipt), receipt.ID, after.Value, after.ID) <= 0 {
+			continue
+		}
+		matches = append(matches, receipt)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return q.compare(q.SortValue(matches[i]), matches[i].ID, q.SortValue(matches[j]), matches[j].ID) < 0
+	})
+
+	page := ReceiptPage{Receipts: matches}
+	if q.Limit > 0 && len(matches) > q.Limit {
+		page.Receipts = matches[:q.Limit]
+		page.NextCursor = q.CursorAfter(page.Receipts[q.Limit-1])
+	}
+	return page, nil
+}
+
+// CursorAfter returns the cursor that resumes a listing immediately after the given receipt.
+func (q ReceiptQuery) CursorAfter(receipt domain.Receipt) string {
+	body, _ := json.Marshal(cursor{SortBy: q.SortField(), Value: q.SortValue(receipt), ID: receipt.ID})
+	return base64.RawURLEncoding.EncodeToString(body)
+}
+
+// CursorPosition decodes the query's cursor into the sort value and ID of the last receipt already returned.
+//
+// Returns:
+//   - value, id: The position to resume after; ok is false when the query has no cursor.
+//   - err: ErrInvalidCursor if the cursor is malformed or was issued for a different sort field.
+func (q ReceiptQuery) CursorPosition() (value string, id string, ok bool, err error) {
+	c, err := q.decodeCursor()
+	if err != nil || c == nil {
+		return "", "", false, err
+	}
+	return c.Value, c.ID, true, nil
+}
+
+func (q ReceiptQuery) decodeCursor() (*cursor, error) {
+	if q.Cursor == "" {
+		return nil, nil
+	}
+	body, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(body, &c); err != nil || c.SortBy != q.SortField() {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// compare orders two (sort value, ID) positions in the query's direction, returning -1, 0 or 1.
+func (q ReceiptQuery) compare(valueA, idA, valueB, idB string) int {
+	result := compareSortValues(q.SortField(), valueA, valueB)
+	if result == 0 {
+		result = strings.Compare(idA, idB)
+	}
+	if q.SortDescending {
+		return -result
+	}
+	return result
+}
+
+// compareSortValues compares numeric sort fields numerically and everything else lexically.
+func compareSortValues(field, a, b string) int {
+	if field == SortByPoints || field == SortByTotal {
+		numA, errA := strconv.ParseFloat(a, 64)
+		numB, errB := strconv.ParseFloat(b, 64)
+		if errA == nil && errB == nil {
+			switch {
+			case numA < numB:
+				return -1
+			case numA > numB:
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// NormalizeRetailer returns the form of a retailer name used for matching and indexing.
+func NormalizeRetailer(retailer string) string {
+	return strings.ToLower(strings.TrimSpace(retailer))
+}
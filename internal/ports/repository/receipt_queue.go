@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-receipt-processor/internal/domain"
+)
+
+// ErrQueueFull is returned by queues that are at capacity and cannot accept another job.
+var ErrQueueFull = errors.New("receipt queue is full")
+
+// ErrJobNotFound is returned by queues that are not tracking a job with the requested ID.
+var ErrJobNotFound = errors.New("job not found")
+
+// ReceiptQueue holds receipts waiting for asynchronous processing and tracks each job until it succeeds or is removed.
+type ReceiptQueue interface {
+	// Enqueue adds a pending job. It returns ErrQueueFull instead of waiting when the queue is at capacity.
+	Enqueue(ctx context.Context, job domain.ReceiptJob) error
+
+	// Dequeue waits for the next pending job, marks it as processing and returns it.
+	// It returns the context's error if ctx is done first.
+	Dequeue(ctx context.Context) (job domain.ReceiptJob, err error)
+
+	// Finish records the outcome of a dequeued job. Successful jobs stop being tracked, since their
+	// receipt is now in the ReceiptStore; failed jobs are kept with the failure as their Error.
+	Finish(ctx context.Context, id string, failure error) error
+
+	// Remove stops tracking a job, such as a failed job whose failure is now kept in the DeadLetterStore.
+	// It returns ErrJobNotFound if no job with the ID is tracked.
+	Remove(ctx context.Context, id string) error
+
+	// Job returns a job that is still tracked, or ErrJobNotFound.
+	Job(ctx context.Context, id string) (job domain.ReceiptJob, err error)
+}
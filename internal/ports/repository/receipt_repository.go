@@ -1,9 +1,117 @@
 package repository
 
-import "go-receipt-processor/internal/domain"
+import (
+	"context"
+	"errors"
+	"go-receipt-processor/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ErrReceiptNotFound is returned by stores when no receipt exists for the requested ID.
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+// ErrReceiptDeleted is returned by stores when the requested receipt has been deleted and only its tombstone remains.
+var ErrReceiptDeleted = errors.New("receipt has been deleted")
+
+// ErrRevisionConflict is returned by stores when an update was based on a revision that is no longer current.
+var ErrRevisionConflict = errors.New("receipt has been modified by another request")
+
+// TransientError is returned by stores for failures that may succeed if retried, such as a dropped
+// connection or a server that is briefly unavailable. Any other store error is assumed to fail the same way every time.
+type TransientError struct {
+	Err error
+}
+
+func (e *TransientError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
 
 // ReceiptStore defines the methods required for storing and retrieving receipts.
 type ReceiptStore interface {
-	Save(receipt domain.Receipt) (receiptID string, err error)
-	Find(id string) (receipt domain.Receipt, err error)
+	// Save stores a new receipt under the ID chosen by AssignReceiptID and returns that ID.
+	Save(ctx context.Context, receipt domain.Receipt) (receiptID string, err error)
+	Find(ctx context.Context, id string) (receipt domain.Receipt, err error)
+	Query(ctx context.Context, query ReceiptQuery) (page ReceiptPage, err error)
+
+	// SaveUnique is Save for a receipt that must be the first stored with its Fingerprint. Looking for an earlier
+	// receipt and saving are a single atomic step, so two concurrent submissions of the same receipt cannot both
+	// be saved. If receipts with the fingerprint are already stored, nothing is saved and the ID of the one
+	// EarliestReceipt picks is returned as originalID. A receipt already stored under the receipt's own ID
+	// counts as saved, so a retried save is not reported as a duplicate of itself.
+	SaveUnique(ctx context.Context, receipt domain.Receipt) (receiptID string, originalID string, err error)
+
+	// FindByFingerprint returns every stored receipt whose Fingerprint equals fingerprint, in no particular order.
+	FindByFingerprint(ctx context.Context, fingerprint string) (receipts []domain.Receipt, err error)
+
+	// Update replaces a stored receipt with a new revision, keeping the replaced version in its history.
+	// It returns ErrRevisionConflict if the stored receipt is no longer at expectedRevision.
+	Update(ctx context.Context, receipt domain.Receipt, expectedRevision int) error
+
+	// Revisions returns every earlier revision of a receipt, oldest first.
+	Revisions(ctx context.Context, id string) (revisions []domain.Receipt, err error)
+
+	// Delete removes a receipt's data, including earlier revisions, and leaves the given tombstone in its place.
+	// It returns ErrReceiptNotFound for unknown IDs and ErrReceiptDeleted if the receipt is already gone.
+	Delete(ctx context.Context, id string, tombstone domain.Tombstone) error
+
+	// Erase deletes every receipt matching the selector, leaving a copy of the audit tombstone
+	// (with ReceiptID filled in) for each one, and returns the IDs that were erased.
+	Erase(ctx context.Context, selector ErasureSelector, audit domain.Tombstone) (erasedIDs []string, err error)
+}
+
+// AssignReceiptID returns the ID a store saves a new receipt under: the ID already assigned to it,
+// such as the ID of the job it was queued as, or a new UUID if it has none.
+func AssignReceiptID(receipt domain.Receipt) string {
+	if receipt.ID != "" {
+		return receipt.ID
+	}
+	return uuid.New().String()
+}
+
+// EarliestReceipt returns the receipt that was received first, breaking ties by ID: the original that
+// later submissions of the same receipt are duplicates of. It returns false if receipts is empty.
+func EarliestReceipt(receipts []domain.Receipt) (domain.Receipt, bool) {
+	var earliest domain.Receipt
+	for i, receipt := range receipts {
+		if i == 0 || receipt.ReceivedAt.Before(earliest.ReceivedAt) ||
+			(receipt.ReceivedAt.Equal(earliest.ReceivedAt) && receipt.ID < earliest.ID) {
+			earliest = receipt
+		}
+	}
+	return earliest, len(receipts) > 0
+}
+
+// ErasureSelector identifies the receipts covered by a bulk erasure request.
+// Exactly one of its fields is expected to be set.
+type ErasureSelector struct {
+	CustomerID string // Every receipt submitted with this customer ID
+	Retailer   string // Every receipt from this retailer, ignoring case and surrounding whitespace
+}
+
+// Matches reports whether a receipt is covered by the selector.
+func (s ErasureSelector) Matches(receipt domain.Receipt) bool {
+	if s.CustomerID != "" {
+		return receipt.CustomerID == s.CustomerID
+	}
+	if s.Retailer != "" {
+		return NormalizeRetailer(receipt.Retailer) == NormalizeRetailer(s.Retailer)
+	}
+	return false
+}
+
+// BackupStore is implemented by stores that can write a consistent copy of their data
+// to a file while continuing to serve reads and writes.
+type BackupStore interface {
+	BackupToFile(ctx context.Context, path string) (bytesWritten int64, err error)
+}
+
+// HealthChecker is implemented by stores that depend on an external resource, such as a
+// database connection pool, whose availability should gate readiness.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) (stats map[string]int64, err error)
 }
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-receipt-processor/internal/domain"
+)
+
+// ErrSubscriptionNotFound is returned by webhook stores when no subscription exists for the requested ID.
+var ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+
+// WebhookStore keeps webhook subscriptions and the log of deliveries made to each of them.
+type WebhookStore interface {
+	// CreateSubscription stores a new subscription under a generated ID and returns it.
+	CreateSubscription(ctx context.Context, subscription domain.WebhookSubscription) (created domain.WebhookSubscription, err error)
+
+	// Subscriptions returns every subscription, oldest first.
+	Subscriptions(ctx context.Context) (subscriptions []domain.WebhookSubscription, err error)
+
+	// Subscription returns a subscription by ID, or ErrSubscriptionNotFound.
+	Subscription(ctx context.Context, id string) (subscription domain.WebhookSubscription, err error)
+
+	// DeleteSubscription removes a subscription and its delivery log, or returns ErrSubscriptionNotFound.
+	DeleteSubscription(ctx context.Context, id string) error
+
+	// RecordDelivery appends an attempt to its subscription's delivery log. Stores may keep only the most recent attempts.
+	RecordDelivery(ctx context.Context, delivery domain.WebhookDelivery) error
+
+	// Deliveries returns a subscription's delivery log, most recent attempt first, or ErrSubscriptionNotFound.
+	Deliveries(ctx context.Context, subscriptionID string) (deliveries []domain.WebhookDelivery, err error)
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"context"
+	"time"
+)
+
+// ExponentialBackoff returns how long to wait after the given failed attempt (1 for the first):
+// initial, doubled for every later attempt and capped at max. A zero max leaves it unbounded.
+func ExponentialBackoff(initial, max time.Duration, attempt int) time.Duration {
+	backoff := initial
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if max > 0 && backoff >= max {
+			break
+		}
+	}
+	if max > 0 && backoff > max {
+		return max
+	}
+	return backoff
+}
+
+// SleepContext waits for d, returning the context's error early if ctx is done first.
+func SleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
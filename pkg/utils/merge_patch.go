@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MergePatch applies a JSON Merge Patch (RFC 7386) to a JSON document and returns the patched document.
+//
+// Members of the patch replace those of the document, members set to null are removed,
+// and nested objects are merged recursively. Any patch that is not an object replaces the document.
+func MergePatch(document, patch []byte) ([]byte, error) {
+	var target, changes interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, fmt.Errorf("invalid document: %v", err)
+	}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %v", err)
+	}
+	return json.Marshal(mergeValue(target, changes))
+}
+
+func mergeValue(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergeValue(targetObject[key], value)
+	}
+	return targetObject
+}
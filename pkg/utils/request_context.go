@@ -0,0 +1,33 @@
+package utils
+
+import "context"
+
+// requestContextKey is an unexported type so values set here cannot collide with other packages' keys.
+type requestContextKey int
+
+const (
+	requestIDKey requestContextKey = iota
+	tenantIDKey
+)
+
+// WithRequestID returns a copy of ctx carrying the given request ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFrom returns the request ID stored in ctx, or "" if none was set.
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithTenantID returns a copy of ctx carrying the given tenant ID.
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantIDKey, tenantID)
+}
+
+// TenantIDFrom returns the tenant ID stored in ctx, or "" if none was set.
+func TenantIDFrom(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantIDKey).(string)
+	return tenantID
+}
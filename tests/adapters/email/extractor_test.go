@@ -0,0 +1,163 @@
+package email_test
+
+import (
+	"encoding/json"
+	"go-receipt-processor/internal/adapters/email"
+	"go-receipt-processor/internal/domain"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expectedExtraction is the extraction recorded next to each sample email in testdata, as <name>.json beside <name>.eml
+type expectedExtraction struct {
+	Template   string             `json:"template"`
+	Part       string             `json:"part"`
+	Receipt    domain.Receipt     `json:"receipt"`
+	Confidence map[string]float64 `json:"confidence"`
+}
+
+// newDefaultExtractor builds an Extractor with the built-in templates
+func newDefaultExtractor(t *testing.T) *email.Extractor {
+	templates, err := email.DefaultTemplates()
+	require.NoError(t, err)
+	extractor, err := email.NewExtractor(templates)
+	require.NoError(t, err)
+	return extractor
+}
+
+func TestExtract_Corpus(t *testing.T) {
+	extractor := newDefaultExtractor(t)
+	samples, err := filepath.Glob(filepath.Join("testdata", "*.eml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, samples)
+
+	for _, sample := range samples {
+		name := strings.TrimSuffix(filepath.Base(sample), ".eml")
+		t.Run(name, func(t *testing.T) {
+			f, err := os.Open(sample)
+			require.NoError(t, err)
+			defer f.Close()
+			data, err := os.ReadFile(strings.TrimSuffix(sample, ".eml") + ".json")
+			require.NoError(t, err)
+			var expected expectedExtraction
+			require.NoError(t, json.Unmarshal(data, &expected))
+
+			result, err := extractor.Extract(f)
+
+			require.NoError(t, err)
+			assert.Equal(t, expected.Template, result.Template)
+			assert.Equal(t, expected.Part, result.Part)
+			assert.Equal(t, expected.Receipt, result.Receipt)
+			assert.Equal(t, expected.Confidence, result.Confidence)
+		})
+	}
+}
+
+func TestExtract_CustomTemplatesAreTriedFirst(t *testing.T) {
+	defaults, err := email.DefaultTemplates()
+	require.NoError(t, err)
+	custom := email.Template{
+		Name:     "target-pickup",
+		Retailer: "Target Pickup",
+		From:     []string{"target.com"},
+		Item:     `^(?P<description>Gatorade)\t\$(?P<price>\d+\.\d{2})$`,
+		Total:    `^Total\t\$(?P<total>\d+\.\d{2})$`,
+	}
+	extractor, err := email.NewExtractor(append([]email.Template{custom}, defaults...))
+	require.NoError(t, err)
+	f, err := os.Open(filepath.Join("testdata", "forwarded_target.eml"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	result, err := extractor.Extract(f)
+
+	require.NoError(t, err)
+	assert.Equal(t, "target-pickup", result.Template)
+	assert.Equal(t, "Target Pickup", result.Receipt.Retailer)
+	assert.Len(t, result.Receipt.Items, 2)
+}
+
+func TestExtract_TemplateThatFindsNoItemsFallsBackToGeneric(t *testing.T) {
+	message := "From: Target <orders@target.com>\r\n" +
+		"Subject: Your Target receipt\r\n" +
+		"Date: Sat, 12 Oct 2024 14:33:00 -0500\r\n" +
+		"\r\n" +
+		"TARGET\r\nSoda    2.00\r\nTOTAL    2.00\r\n"
+
+	result, err := newDefaultExtractor(t).Extract(strings.NewReader(message))
+
+	require.NoError(t, err)
+	assert.Equal(t, email.GenericTemplate, result.Template)
+	assert.Equal(t, "Target", result.Receipt.Retailer)
+	assert.Equal(t, "2.00", result.Receipt.Total)
+}
+
+func TestExtract_RejectsMessagesWithoutABody(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		err     error
+	}{
+		{"not a message", "this is not an email", email.ErrInvalidMessage},
+		{"broken multipart", "Content-Type: multipart/mixed; boundary=x\r\n\r\n--x\r\nbroken", email.ErrInvalidMessage},
+		{"only attachments", "Content-Type: multipart/mixed; boundary=x\r\n\r\n--x\r\n" +
+			"Content-Type: application/pdf\r\nContent-Disposition: attachment\r\n\r\n%PDF\r\n--x--\r\n", email.ErrNoBody},
+		{"oversized part", "Content-Type: multipart/mixed; boundary=x\r\n\r\n--x\r\nContent-Type: text/plain\r\n\r\n" +
+			strings.Repeat("a", email.MaxPartBytes+1) + "\r\n--x--\r\n", email.ErrPartTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newDefaultExtractor(t).Extract(strings.NewReader(tt.message))
+
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestNewExtractor_RejectsInvalidTemplates(t *testing.T) {
+	valid := email.Template{
+		Name:     "shop",
+		Retailer: "Shop",
+		From:     []string{"shop.example"},
+		Item:     `^(?P<description>.+)\t(?P<price>\d+\.\d{2})$`,
+		Total:    `^Total\t(?P<total>\d+\.\d{2})$`,
+	}
+	missingField := valid
+	missingField.Retailer = ""
+	badPattern := valid
+	badPattern.Total = `(?P<total>`
+	missingGroup := valid
+	missingGroup.Item = `^(?P<description>.+)\t\d+\.\d{2}$`
+
+	for name, template := range map[string]email.Template{"missing field": missingField, "bad pattern": badPattern, "missing group": missingGroup} {
+		t.Run(name, func(t *testing.T) {
+			_, err := email.NewExtractor([]email.Template{template})
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "templates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name":"shop","retailer":"Shop","from":["shop.example"],"item":"(?P<description>.+) (?P<price>\\d+\\.\\d{2})","total":"Total (?P<total>.+)"}]`), 0o600))
+
+	templates, err := email.LoadTemplates(path)
+
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, "shop", templates[0].Name)
+	assert.Equal(t, []string{"shop.example"}, templates[0].From)
+
+	_, err = email.LoadTemplates(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(`{"name":"shop"}`), 0o600))
+	_, err = email.LoadTemplates(path)
+	assert.Error(t, err)
+}
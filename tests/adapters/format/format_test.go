@@ -0,0 +1,241 @@
+package format_test
+
+import (
+	"encoding/json"
+	"go-receipt-processor/internal/adapters/format"
+	"go-receipt-processor/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var target = domain.Receipt{
+	Retailer:     "Target",
+	PurchaseDate: "2022-01-01",
+	PurchaseTime: "13:01",
+	Items: []domain.Item{
+		{ShortDescription: "Mountain Dew 12PK", Price: "6.49"},
+		{ShortDescription: "Emils Cheese Pizza", Price: "12.25"},
+	},
+	Total: "18.74",
+}
+
+var cornerMarket = domain.Receipt{
+	Retailer:     "M&M Corner Market",
+	PurchaseDate: "2022-03-20",
+	PurchaseTime: "14:33",
+	Items:        []domain.Item{{ShortDescription: "Gatorade, \"Blue\"", Price: "2.25"}},
+	Total:        "2.25",
+	CustomerID:   "customer-1",
+}
+
+func TestXML_RoundTrip(t *testing.T) {
+	for _, receipt := range []domain.Receipt{target, cornerMarket} {
+		encoded, err := format.MarshalReceiptXML(receipt)
+		require.NoError(t, err)
+
+		for _, mediaType := range []string{format.MIMEXML, format.MIMETextXML} {
+			decoded, err := format.DecodeReceipt(mediaType, encoded)
+			require.NoError(t, err)
+			assert.Equal(t, receipt, decoded)
+		}
+	}
+}
+
+func TestXML_MissingElementsAreLeftOutOfTheDocument(t *testing.T) {
+	document, err := format.DecodeReceiptDocument(format.MIMEXML, []byte(`
+		<receipt>
+			<retailer>Target</retailer>
+			<items><item><price>6.49</price></item></items>
+			<total></total>
+		</receipt>`))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"retailer": "Target",
+		"items":    []any{map[string]any{"price": "6.49"}},
+		"total":    "",
+	}, document)
+}
+
+func TestXML_RejectsOtherDocuments(t *testing.T) {
+	_, err := format.DecodeReceipt(format.MIMEXML, []byte(`<invoice><total>1.00</total></invoice>`))
+	assert.Error(t, err)
+
+	_, err = format.DecodeReceipt(format.MIMEXML, []byte(`<receipt><total>`))
+	assert.Error(t, err)
+}
+
+func TestCSV_RoundTrip(t *testing.T) {
+	encoded, err := format.MarshalReceiptsCSV([]domain.Receipt{target, cornerMarket})
+	require.NoError(t, err)
+
+	decoded, err := format.UnmarshalReceiptsCSV(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Receipt{target, cornerMarket}, decoded)
+
+	// A single receipt can be submitted on its own
+	single, err := format.MarshalReceiptsCSV([]domain.Receipt{cornerMarket})
+	require.NoError(t, err)
+	receipt, err := format.DecodeReceipt(format.MIMECSV, single)
+	require.NoError(t, err)
+	assert.Equal(t, cornerMarket, receipt)
+}
+
+func TestCSV_ColumnsInAnyOrderWithoutCustomerID(t *testing.T) {
+	receipt, err := format.DecodeReceipt(format.MIMECSV, []byte(
+		"price,shortDescription,receipt,total,purchaseTime,purchaseDate,retailer\n"+
+			"6.49,Mountain Dew 12PK,a,18.74,13:01,2022-01-01,Target\n"+
+			"12.25,Emils Cheese Pizza,a,18.74,13:01,2022-01-01,Target\n"))
+	require.NoError(t, err)
+	assert.Equal(t, target, receipt)
+}
+
+func TestCSV_RejectsMalformedLayouts(t *testing.T) {
+	const header = "receipt,retailer,purchaseDate,purchaseTime,total,shortDescription,price\n"
+
+	tests := []struct {
+		name    string
+		csv     string
+		wantErr string
+	}{
+		{"empty", "", "csv is empty"},
+		{"header only", header, "no receipts"},
+		{"unknown column", "receipt,retailer,store\n", "unknown csv column 'store'"},
+		{"missing column", "receipt,retailer,purchaseDate,purchaseTime,total,shortDescription\n", "'price' is missing"},
+		{"receipt columns differ", header +
+			"1,Target,2022-01-01,13:01,18.74,Mountain Dew 12PK,6.49\n" +
+			"1,Target,2022-01-01,13:01,18.75,Emils Cheese Pizza,12.25\n", "row 3: total differs"},
+		{"rows not consecutive", header +
+			"1,Target,2022-01-01,13:01,6.49,Mountain Dew 12PK,6.49\n" +
+			"2,Walmart,2022-01-01,13:01,1.00,Gum,1.00\n" +
+			"1,Target,2022-01-01,13:01,6.49,Doritos,1.00\n", "row 4: rows of receipt '1' must be consecutive"},
+		{"several receipts", header +
+			"1,Target,2022-01-01,13:01,6.49,Mountain Dew 12PK,6.49\n" +
+			"2,Walmart,2022-01-01,13:01,1.00,Gum,1.00\n", "csv holds 2 receipts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := format.DecodeReceipt(format.MIMECSV, []byte(tt.csv))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestDecodeReceipt_OtherTypesAreReadAsJSON(t *testing.T) {
+	receipt, err := format.DecodeReceipt("application/x-www-form-urlencoded", []byte(`{"retailer": "Target"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Target", receipt.Retailer)
+
+	_, err = format.DecodeReceiptDocument("application/yaml", nil)
+	assert.Error(t, err)
+}
+
+func TestDecodeReceipt_IgnoresServerOwnedFields(t *testing.T) {
+	submitted := map[string]any{
+		"id": "chosen-id", "retailer": "Target", "points": 999, "revision": 7, "pointsDelta": 999,
+		"revisedAt": "2024-01-01T00:00:00Z", "receivedAt": "2024-01-01T00:00:00Z", "scoringVersion": "v0",
+		"fingerprint": "f1", "duplicateOf": "other-id",
+	}
+	asJSON, err := json.Marshal(submitted)
+	require.NoError(t, err)
+	asMsgpack, err := format.MarshalMsgpack(submitted)
+	require.NoError(t, err)
+
+	for mediaType, data := range map[string][]byte{format.MIMEJSON: asJSON, format.MIMEMsgpack: asMsgpack} {
+		receipt, err := format.DecodeReceipt(mediaType, data)
+		require.NoError(t, err, mediaType)
+		assert.Equal(t, domain.Receipt{Retailer: "Target"}, receipt, mediaType)
+	}
+}
+
+func TestProtobuf_RoundTrip(t *testing.T) {
+	for _, receipt := range []domain.Receipt{target, cornerMarket} {
+		encoded, err := format.MarshalReceiptProtobuf(receipt)
+		require.NoError(t, err)
+
+		decoded, err := format.DecodeReceipt(format.MIMEProtobuf, encoded)
+		require.NoError(t, err)
+		assert.Equal(t, receipt, decoded)
+	}
+}
+
+func TestProtobuf_EmptyFieldsAreLeftOutOfTheDocument(t *testing.T) {
+	encoded, err := format.MarshalReceiptProtobuf(domain.Receipt{
+		Retailer: "Target",
+		Items:    []domain.Item{{Price: "6.49"}},
+	})
+	require.NoError(t, err)
+
+	document, err := format.DecodeReceiptDocument(format.MIMEProtobuf, encoded)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"retailer": "Target",
+		"items":    []any{map[string]any{"price": "6.49"}},
+	}, document)
+
+	_, err = format.DecodeReceipt(format.MIMEProtobuf, []byte("not a message"))
+	assert.Error(t, err)
+}
+
+func TestMsgpack_RoundTrip(t *testing.T) {
+	for _, receipt := range []domain.Receipt{target, cornerMarket} {
+		encoded, err := format.MarshalMsgpack(receipt)
+		require.NoError(t, err)
+
+		decoded, err := format.DecodeReceipt(format.MIMEMsgpack, encoded)
+		require.NoError(t, err)
+		assert.Equal(t, receipt, decoded)
+	}
+}
+
+func TestMsgpack_DocumentHasJSONTypes(t *testing.T) {
+	encoded, err := format.MarshalMsgpack(map[string]any{
+		"retailer": "Target",
+		"items":    []any{map[string]any{"shortDescription": []byte("Pepsi"), "price": 1.25}},
+		"total":    int8(1),
+	})
+	require.NoError(t, err)
+
+	document, err := format.DecodeReceiptDocument(format.MIMEMsgpack, encoded)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"retailer": "Target",
+		"items":    []any{map[string]any{"shortDescription": "Pepsi", "price": 1.25}},
+		"total":    float64(1),
+	}, document)
+
+	encoded, err = format.MarshalMsgpack([]string{"Target"})
+	require.NoError(t, err)
+	_, err = format.DecodeReceiptDocument(format.MIMEMsgpack, encoded)
+	assert.Error(t, err)
+}
+
+func BenchmarkDecodeReceipt(b *testing.B) {
+	encoders := []struct {
+		name      string
+		mediaType string
+		encode    func(domain.Receipt) ([]byte, error)
+	}{
+		{"json", format.MIMEJSON, func(r domain.Receipt) ([]byte, error) { return json.Marshal(r) }},
+		{"xml", format.MIMEXML, format.MarshalReceiptXML},
+		{"protobuf", format.MIMEProtobuf, format.MarshalReceiptProtobuf},
+		{"msgpack", format.MIMEMsgpack, func(r domain.Receipt) ([]byte, error) { return format.MarshalMsgpack(r) }},
+	}
+	for _, encoder := range encoders {
+		data, err := encoder.encode(target)
+		require.NoError(b, err)
+
+		b.Run(encoder.name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for range b.N {
+				if _, err := format.DecodeReceipt(encoder.mediaType, data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
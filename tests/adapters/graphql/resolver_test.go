@@ -0,0 +1,149 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-receipt-processor/internal/adapters/graphql"
+	"go-receipt-processor/internal/adapters/memory"
+	"go-receipt-processor/internal/application"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/tests/local_mocks"
+	"testing"
+
+	graphqlGo "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// exec runs a query against schema and decodes its data, failing the test on errors
+func exec(t *testing.T, schema *graphqlGo.Schema, query string, variables map[string]interface{}) map[string]interface{} {
+	result := schema.Exec(context.Background(), query, "", variables)
+	require.Empty(t, result.Errors)
+
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal(result.Data, &data))
+	return data
+}
+
+// newSchema builds the schema on a real service and the shared in-memory store
+func newSchema(t *testing.T) *graphqlGo.Schema {
+	calculator := application.NewPointsCalculator(application.NewPointsCalculatorHelper())
+	schema, err := graphql.NewSchema(application.NewReceiptService(calculator, memory.NewReceiptStore()), calculator)
+	require.NoError(t, err)
+	return schema
+}
+
+const processReceipt = `mutation ($input: ReceiptInput!) {
+	processReceipt(input: $input) {
+		id
+		receipt { retailer points items { shortDescription price } breakdown { rule points } }
+	}
+}`
+
+func TestSchema_ProcessReceiptThenQuery(t *testing.T) {
+	schema := newSchema(t)
+
+	data := exec(t, schema, processReceipt, map[string]interface{}{"input": map[string]interface{}{
+		"retailer":     "GraphQL Corner Market",
+		"purchaseDate": "2022-03-20",
+		"purchaseTime": "14:33",
+		"items": []interface{}{
+			map[string]interface{}{"shortDescription": "Gatorade", "price": "2.25"},
+			map[string]interface{}{"shortDescription": "Gatorade", "price": "2.25"},
+		},
+		"total": "4.50",
+	}})
+
+	payload := data["processReceipt"].(map[string]interface{})
+	id := payload["id"].(string)
+	assert.NotEmpty(t, id)
+	receipt := payload["receipt"].(map[string]interface{})
+	assert.Equal(t, "GraphQL Corner Market", receipt["retailer"])
+	assert.Len(t, receipt["items"], 2)
+
+	points := receipt["points"].(float64)
+	sum := 0.0
+	for _, rule := range receipt["breakdown"].([]interface{}) {
+		sum += rule.(map[string]interface{})["points"].(float64)
+	}
+	assert.Equal(t, points, sum)
+
+	data = exec(t, schema, `query ($id: ID!) { points(id: $id) receipt(id: $id) { id purchaseDate revision customerId } }`,
+		map[string]interface{}{"id": id})
+	assert.Equal(t, points, data["points"])
+	assert.Equal(t, map[string]interface{}{"id": id, "purchaseDate": "2022-03-20", "revision": float64(1), "customerId": nil}, data["receipt"])
+
+	data = exec(t, schema, `{ receipts(filter: {retailer: "graphql corner market"}, first: 5) { receipts { id } nextCursor } }`, nil)
+	assert.Equal(t, map[string]interface{}{
+		"receipts":   []interface{}{map[string]interface{}{"id": id}},
+		"nextCursor": nil,
+	}, data["receipts"])
+}
+
+func TestSchema_ProcessReceipt_InvalidInput(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	schema, err := graphql.NewSchema(mockService, new(local_mocks.MockPointsCalculator))
+	require.NoError(t, err)
+
+	result := schema.Exec(context.Background(), processReceipt, "", map[string]interface{}{"input": map[string]interface{}{
+		"retailer": "", "purchaseDate": "2022-03-20", "purchaseTime": "14:33", "items": []interface{}{}, "total": "1.00",
+	}})
+
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Message, "invalid receipt")
+	mockService.AssertNotCalled(t, "ProcessReceipt", mock.Anything, mock.Anything)
+}
+
+func TestSchema_Receipt_UnknownIsNull(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetReceipt", mock.Anything, "missing").Return(domain.Receipt{}, fmt.Errorf("failed to find receipt: %w", repository.ErrReceiptNotFound))
+	schema, err := graphql.NewSchema(mockService, new(local_mocks.MockPointsCalculator))
+	require.NoError(t, err)
+
+	data := exec(t, schema, `{ receipt(id: "missing") { id } }`, nil)
+
+	assert.Nil(t, data["receipt"])
+}
+
+func TestSchema_Receipts_PassesQuery(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	minPoints := 10
+	mockService.On("ListReceipts", mock.Anything, repository.ReceiptQuery{
+		Retailer:         "Target",
+		PurchaseDateFrom: "2022-01-01",
+		MinPoints:        &minPoints,
+		SortBy:           repository.SortByPoints,
+		SortDescending:   true,
+		Limit:            2,
+		Cursor:           "abc",
+	}).Return(repository.ReceiptPage{Receipts: []domain.Receipt{{ID: "1"}, {ID: "2"}}, NextCursor: "def"}, nil)
+	schema, err := graphql.NewSchema(mockService, new(local_mocks.MockPointsCalculator))
+	require.NoError(t, err)
+
+	data := exec(t, schema, `{
+		receipts(filter: {retailer: "Target", purchaseDateFrom: "2022-01-01", minPoints: 10}, sort: POINTS, descending: true, first: 2, after: "abc") {
+			receipts { id }
+			nextCursor
+		}
+	}`, nil)
+
+	assert.Equal(t, "def", data["receipts"].(map[string]interface{})["nextCursor"])
+	mockService.AssertExpectations(t)
+}
+
+func TestSchema_Receipts_RejectsInvalidArguments(t *testing.T) {
+	schema, err := graphql.NewSchema(new(local_mocks.MockReceiptService), new(local_mocks.MockPointsCalculator))
+	require.NoError(t, err)
+
+	for _, query := range []string{
+		`{ receipts(first: 0) { nextCursor } }`,
+		`{ receipts(first: 101) { nextCursor } }`,
+		`{ receipts(filter: {purchaseDateTo: "20/03/2022"}) { nextCursor } }`,
+	} {
+		result := schema.Exec(context.Background(), query, "", nil)
+		assert.NotEmpty(t, result.Errors, query)
+	}
+}
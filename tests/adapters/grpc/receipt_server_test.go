@@ -0,0 +1,166 @@
+package grpc_test
+
+import (
+	"context"
+	"fmt"
+	adaptersGrpc "go-receipt-processor/internal/adapters/grpc"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/grpc/receiptpb"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/pkg/utils"
+	"go-receipt-processor/tests/local_mocks"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	googleGrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var pbReceipt = &receiptpb.Receipt{
+	Retailer:     "StoreABC",
+	PurchaseDate: "2024-11-29",
+	PurchaseTime: "15:30",
+	Items: []*receiptpb.Item{
+		{ShortDescription: "Item 1", Price: "5.00"},
+		{ShortDescription: "Item 2", Price: "5.00"},
+	},
+	Total: "10.00",
+}
+
+// newClient serves a ReceiptServer backed by service on an in-process bufconn listener and returns a client for it
+func newClient(t *testing.T, service internalHttp.ReceiptService) receiptpb.ReceiptServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := googleGrpc.NewServer(
+		googleGrpc.UnaryInterceptor(adaptersGrpc.RequestContextUnaryInterceptor(time.Second)),
+		googleGrpc.StreamInterceptor(adaptersGrpc.RequestContextStreamInterceptor()),
+	)
+	receiptpb.RegisterReceiptServiceServer(server, adaptersGrpc.NewReceiptServer(service, time.Second))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := googleGrpc.NewClient("passthrough:///bufnet",
+		googleGrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		googleGrpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return receiptpb.NewReceiptServiceClient(conn)
+}
+
+func TestReceiptServer_ProcessReceipt(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.MatchedBy(func(ctx context.Context) bool {
+		_, hasDeadline := ctx.Deadline()
+		return utils.RequestIDFrom(ctx) == "req-1" && utils.TenantIDFrom(ctx) == "tenant-1" && hasDeadline
+	}), local_mocks.MockReceipt).Return("12345", nil)
+	client := newClient(t, mockService)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		adaptersGrpc.RequestIDMetadata, "req-1", adaptersGrpc.TenantIDMetadata, "tenant-1")
+	var header metadata.MD
+	resp, err := client.ProcessReceipt(ctx, &receiptpb.ProcessReceiptRequest{Receipt: pbReceipt}, googleGrpc.Header(&header))
+
+	require.NoError(t, err)
+	assert.Equal(t, "12345", resp.GetId())
+	assert.Equal(t, []string{"req-1"}, header.Get(adaptersGrpc.RequestIDMetadata))
+	mockService.AssertExpectations(t)
+}
+
+func TestReceiptServer_ProcessReceipt_InvalidReceipt(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	client := newClient(t, mockService)
+
+	_, err := client.ProcessReceipt(context.Background(), &receiptpb.ProcessReceiptRequest{
+		Receipt: &receiptpb.Receipt{Retailer: "StoreABC"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.ProcessReceipt(context.Background(), &receiptpb.ProcessReceiptRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	mockService.AssertNotCalled(t, "ProcessReceipt", mock.Anything, mock.Anything)
+}
+
+func TestReceiptServer_ProcessReceipt_Duplicate(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).
+		Return("", fmt.Errorf("unable to process receipt: %w", &internalHttp.DuplicateReceiptError{OriginalID: "original"}))
+	client := newClient(t, mockService)
+
+	_, err := client.ProcessReceipt(context.Background(), &receiptpb.ProcessReceiptRequest{Receipt: pbReceipt})
+
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "original")
+}
+
+func TestReceiptServer_GetPoints(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetPoints", mock.Anything, "12345").Return(28, nil)
+	mockService.On("GetPoints", mock.Anything, "missing").Return(0, fmt.Errorf("failed to find receipt: %w", repository.ErrReceiptNotFound))
+	mockService.On("GetPoints", mock.Anything, "queued").Return(0, &internalHttp.ReceiptPendingError{Status: domain.JobStatusPending})
+	client := newClient(t, mockService)
+
+	resp, err := client.GetPoints(context.Background(), &receiptpb.GetPointsRequest{Id: "12345"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(28), resp.GetPoints())
+
+	_, err = client.GetPoints(context.Background(), &receiptpb.GetPointsRequest{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetPoints(context.Background(), &receiptpb.GetPointsRequest{Id: "queued"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestReceiptServer_ProcessReceipts(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool { return r.Retailer == "StoreABC" })).Return("first", nil)
+	mockService.On("ProcessReceipt", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool { return r.Retailer == "Broken" })).
+		Return("", fmt.Errorf("unable to process receipt: store unavailable"))
+	client := newClient(t, mockService)
+
+	stream, err := client.ProcessReceipts(context.Background())
+	require.NoError(t, err)
+
+	broken := &receiptpb.Receipt{Retailer: "Broken", PurchaseDate: "2024-11-29", PurchaseTime: "14:30", Total: "1.00",
+		Items: []*receiptpb.Item{{ShortDescription: "Item", Price: "1.00"}}}
+	// The Receipt schema rejects impossible times before the service sees them
+	outOfRange := &receiptpb.Receipt{Retailer: "OutOfRange", PurchaseDate: "2024-11-29", PurchaseTime: "25:00", Total: "1.00",
+		Items: []*receiptpb.Item{{ShortDescription: "Item", Price: "1.00"}}}
+	for _, receipt := range []*receiptpb.Receipt{pbReceipt, {Retailer: "Incomplete"}, broken, outOfRange} {
+		require.NoError(t, stream.Send(&receiptpb.ProcessReceiptRequest{Receipt: receipt}))
+
+		// Each result arrives before the next receipt is sent
+		result, err := stream.Recv()
+		require.NoError(t, err)
+		switch result.GetIndex() {
+		case 0:
+			assert.Equal(t, "first", result.GetId())
+			assert.Equal(t, uint32(codes.OK), result.GetCode())
+		case 1:
+			assert.Empty(t, result.GetId())
+			assert.Equal(t, uint32(codes.InvalidArgument), result.GetCode())
+		case 2:
+			assert.Equal(t, uint32(codes.Internal), result.GetCode())
+			assert.Contains(t, result.GetMessage(), "store unavailable")
+		case 3:
+			assert.Equal(t, uint32(codes.InvalidArgument), result.GetCode())
+			assert.Contains(t, result.GetMessage(), "purchaseTime")
+		}
+	}
+	require.NoError(t, stream.CloseSend())
+
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+	mockService.AssertExpectations(t)
+}
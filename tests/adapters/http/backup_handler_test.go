@@ -0,0 +1,71 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBackupHandler_Success(t *testing.T) {
+	// Arrange
+	mockStore := new(local_mocks.MockBackupStore)
+	mockStore.On("BackupToFile", mock.Anything, mock.MatchedBy(func(path string) bool {
+		return filepath.Dir(path) == "/backups"
+	})).Return(int64(4096), nil)
+
+	handler := adaptersHttp.NewBackupHandler(mockStore, "/backups")
+	router := gin.Default()
+	router.POST("/admin/backup", handler.Backup)
+
+	// Act
+	req, err := http.NewRequest("POST", "/admin/backup", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body response.BackupResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, int64(4096), body.Bytes)
+	assert.Regexp(t, `^/backups/receipts-\d{8}T\d{6}Z\.db$`, body.Path)
+
+	mockStore.AssertExpectations(t)
+}
+
+func TestBackupHandler_Error(t *testing.T) {
+	// Arrange
+	mockStore := new(local_mocks.MockBackupStore)
+	mockStore.On("BackupToFile", mock.Anything, mock.Anything).Return(int64(0), fmt.Errorf("disk full"))
+
+	handler := adaptersHttp.NewBackupHandler(mockStore, "/backups")
+	router := gin.Default()
+	router.POST("/admin/backup", handler.Backup)
+
+	// Act
+	req, err := http.NewRequest("POST", "/admin/backup", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":"disk full"}`, w.Body.String())
+
+	mockStore.AssertExpectations(t)
+}
@@ -0,0 +1,131 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/domain"
+	portsHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const validBatchReceipt = `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",
+	"items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}], "total": "6.49"}`
+
+// serveBatch routes a single batch request to a BatchProcessHandler backed by mockService
+func serveBatch(t *testing.T, mockService *local_mocks.MockReceiptService, maxSize int, body string) *httptest.ResponseRecorder {
+	return serveBatchAs(t, mockService, maxSize, "application/json", body)
+}
+
+// serveBatchAs is serveBatch for a body of the given Content-Type
+func serveBatchAs(t *testing.T, mockService *local_mocks.MockReceiptService, maxSize int, contentType, body string) *httptest.ResponseRecorder {
+	handler := adaptersHttp.NewBatchProcessHandler(mockService, maxSize)
+	router := gin.Default()
+	router.POST("/receipts/batch", handler.ProcessBatch)
+
+	req, err := http.NewRequest("POST", "/receipts/batch", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestProcessBatch_PartialSuccess(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	// Only the two valid receipts reach the service; the invalid one in between is rejected up front
+	mockService.On("ProcessReceipts", mock.Anything, mock.MatchedBy(func(receipts []domain.Receipt) bool {
+		return len(receipts) == 2
+	})).Return([]portsHttp.ProcessResult{
+		{ReceiptID: "id-0"},
+		{Err: context.DeadlineExceeded},
+	})
+
+	w := serveBatch(t, mockService, 10, `[`+validBatchReceipt+`, {"retailer": "Target"}, `+validBatchReceipt+`]`)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body response.BatchProcessResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 1, body.Succeeded)
+	assert.Equal(t, 2, body.Failed)
+	require.Len(t, body.Results, 3)
+	assert.Equal(t, "id-0", body.Results[0].ID)
+	assert.Equal(t, http.StatusBadRequest, body.Results[1].Error.Status)
+	assert.Equal(t, 2, body.Results[2].Index)
+	assert.Equal(t, http.StatusGatewayTimeout, body.Results[2].Error.Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestProcessBatch_TooLarge(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+
+	w := serveBatch(t, mockService, 1, `[`+validBatchReceipt+`, `+validBatchReceipt+`]`)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	mockService.AssertNotCalled(t, "ProcessReceipts", mock.Anything, mock.Anything)
+}
+
+func TestProcessBatch_BodyTooLarge(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	// A single receipt padded past the 1 MiB allowed per receipt is refused before it is decoded
+	oversized := `[{"retailer": "` + strings.Repeat("T", 1<<20) + `"}]`
+
+	for _, contentType := range []string{"application/json", "text/csv"} {
+		w := serveBatchAs(t, mockService, 1, contentType, oversized)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, contentType)
+	}
+	mockService.AssertNotCalled(t, "ProcessReceipts", mock.Anything, mock.Anything)
+}
+
+func TestProcessBatch_InvalidBody(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+
+	for _, body := range []string{`{"retailer": "Target"}`, `[]`} {
+		w := serveBatch(t, mockService, 10, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestProcessBatch_CSV(t *testing.T) {
+	csvBody := "receipt,retailer,purchaseDate,purchaseTime,total,shortDescription,price\n" +
+		"1,Target,2022-01-01,13:01,18.74,Mountain Dew 12PK,6.49\n" +
+		"1,Target,2022-01-01,13:01,18.74,Emils Cheese Pizza,12.25\n" +
+		"2,Corner Market,2022-03-20,14:33,9.00,Gatorade,2.25\n" +
+		"3,Walgreens,2022-01-02,25:00,2.65,Pepsi - 12-oz,1.25\n"
+
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipts", mock.Anything, mock.MatchedBy(func(receipts []domain.Receipt) bool {
+		return len(receipts) == 2 && len(receipts[0].Items) == 2 && receipts[1].Retailer == "Corner Market"
+	})).Return([]portsHttp.ProcessResult{{ReceiptID: "id-0"}, {ReceiptID: "id-1"}})
+
+	w := serveBatchAs(t, mockService, 10, "text/csv; charset=utf-8", csvBody)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body response.BatchProcessResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 2, body.Succeeded)
+	require.Len(t, body.Results, 3)
+	assert.Equal(t, "id-1", body.Results[1].ID)
+	assert.Equal(t, http.StatusBadRequest, body.Results[2].Error.Status)
+	assert.Contains(t, body.Results[2].Error.Message, "purchaseTime")
+	mockService.AssertExpectations(t)
+
+	// The batch size limit counts receipts, not rows
+	w = serveBatchAs(t, new(local_mocks.MockReceiptService), 2, "text/csv", csvBody)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	w = serveBatchAs(t, new(local_mocks.MockReceiptService), 10, "text/csv", "receipt,retailer\n1,Target\n")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
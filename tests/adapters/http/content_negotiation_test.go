@@ -0,0 +1,271 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"go-receipt-processor/internal/adapters/format"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/grpc/receiptpb"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+var negotiatedReceipt = domain.Receipt{
+	Retailer:     "M&M Corner Market",
+	PurchaseDate: "2022-03-20",
+	PurchaseTime: "14:33",
+	Items: []domain.Item{
+		{ShortDescription: "Gatorade", Price: "2.25"},
+		{ShortDescription: "Gatorade", Price: "2.25"},
+	},
+	Total:      "4.50",
+	CustomerID: "customer-1",
+}
+
+func TestProcessReceipt_AcceptsXMLAndCSV(t *testing.T) {
+	xmlBody, err := format.MarshalReceiptXML(negotiatedReceipt)
+	require.NoError(t, err)
+	csvBody, err := format.MarshalReceiptsCSV([]domain.Receipt{negotiatedReceipt})
+	require.NoError(t, err)
+
+	for contentType, body := range map[string][]byte{
+		"application/xml":          xmlBody,
+		"text/xml; charset=utf-8":  xmlBody,
+		"text/csv; header=present": csvBody,
+	} {
+		t.Run(contentType, func(t *testing.T) {
+			mockService := new(local_mocks.MockReceiptService)
+			mockService.On("ProcessReceipt", mock.Anything, negotiatedReceipt).Return("12345", nil).Once()
+			router := newValidatedRouter(t, mockService)
+
+			w := serveValidated(router, "POST", "/receipt/process", contentType, string(body))
+
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.JSONEq(t, `{"id": "12345"}`, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessReceipt_ValidatesXMLAndCSVLikeJSON(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	router := newValidatedRouter(t, mockService)
+
+	invalid := negotiatedReceipt
+	invalid.Items = []domain.Item{{ShortDescription: "Gatorade", Price: "2.5"}}
+	xmlBody, err := format.MarshalReceiptXML(invalid)
+	require.NoError(t, err)
+	csvBody, err := format.MarshalReceiptsCSV([]domain.Receipt{invalid})
+	require.NoError(t, err)
+
+	for contentType, body := range map[string]string{
+		"application/xml": string(xmlBody),
+		"text/csv":        string(csvBody),
+		"text/xml":        `<receipt><retailer>Target</retailer></receipt>`,
+	} {
+		t.Run(contentType, func(t *testing.T) {
+			w := serveValidated(router, "POST", "/receipt/process", contentType, body)
+
+			require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+			var body validationResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			require.NotEmpty(t, body.Fields)
+			if contentType == "text/xml" {
+				assert.Len(t, body.Fields, 4)
+				return
+			}
+			require.Len(t, body.Fields, 1)
+			assert.Equal(t, "items[0].price", body.Fields[0].Field)
+		})
+	}
+
+	// A body that cannot be decoded is rejected without field paths
+	w := serveValidated(router, "POST", "/receipt/process", "text/csv", "receipt,store\n")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown csv column")
+	mockService.AssertNotCalled(t, "ProcessReceipt", mock.Anything, mock.Anything)
+}
+
+// getPointsAs requests the points of id with the given Accept header
+func getPointsAs(t *testing.T, mockService *local_mocks.MockReceiptService, id, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/receipt/"+id+"/points", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	newValidatedRouter(t, mockService).ServeHTTP(w, req)
+	return w
+}
+
+func TestGetPoints_HonorsAccept(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetPoints", mock.Anything, "abc").Return(109, nil)
+
+	w := getPointsAs(t, mockService, "abc", "")
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	var fromJSON response.GetReceiptPointsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fromJSON))
+	assert.Equal(t, 109, fromJSON.Points)
+
+	w = getPointsAs(t, mockService, "abc", "application/xml")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `<receipt><points>109</points></receipt>`, w.Body.String())
+	var fromXML response.GetReceiptPointsResponse
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &fromXML))
+	assert.Equal(t, 109, fromXML.Points)
+
+	w = getPointsAs(t, mockService, "abc", "text/plain")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "109", w.Body.String())
+
+	// The first supported type in the client's order of preference wins
+	w = getPointsAs(t, mockService, "abc", "image/png, text/plain;q=0.9, application/json;q=0.5")
+	assert.Equal(t, "109", w.Body.String())
+
+	w = getPointsAs(t, mockService, "abc", "image/png")
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+}
+
+func TestGetPoints_NegotiatesPendingAndErrors(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetPoints", mock.Anything, "queued").Return(0, &internalHttp.ReceiptPendingError{Status: domain.JobStatusPending})
+	mockService.On("GetPoints", mock.Anything, "missing").Return(0, fmt.Errorf("failed to find receipt: %w", repository.ErrReceiptNotFound))
+
+	w := getPointsAs(t, mockService, "queued", "application/xml")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var status response.ReceiptStatusResponse
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, response.ReceiptStatusResponse{XMLName: status.XMLName, ID: "queued", Status: "pending"}, status)
+
+	w = getPointsAs(t, mockService, "queued", "text/plain")
+	assert.Equal(t, "pending", w.Body.String())
+
+	w = getPointsAs(t, mockService, "missing", "text/plain")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "receipt not found")
+}
+
+func TestProcessReceipt_AcceptsProtobufAndMsgpack(t *testing.T) {
+	protobufBody, err := format.MarshalReceiptProtobuf(negotiatedReceipt)
+	require.NoError(t, err)
+	msgpackBody, err := format.MarshalMsgpack(negotiatedReceipt)
+	require.NoError(t, err)
+
+	for contentType, body := range map[string][]byte{
+		format.MIMEProtobuf: protobufBody,
+		format.MIMEMsgpack:  msgpackBody,
+	} {
+		t.Run(contentType, func(t *testing.T) {
+			mockService := new(local_mocks.MockReceiptService)
+			mockService.On("ProcessReceipt", mock.Anything, negotiatedReceipt).Return("12345", nil).Once()
+			router := newValidatedRouter(t, mockService)
+
+			req := httptest.NewRequest("POST", "/receipt/process", bytes.NewReader(body))
+			req.Header.Set("Content-Type", contentType)
+			req.Header.Set("Accept", contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.Equal(t, contentType, w.Header().Get("Content-Type"))
+			if contentType == format.MIMEProtobuf {
+				var resp receiptpb.ProcessReceiptResponse
+				require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, "12345", resp.GetId())
+			} else {
+				var resp map[string]any
+				require.NoError(t, msgpack.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, "12345", resp["id"])
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessReceipt_ValidatesProtobufAndMsgpackLikeJSON(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	router := newValidatedRouter(t, mockService)
+
+	invalid := negotiatedReceipt
+	invalid.Items = []domain.Item{{ShortDescription: "Gatorade", Price: "2.5"}}
+	protobufBody, err := format.MarshalReceiptProtobuf(invalid)
+	require.NoError(t, err)
+	msgpackBody, err := format.MarshalMsgpack(invalid)
+	require.NoError(t, err)
+
+	// JSON clients are told about the same field
+	w := serveValidated(router, "POST", "/receipt/process", format.MIMEMsgpack, string(msgpackBody))
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	var fromJSON validationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fromJSON))
+	require.Len(t, fromJSON.Fields, 1)
+	assert.Equal(t, "items[0].price", fromJSON.Fields[0].Field)
+
+	req := httptest.NewRequest("POST", "/receipt/process", bytes.NewReader(protobufBody))
+	req.Header.Set("Content-Type", format.MIMEProtobuf)
+	req.Header.Set("Accept", format.MIMEProtobuf)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var fromProtobuf receiptpb.Error
+	require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &fromProtobuf))
+	require.Len(t, fromProtobuf.GetFields(), 1)
+	assert.Equal(t, "items[0].price", fromProtobuf.GetFields()[0].GetField())
+
+	// Empty protobuf fields are reported as missing, like absent JSON fields
+	empty, err := format.MarshalReceiptProtobuf(domain.Receipt{Retailer: "Target"})
+	require.NoError(t, err)
+	w = serveValidated(router, "POST", "/receipt/process", format.MIMEProtobuf, string(empty))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var missing validationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &missing))
+	assert.Len(t, missing.Fields, 4)
+
+	mockService.AssertNotCalled(t, "ProcessReceipt", mock.Anything, mock.Anything)
+}
+
+func TestGetPoints_ProtobufAndMsgpack(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetPoints", mock.Anything, "abc").Return(109, nil)
+	mockService.On("GetPoints", mock.Anything, "queued").Return(0, &internalHttp.ReceiptPendingError{Status: domain.JobStatusPending})
+	mockService.On("GetPoints", mock.Anything, "missing").Return(0, fmt.Errorf("failed to find receipt: %w", repository.ErrReceiptNotFound))
+
+	w := getPointsAs(t, mockService, "abc", format.MIMEProtobuf)
+	assert.Equal(t, format.MIMEProtobuf, w.Header().Get("Content-Type"))
+	var points receiptpb.GetPointsResponse
+	require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &points))
+	assert.Equal(t, int64(109), points.GetPoints())
+
+	w = getPointsAs(t, mockService, "abc", format.MIMEMsgpack)
+	assert.Equal(t, format.MIMEMsgpack, w.Header().Get("Content-Type"))
+	var fromMsgpack map[string]any
+	require.NoError(t, msgpack.Unmarshal(w.Body.Bytes(), &fromMsgpack))
+	assert.EqualValues(t, 109, fromMsgpack["points"])
+
+	w = getPointsAs(t, mockService, "queued", format.MIMEProtobuf)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var status receiptpb.ReceiptStatus
+	require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, "pending", status.GetStatus())
+
+	w = getPointsAs(t, mockService, "missing", format.MIMEProtobuf)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var notFound receiptpb.Error
+	require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &notFound))
+	assert.Contains(t, notFound.GetError(), "receipt not found")
+}
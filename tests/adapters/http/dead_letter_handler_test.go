@@ -0,0 +1,94 @@
+package http_test
+
+import (
+	"fmt"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var deadLetter = domain.DeadLetter{
+	ID:         "job-1",
+	Receipt:    domain.Receipt{Retailer: "Target", PurchaseDate: "2022-01-01", PurchaseTime: "13:01", Total: "6.49", Items: []domain.Item{{ShortDescription: "Mountain Dew 12PK", Price: "6.49"}}},
+	Reason:     "failed to insert receipt: connection reset",
+	Attempts:   5,
+	EnqueuedAt: time.Date(2024, 11, 29, 14, 30, 0, 0, time.UTC),
+	FailedAt:   time.Date(2024, 11, 29, 14, 31, 0, 0, time.UTC),
+}
+
+const deadLetterJSON = `{
+	"id": "job-1",
+	"receipt": {
+		"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "total": "6.49",
+		"items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}]
+	},
+	"reason": "failed to insert receipt: connection reset",
+	"attempts": 5,
+	"enqueuedAt": "2024-11-29T14:30:00Z",
+	"failedAt": "2024-11-29T14:31:00Z"
+}`
+
+// serveDeadLetters routes a single request to a DeadLetterHandler backed by mockService
+func serveDeadLetters(t *testing.T, mockService *local_mocks.MockReceiptService, method, url string) *httptest.ResponseRecorder {
+	handler := adaptersHttp.NewDeadLetterHandler(mockService)
+	router := gin.Default()
+	router.GET("/admin/dead-letters", handler.ListDeadLetters)
+	router.GET("/admin/dead-letters/:id", handler.GetDeadLetter)
+	router.POST("/admin/dead-letters/:id/requeue", handler.RequeueDeadLetter)
+
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDeadLetterHandler_List(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ListDeadLetters", mock.Anything).Return([]domain.DeadLetter{deadLetter}, nil)
+
+	w := serveDeadLetters(t, mockService, "GET", "/admin/dead-letters")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"deadLetters":[`+deadLetterJSON+`],"count":1}`, w.Body.String())
+}
+
+func TestDeadLetterHandler_Get(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetDeadLetter", mock.Anything, "job-1").Return(deadLetter, nil)
+	mockService.On("GetDeadLetter", mock.Anything, "missing").
+		Return(domain.DeadLetter{}, fmt.Errorf("failed to find dead letter: %w", repository.ErrDeadLetterNotFound))
+
+	w := serveDeadLetters(t, mockService, "GET", "/admin/dead-letters/job-1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, deadLetterJSON, w.Body.String())
+
+	w = serveDeadLetters(t, mockService, "GET", "/admin/dead-letters/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeadLetterHandler_Requeue(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("RequeueDeadLetter", mock.Anything, "job-1").Return(domain.ReceiptJob{ID: "job-1", Status: domain.JobStatusPending}, nil)
+	mockService.On("RequeueDeadLetter", mock.Anything, "job-2").
+		Return(domain.ReceiptJob{}, fmt.Errorf("unable to enqueue receipt: %w", repository.ErrQueueFull))
+
+	w := serveDeadLetters(t, mockService, "POST", "/admin/dead-letters/job-1/requeue")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"id":"job-1","status":"pending"}`, w.Body.String())
+
+	w = serveDeadLetters(t, mockService, "POST", "/admin/dead-letters/job-2/requeue")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+}
@@ -0,0 +1,103 @@
+package http_test
+
+import (
+	"fmt"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// serveDelete routes a single request to a DeleteReceiptHandler backed by mockService
+func serveDelete(t *testing.T, mockService *local_mocks.MockReceiptService, method, url, actor, body string) *httptest.ResponseRecorder {
+	handler := adaptersHttp.NewDeleteReceiptHandler(mockService)
+	router := gin.Default()
+	router.DELETE("/receipt/:id", handler.DeleteReceipt)
+	router.POST("/receipts/erasure", handler.EraseReceipts)
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actor != "" {
+		req.Header.Set(adaptersHttp.ActorHeader, actor)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDeleteReceiptHandler_Success(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	tombstone := domain.Tombstone{
+		ReceiptID: "123",
+		DeletedAt: time.Date(2024, 11, 29, 14, 30, 0, 0, time.UTC),
+		DeletedBy: "alice",
+		Reason:    "duplicate",
+	}
+	mockService.On("DeleteReceipt", mock.Anything, "123", "alice", "duplicate").Return(tombstone, nil)
+
+	w := serveDelete(t, mockService, "DELETE", "/receipt/123?reason=duplicate", "alice", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id": "123", "deletedAt": "2024-11-29T14:30:00Z", "deletedBy": "alice", "reason": "duplicate"}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteReceiptHandler_MissingActorOrReason(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+
+	w := serveDelete(t, mockService, "DELETE", "/receipt/123?reason=duplicate", "", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveDelete(t, mockService, "DELETE", "/receipt/123", "alice", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockService.AssertNotCalled(t, "DeleteReceipt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteReceiptHandler_AlreadyDeleted(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("DeleteReceipt", mock.Anything, "123", "alice", "duplicate").
+		Return(domain.Tombstone{}, fmt.Errorf("failed to delete receipt: %w", repository.ErrReceiptDeleted))
+
+	w := serveDelete(t, mockService, "DELETE", "/receipt/123?reason=duplicate", "alice", "")
+
+	assert.Equal(t, http.StatusGone, w.Code)
+}
+
+func TestEraseReceiptsHandler_Success(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	selector := repository.ErasureSelector{CustomerID: "customer-1"}
+	mockService.On("EraseReceipts", mock.Anything, selector, "dpo", "gdpr request").Return([]string{"1", "2"}, nil)
+
+	w := serveDelete(t, mockService, "POST", "/receipts/erasure", "dpo", `{"customerId": "customer-1", "reason": "gdpr request"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"erasedIds": ["1", "2"], "count": 2}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestEraseReceiptsHandler_InvalidSelector(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+
+	for _, body := range []string{
+		`{"reason": "gdpr"}`,
+		`{"customerId": "customer-1", "retailer": "Target", "reason": "gdpr"}`,
+		`{"customerId": "customer-1"}`,
+	} {
+		w := serveDelete(t, mockService, "POST", "/receipts/erasure", "dpo", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+
+	mockService.AssertNotCalled(t, "EraseReceipts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,136 @@
+package http_test
+
+import (
+	"encoding/json"
+	"go-receipt-processor/internal/adapters/email"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const targetEmail = "From: Target <orders@email.target.com>\r\n" +
+	"Subject: Your Target receipt\r\n" +
+	"Date: Thu, 13 Jan 2022 13:01:00 -0600\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<table><tr><th>Item</th><th>Price</th></tr>" +
+	"<tr><td>Mountain Dew 12PK</td><td>$6.49</td></tr>" +
+	"<tr><td>Emils Cheese Pizza</td><td>$12.25</td></tr>" +
+	"<tr><td>Total</td><td>$18.74</td></tr></table>\r\n"
+
+// serveEmail posts message to an EmailReceiptHandler backed by mockService and the built-in templates
+func serveEmail(t *testing.T, mockService *local_mocks.MockReceiptService, message string) (*httptest.ResponseRecorder, response.ParsedReceiptResponse) {
+	templates, err := email.DefaultTemplates()
+	require.NoError(t, err)
+	extractor, err := email.NewExtractor(templates)
+	require.NoError(t, err)
+
+	router := gin.Default()
+	router.POST("/receipts/email", adaptersHttp.NewEmailReceiptHandler(mockService, extractor).ProcessEmail)
+
+	req := httptest.NewRequest("POST", "/receipts/email", strings.NewReader(message))
+	req.Header.Set("Content-Type", "message/rfc822")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var body response.ParsedReceiptResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return w, body
+}
+
+func TestProcessEmail_ScoresTheReceiptAndReportsTheTemplate(t *testing.T) {
+	expected := domain.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-13",
+		PurchaseTime: "13:01",
+		Items: []domain.Item{
+			{ShortDescription: "Mountain Dew 12PK", Price: "6.49"},
+			{ShortDescription: "Emils Cheese Pizza", Price: "12.25"},
+		},
+		Total: "18.74",
+	}
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, expected).Return("12345", nil).Once()
+
+	w, body := serveEmail(t, mockService, targetEmail)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "12345", body.ID)
+	assert.Equal(t, "target", body.Template)
+	assert.Empty(t, body.Confidence)
+	mockService.AssertExpectations(t)
+}
+
+func TestProcessEmail_ReportsConfidenceWhenNoTemplateMatches(t *testing.T) {
+	message := "From: Corner Deli <hello@cornerdeli.example>\r\n" +
+		"Date: Fri, 1 Mar 2024 12:30:00 -0500\r\n" +
+		"\r\n" +
+		"Sandwich    7.95\r\nTOTAL    7.95\r\n"
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Return("12345", nil).Once()
+
+	w, body := serveEmail(t, mockService, message)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, email.GenericTemplate, body.Template)
+	assert.Equal(t, "Corner Deli", body.Receipt.Retailer)
+	assert.Equal(t, 0.8, body.Confidence["retailer"])
+	assert.Equal(t, 0.6, body.Confidence["purchaseDate"])
+	mockService.AssertExpectations(t)
+}
+
+func TestProcessEmail_RejectsBodiesThatAreNotEmails(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+
+	w, _ := serveEmail(t, mockService, "not an email")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid request payload")
+	mockService.AssertNotCalled(t, "ProcessReceipt", mock.Anything, mock.Anything)
+}
+
+func TestProcessEmail_RejectsOversizedEmails(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		details string
+	}{
+		{"whole email", targetEmail + strings.Repeat("a", 10<<20), "email exceeds"},
+		{"text part", "From: Corner Deli <hello@cornerdeli.example>\r\n\r\n" + strings.Repeat("a", email.MaxPartBytes+1),
+			"email body part exceeds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(local_mocks.MockReceiptService)
+
+			w, _ := serveEmail(t, mockService, tt.message)
+
+			assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+			assert.Contains(t, w.Body.String(), tt.details)
+			mockService.AssertNotCalled(t, "ProcessReceipt", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestProcessEmail_ReportsDuplicates(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).
+		Return("", &internalHttp.DuplicateReceiptError{OriginalID: "original"}).Once()
+
+	w, body := serveEmail(t, mockService, targetEmail)
+
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	assert.Equal(t, "original", body.OriginalID)
+	assert.Equal(t, "target", body.Template)
+}
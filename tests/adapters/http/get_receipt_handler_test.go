@@ -0,0 +1,127 @@
+package http_test
+
+import (
+	"fmt"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var storedReceipt = domain.Receipt{
+	ID:             "123",
+	Retailer:       "Target",
+	PurchaseDate:   "2022-01-01",
+	PurchaseTime:   "13:01",
+	Items:          []domain.Item{{ShortDescription: "Mountain Dew 12PK", Price: "6.49"}},
+	Total:          "6.49",
+	Points:         28,
+	ReceivedAt:     time.Date(2024, 11, 29, 14, 30, 0, 0, time.UTC),
+	ScoringVersion: "v1",
+	Revision:       1,
+}
+
+// serveGetReceipt routes a single GET request to a GetReceiptHandler backed by mockService
+func serveGetReceipt(t *testing.T, mockService *local_mocks.MockReceiptService, url string) *httptest.ResponseRecorder {
+	handler := adaptersHttp.NewGetReceiptHandler(mockService)
+	router := gin.Default()
+	router.GET("/receipt/:id", handler.GetReceipt)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetReceiptHandler_Success(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetReceipt", mock.Anything, "123").Return(storedReceipt, nil)
+
+	w := serveGetReceipt(t, mockService, "/receipt/123")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	expectedResponse := `{
+		"id": "123",
+		"retailer": "Target",
+		"purchaseDate": "2022-01-01",
+		"purchaseTime": "13:01",
+		"items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}],
+		"total": "6.49",
+		"points": 28,
+		"receivedAt": "2024-11-29T14:30:00Z",
+		"scoringVersion": "v1",
+		"revision": 1,
+		"pointsDelta": 0
+	}`
+	assert.JSONEq(t, expectedResponse, w.Body.String())
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestGetReceiptHandler_FieldSelection(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetReceipt", mock.Anything, "123").Return(storedReceipt, nil)
+
+	w := serveGetReceipt(t, mockService, "/receipt/123?fields=retailer,total,points")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"retailer":"Target","total":"6.49","points":28}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestGetReceiptHandler_UnknownField(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetReceipt", mock.Anything, "123").Return(storedReceipt, nil)
+
+	w := serveGetReceipt(t, mockService, "/receipt/123?fields=retailer,secret")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"Invalid query parameter","details":"unknown field 'secret'"}`, w.Body.String())
+}
+
+func TestGetReceiptHandler_OmitsUnknownMetadata(t *testing.T) {
+	// Receipts stored before metadata was recorded have no receivedAt or scoringVersion
+	legacy := storedReceipt
+	legacy.ReceivedAt = time.Time{}
+	legacy.ScoringVersion = ""
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetReceipt", mock.Anything, "123").Return(legacy, nil)
+
+	w := serveGetReceipt(t, mockService, "/receipt/123?fields=id,receivedAt")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":"123"}`, w.Body.String())
+}
+
+func TestGetReceiptHandler_NotFound(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetReceipt", mock.Anything, "missing").
+		Return(domain.Receipt{}, fmt.Errorf("failed to find receipt: %w", repository.ErrReceiptNotFound))
+
+	w := serveGetReceipt(t, mockService, "/receipt/missing")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"failed to find receipt: receipt not found"}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestGetReceiptHandler_Deleted(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetReceipt", mock.Anything, "123").
+		Return(domain.Receipt{}, fmt.Errorf("failed to find receipt: %w", repository.ErrReceiptDeleted))
+
+	w := serveGetReceipt(t, mockService, "/receipt/123")
+
+	assert.Equal(t, http.StatusGone, w.Code)
+}
@@ -3,6 +3,8 @@ package http_test
 import (
 	"fmt"
 	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/domain"
+	portsHttp "go-receipt-processor/internal/ports/core"
 	"go-receipt-processor/tests/local_mocks"
 	externalHttp "net/http"
 	"net/http/httptest"
@@ -10,12 +12,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetPointsHandler_Success(t *testing.T) {
 	// Arrange
 	mockService := new(local_mocks.MockReceiptService)
-	mockService.On("GetPoints", "123").Return(100, nil) // Mock a successful return of 100 points
+	mockService.On("GetPoints", mock.Anything, "123").Return(100, nil) // Mock a successful return of 100 points
 
 	handler := adaptersHttp.NewGetReceiptPointsHandler(mockService)
 	router := gin.Default()
@@ -41,7 +44,7 @@ func TestGetPointsHandler_Success(t *testing.T) {
 func TestGetPointsHandler_Error(t *testing.T) {
 	// Arrange
 	mockService := new(local_mocks.MockReceiptService)
-	mockService.On("GetPoints", "123").Return(0, fmt.Errorf("receipt not found")) // Mock an error return
+	mockService.On("GetPoints", mock.Anything, "123").Return(0, fmt.Errorf("receipt not found")) // Mock an error return
 
 	handler := adaptersHttp.NewGetReceiptPointsHandler(mockService)
 	router := gin.Default()
@@ -63,3 +66,23 @@ func TestGetPointsHandler_Error(t *testing.T) {
 	// Verify that the mock service method was called as expected
 	mockService.AssertExpectations(t)
 }
+
+func TestGetPointsHandler_Pending(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetPoints", mock.Anything, "queued").Return(0, &portsHttp.ReceiptPendingError{Status: domain.JobStatusProcessing})
+
+	handler := adaptersHttp.NewGetReceiptPointsHandler(mockService)
+	router := gin.Default()
+	router.GET("/receipts/:id/points", handler.GetPoints)
+
+	req, err := externalHttp.NewRequest("GET", "/receipts/queued/points", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, externalHttp.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"id":"queued","status":"processing"}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
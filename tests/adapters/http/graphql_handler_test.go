@@ -0,0 +1,54 @@
+package http_test
+
+import (
+	"go-receipt-processor/internal/adapters/graphql"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// serveGraphQL posts body to a GraphQLHandler backed by mockService
+func serveGraphQL(t *testing.T, mockService *local_mocks.MockReceiptService, body string) *httptest.ResponseRecorder {
+	schema, err := graphql.NewSchema(mockService, new(local_mocks.MockPointsCalculator))
+	require.NoError(t, err)
+	router := gin.Default()
+	router.POST("/graphql", adaptersHttp.NewGraphQLHandler(schema).Query)
+
+	req, err := http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGraphQLHandler_Query(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetPoints", mock.Anything, "123").Return(28, nil)
+
+	w := serveGraphQL(t, mockService, `{"query": "query Points($id: ID!) { points(id: $id) }", "operationName": "Points", "variables": {"id": "123"}}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": {"points": 28}}`, w.Body.String())
+}
+
+func TestGraphQLHandler_ReportsErrorsInBody(t *testing.T) {
+	w := serveGraphQL(t, new(local_mocks.MockReceiptService), `{"query": "{ nonexistent }"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"errors"`)
+}
+
+func TestGraphQLHandler_InvalidBody(t *testing.T) {
+	w := serveGraphQL(t, new(local_mocks.MockReceiptService), `{"variables": {}}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
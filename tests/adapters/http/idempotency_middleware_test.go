@@ -0,0 +1,136 @@
+package http_test
+
+import (
+	"go-receipt-processor/internal/adapters/format"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/adapters/memory"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newIdempotentRouter serves POST /receipt/process behind the idempotency middleware with a fresh store
+func newIdempotentRouter(mockService *local_mocks.MockReceiptService) *gin.Engine {
+	router := gin.Default()
+	router.POST("/receipt/process",
+		adaptersHttp.IdempotencyMiddleware(memory.NewIdempotencyStore(), time.Hour),
+		adaptersHttp.NewReceiptProcessHandler(mockService).ProcessReceipt)
+	return router
+}
+
+func postWithKey(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	return postWithKeyAccepting(router, key, "", body)
+}
+
+// postWithKeyAccepting is postWithKey with an Accept header, unless accept is empty
+func postWithKeyAccepting(router *gin.Engine, key, accept, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/receipt/process", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(adaptersHttp.IdempotencyKeyHeader, key)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyMiddleware_ReplaysFirstResponse(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Return("12345", nil).Once()
+	router := newIdempotentRouter(mockService)
+
+	first := postWithKey(router, "key-1", validBatchReceipt)
+	// Whitespace differences do not make it a different request
+	retry := postWithKey(router, "key-1", strings.ReplaceAll(validBatchReceipt, "\n", " "))
+
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusOK, retry.Code)
+	assert.JSONEq(t, `{"id": "12345"}`, retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(adaptersHttp.IdempotentReplayHeader))
+	assert.Empty(t, first.Header().Get(adaptersHttp.IdempotentReplayHeader))
+	mockService.AssertNumberOfCalls(t, "ProcessReceipt", 1)
+}
+
+func TestIdempotencyMiddleware_RejectsKeyReuseWithDifferentBody(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Return("12345", nil)
+	router := newIdempotentRouter(mockService)
+
+	postWithKey(router, "key-1", validBatchReceipt)
+	w := postWithKey(router, "key-1", strings.Replace(validBatchReceipt, "Target", "Walmart", 1))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockService.AssertNumberOfCalls(t, "ProcessReceipt", 1)
+}
+
+func TestIdempotencyMiddleware_RejectsKeyReuseWithDifferentAccept(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Return("12345", nil)
+	router := newIdempotentRouter(mockService)
+
+	first := postWithKeyAccepting(router, "key-1", "application/json", validBatchReceipt)
+	assert.Equal(t, http.StatusOK, first.Code)
+
+	// Any Accept that allows the recorded JSON response gets it replayed
+	for _, accept := range []string{"", "*/*", "application/*", "application/x-protobuf;q=0.5, application/json"} {
+		w := postWithKeyAccepting(router, "key-1", accept, validBatchReceipt)
+		assert.Equal(t, http.StatusOK, w.Code, accept)
+		assert.Equal(t, "true", w.Header().Get(adaptersHttp.IdempotentReplayHeader), accept)
+	}
+
+	w := postWithKeyAccepting(router, "key-1", format.MIMEProtobuf, validBatchReceipt)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Empty(t, w.Header().Get(adaptersHttp.IdempotentReplayHeader))
+	mockService.AssertNumberOfCalls(t, "ProcessReceipt", 1)
+}
+
+func TestIdempotencyMiddleware_FailedRequestsAreNotRecorded(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Return("12345", nil)
+	router := newIdempotentRouter(mockService)
+
+	invalid := postWithKey(router, "key-1", `{"retailer": "Target"`)
+	assert.Equal(t, http.StatusBadRequest, invalid.Code)
+
+	// The key was released, so it can be used with a corrected body
+	w := postWithKey(router, "key-1", validBatchReceipt)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(adaptersHttp.IdempotentReplayHeader))
+}
+
+func TestIdempotencyMiddleware_PanickingRequestsAreNotRecorded(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Panic("store exploded").Once()
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Return("12345", nil).Once()
+	router := newIdempotentRouter(mockService)
+
+	// gin.Default recovers the panic as a 500
+	failed := postWithKey(router, "key-1", validBatchReceipt)
+	assert.Equal(t, http.StatusInternalServerError, failed.Code)
+
+	// The key was released rather than left in progress, so the retry is processed instead of answered 409
+	w := postWithKey(router, "key-1", validBatchReceipt)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(adaptersHttp.IdempotentReplayHeader))
+	mockService.AssertNumberOfCalls(t, "ProcessReceipt", 2)
+}
+
+func TestIdempotencyMiddleware_WithoutKey(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Return("12345", nil)
+	router := newIdempotentRouter(mockService)
+
+	postWithKey(router, "", validBatchReceipt)
+	postWithKey(router, "", validBatchReceipt)
+
+	mockService.AssertNumberOfCalls(t, "ProcessReceipt", 2)
+}
@@ -0,0 +1,127 @@
+package http_test
+
+import (
+	"fmt"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListReceiptsHandler_Success(t *testing.T) {
+	// Arrange: expect every query parameter to reach the service
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ListReceipts", mock.Anything, mock.MatchedBy(func(q repository.ReceiptQuery) bool {
+		return q.Retailer == "Target" &&
+			q.PurchaseDateFrom == "2024-01-01" && q.PurchaseDateTo == "2024-01-31" &&
+			*q.MinPoints == 10 && *q.MaxPoints == 100 &&
+			*q.MinTotal == 1.5 && q.MaxTotal == nil &&
+			q.SortBy == repository.SortByPoints && q.SortDescending &&
+			q.Limit == 5 && q.Cursor == "abc"
+	})).Return(repository.ReceiptPage{
+		Receipts: []domain.Receipt{{
+			ID: "r1", Retailer: "Target", PurchaseDate: "2024-01-02", PurchaseTime: "13:01", Total: "35.35", Points: 28,
+			Items: []domain.Item{{ShortDescription: "Pepsi", Price: "35.35"}},
+		}},
+		NextCursor: "next",
+	}, nil)
+
+	handler := adaptersHttp.NewListReceiptsHandler(mockService)
+	router := gin.Default()
+	router.GET("/receipts", handler.ListReceipts)
+
+	// Act
+	req, err := http.NewRequest("GET", "/receipts?retailer=Target&purchaseDateFrom=2024-01-01&purchaseDateTo=2024-01-31"+
+		"&minPoints=10&maxPoints=100&minTotal=1.5&sort=-points&limit=5&cursor=abc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	expectedResponse := `{"receipts":[{"id":"r1","retailer":"Target","purchaseDate":"2024-01-02","purchaseTime":"13:01",
+		"total":"35.35","points":28,"itemCount":1}],"nextCursor":"next"}`
+	assert.JSONEq(t, expectedResponse, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestListReceiptsHandler_DefaultLimit(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ListReceipts", mock.Anything, repository.ReceiptQuery{Limit: 20}).
+		Return(repository.ReceiptPage{}, nil)
+
+	handler := adaptersHttp.NewListReceiptsHandler(mockService)
+	router := gin.Default()
+	router.GET("/receipts", handler.ListReceipts)
+
+	req, err := http.NewRequest("GET", "/receipts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"receipts":[]}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestListReceiptsHandler_InvalidParameters(t *testing.T) {
+	tests := map[string]string{
+		"/receipts?purchaseDateFrom=01-01-2024": "purchaseDateFrom must be a date in YYYY-MM-DD format",
+		"/receipts?minPoints=lots":              "minPoints must be an integer",
+		"/receipts?maxTotal=ten":                "maxTotal must be a number",
+		"/receipts?sort=id":                     "sort must be one of purchaseDate, retailer, points or total",
+		"/receipts?limit=1000":                  "limit must be an integer between 1 and 100",
+	}
+
+	for url, details := range tests {
+		t.Run(url, func(t *testing.T) {
+			// The service is never reached when parameters are invalid
+			mockService := new(local_mocks.MockReceiptService)
+			handler := adaptersHttp.NewListReceiptsHandler(mockService)
+			router := gin.Default()
+			router.GET("/receipts", handler.ListReceipts)
+
+			req, err := http.NewRequest("GET", url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.JSONEq(t, fmt.Sprintf(`{"error":"Invalid query parameter","details":%q}`, details), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListReceiptsHandler_InvalidCursor(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ListReceipts", mock.Anything, mock.Anything).
+		Return(repository.ReceiptPage{}, fmt.Errorf("failed to list receipts: %w", repository.ErrInvalidCursor))
+
+	handler := adaptersHttp.NewListReceiptsHandler(mockService)
+	router := gin.Default()
+	router.GET("/receipts", handler.ListReceipts)
+
+	req, err := http.NewRequest("GET", "/receipts?cursor=stale", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
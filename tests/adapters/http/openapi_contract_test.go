@@ -0,0 +1,206 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-receipt-processor/api"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/adapters/memory"
+	"go-receipt-processor/internal/application"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/tests/local_mocks"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// contractServer routes the documented paths to the real handlers on service, behind the validation middleware
+func contractServer(service internalHttp.ReceiptService) *gin.Engine {
+	doc, err := api.LoadOpenAPI()
+	if err != nil {
+		panic(err)
+	}
+	validator, err := api.NewValidator(doc)
+	if err != nil {
+		panic(err)
+	}
+	router := gin.Default()
+	router.Use(adaptersHttp.OpenAPIValidationMiddleware(validator, "", adaptersHttp.WriteValidationError))
+	router.POST("/receipt/process", adaptersHttp.NewReceiptProcessHandler(service).ProcessReceipt)
+	router.GET("/receipt/:id/points", adaptersHttp.NewGetReceiptPointsHandler(service).GetPoints)
+	return router
+}
+
+// contractRouter finds the operation of a request in the OpenAPI document
+func contractRouter(t *testing.T) routers.Router {
+	doc, err := api.LoadOpenAPI()
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+	return router
+}
+
+// serveAndValidate sends a request to server and checks both the request and the response against the OpenAPI document
+func serveAndValidate(t *testing.T, router routers.Router, server *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "http://localhost:8080"+path, bytes.NewBufferString(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	route, pathParams, err := router.FindRoute(req)
+	require.NoError(t, err, "%s %s is not documented", method, path)
+	requestInput := &openapi3filter.RequestValidationInput{Request: req, PathParams: pathParams, Route: route}
+	require.NoError(t, openapi3filter.ValidateRequest(context.Background(), requestInput))
+
+	// Validating the request consumed its body, so send a fresh copy
+	served := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	served.Header = req.Header
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, served)
+
+	err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: requestInput,
+		Status:                 w.Code,
+		Header:                 w.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(w.Body.Bytes())),
+	})
+	assert.NoError(t, err, "%s %s answered %d %s", method, path, w.Code, w.Body.String())
+	return w
+}
+
+const contractReceipt = `{
+	"retailer": "Contract Corner Market",
+	"purchaseDate": "2022-03-20",
+	"purchaseTime": "14:33",
+	"items": [
+		{"shortDescription": "Gatorade", "price": "2.25"},
+		{"shortDescription": "Gatorade", "price": "2.25"}
+	],
+	"total": "4.50"
+}`
+
+func TestOpenAPIContract_ProcessThenGetPoints(t *testing.T) {
+	router := contractRouter(t)
+	service := application.NewReceiptService(
+		application.NewPointsCalculator(application.NewPointsCalculatorHelper()),
+		memory.NewReceiptStore(),
+	)
+	server := contractServer(service)
+
+	w := serveAndValidate(t, router, server, http.MethodPost, "/receipt/process", contractReceipt)
+	require.Equal(t, http.StatusOK, w.Code)
+	id := decodeID(t, w)
+
+	w = serveAndValidate(t, router, server, http.MethodGet, "/receipt/"+id+"/points", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = serveAndValidate(t, router, server, http.MethodGet, "/receipt/does-not-exist/points", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOpenAPIContract_ErrorResponses(t *testing.T) {
+	router := contractRouter(t)
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).
+		Return("", fmt.Errorf("unable to process receipt: %w", &internalHttp.DuplicateReceiptError{OriginalID: "original"})).Once()
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Return("", errors.New("failed to insert receipt: connection reset")).Once()
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Return("", context.DeadlineExceeded).Once()
+	mockService.On("GetPoints", mock.Anything, "deleted").Return(0, repository.ErrReceiptDeleted)
+	mockService.On("GetPoints", mock.Anything, "queued").Return(0, &internalHttp.ReceiptPendingError{Status: domain.JobStatusPending})
+	server := contractServer(mockService)
+
+	for _, status := range []int{http.StatusConflict, http.StatusInternalServerError, http.StatusGatewayTimeout} {
+		w := serveAndValidate(t, router, server, http.MethodPost, "/receipt/process", contractReceipt)
+		assert.Equal(t, status, w.Code)
+	}
+
+	w := serveAndValidate(t, router, server, http.MethodGet, "/receipt/deleted/points", "")
+	assert.Equal(t, http.StatusGone, w.Code)
+	w = serveAndValidate(t, router, server, http.MethodGet, "/receipt/queued/points", "")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+}
+
+func TestOpenAPIContract_AsyncProcessing(t *testing.T) {
+	router := contractRouter(t)
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("EnqueueReceipt", mock.Anything, mock.Anything).Return(domain.ReceiptJob{ID: "job-1", Status: domain.JobStatusPending}, nil)
+	server := gin.Default()
+	server.POST("/receipt/process", adaptersHttp.NewReceiptProcessHandler(mockService).EnqueueReceipt)
+
+	w := serveAndValidate(t, router, server, http.MethodPost, "/receipt/process", contractReceipt)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+}
+
+func TestOpenAPIContract_InvalidReceiptBreaksContract(t *testing.T) {
+	doc, err := api.LoadOpenAPI()
+	require.NoError(t, err)
+	schema := doc.Components.Schemas["Receipt"].Value
+
+	assert.NoError(t, schema.VisitJSON(mustJSON(t, contractReceipt)))
+	for name, receipt := range map[string]string{
+		"retailer":    `{"retailer": "Shop!", "purchaseDate": "2022-03-20", "purchaseTime": "14:33", "items": [{"shortDescription": "Gatorade", "price": "2.25"}], "total": "2.25"}`,
+		"total":       `{"retailer": "Shop", "purchaseDate": "2022-03-20", "purchaseTime": "14:33", "items": [{"shortDescription": "Gatorade", "price": "2.25"}], "total": "2.2"}`,
+		"price":       `{"retailer": "Shop", "purchaseDate": "2022-03-20", "purchaseTime": "14:33", "items": [{"shortDescription": "Gatorade", "price": "$2.25"}], "total": "2.25"}`,
+		"no items":    `{"retailer": "Shop", "purchaseDate": "2022-03-20", "purchaseTime": "14:33", "items": [], "total": "2.25"}`,
+		"time":        `{"retailer": "Shop", "purchaseDate": "2022-03-20", "purchaseTime": "2:33pm", "items": [{"shortDescription": "Gatorade", "price": "2.25"}], "total": "2.25"}`,
+		"description": `{"retailer": "Shop", "purchaseDate": "2022-03-20", "purchaseTime": "14:33", "items": [{"shortDescription": "Gatorade!", "price": "2.25"}], "total": "2.25"}`,
+	} {
+		assert.Error(t, schema.VisitJSON(mustJSON(t, receipt)), name)
+	}
+}
+
+func TestOpenAPIHandler_ServesDocumentAndDocs(t *testing.T) {
+	doc, err := api.LoadOpenAPI()
+	require.NoError(t, err)
+	handler, err := adaptersHttp.NewOpenAPIHandler(doc)
+	require.NoError(t, err)
+	router := gin.Default()
+	router.GET("/openapi.json", handler.Spec)
+	router.GET("/docs", handler.Docs)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	served, err := openapi3.NewLoader().LoadFromData(w.Body.Bytes())
+	require.NoError(t, err)
+	assert.NotNil(t, served.Paths.Find("/receipt/process"))
+	assert.NotNil(t, served.Paths.Find("/receipt/{id}/points"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), "/openapi.json")
+}
+
+// decodeID returns the "id" field of a JSON response
+func decodeID(t *testing.T, w *httptest.ResponseRecorder) string {
+	var body struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body.ID
+}
+
+// mustJSON decodes a JSON document into the generic form schemas are validated against
+func mustJSON(t *testing.T, document string) interface{} {
+	var value interface{}
+	require.NoError(t, json.Unmarshal([]byte(document), &value))
+	return value
+}
@@ -0,0 +1,144 @@
+package http_test
+
+import (
+	"encoding/json"
+	"go-receipt-processor/api"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// validationResponse is the body of a request rejected by the validation middleware
+type validationResponse struct {
+	Error   string           `json:"error"`
+	Details string           `json:"details"`
+	Fields  []api.FieldError `json:"fields"`
+}
+
+// newValidatedRouter serves the receipt routes behind the validation middleware built from the embedded document
+func newValidatedRouter(t *testing.T, mockService *local_mocks.MockReceiptService) *gin.Engine {
+	doc, err := api.LoadOpenAPI()
+	require.NoError(t, err)
+	validator, err := api.NewValidator(doc)
+	require.NoError(t, err)
+
+	router := gin.Default()
+	router.Use(adaptersHttp.OpenAPIValidationMiddleware(validator, "", adaptersHttp.WriteValidationError))
+	router.POST("/receipt/process", adaptersHttp.NewReceiptProcessHandler(mockService).ProcessReceipt)
+	router.GET("/receipt/:id/points", adaptersHttp.NewGetReceiptPointsHandler(mockService).GetPoints)
+	router.PUT("/receipt/:id", adaptersHttp.NewUpdateReceiptHandler(mockService).PutReceipt)
+	return router
+}
+
+func serveValidated(router *gin.Engine, method, path, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOpenAPIValidationMiddleware_ReportsFieldPaths(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	router := newValidatedRouter(t, mockService)
+
+	w := serveValidated(router, "POST", "/receipt/process", "application/json", `{
+		"retailer": "Target!",
+		"purchaseDate": "2022-01-01",
+		"items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.5"}, {"price": "1.00"}],
+		"total": "6.49"
+	}`)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var body validationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Invalid request payload", body.Error)
+
+	fields := make(map[string]string, len(body.Fields))
+	for _, field := range body.Fields {
+		fields[field.Field] = field.Message
+	}
+	assert.Len(t, fields, 4, body.Fields)
+	assert.Contains(t, fields["retailer"], "regular expression")
+	assert.Contains(t, fields["purchaseTime"], "missing")
+	assert.Contains(t, fields["items[0].price"], "regular expression")
+	assert.Contains(t, fields["items[1].shortDescription"], "missing")
+	assert.Contains(t, body.Details, "items[0].price: ")
+	mockService.AssertNotCalled(t, "ProcessReceipt", mock.Anything, mock.Anything)
+}
+
+func TestOpenAPIValidationMiddleware_RejectsEmptyItems(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	router := newValidatedRouter(t, mockService)
+
+	w := serveValidated(router, "POST", "/receipt/process", "application/json",
+		strings.Replace(validBatchReceipt, `[{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}]`, `[]`, 1))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var body validationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Fields, 1)
+	assert.Equal(t, "items", body.Fields[0].Field)
+}
+
+func TestOpenAPIValidationMiddleware_PassesValidRequests(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool {
+		// The handler still sees the whole body after the middleware has read it
+		return r.Retailer == "Target" && len(r.Items) == 1
+	})).Return("12345", nil).Times(3)
+	router := newValidatedRouter(t, mockService)
+
+	w := serveValidated(router, "POST", "/receipt/process", "application/json", validBatchReceipt)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Like ShouldBindJSON, bodies without a Content-Type or with the form type curl -d sends are read as JSON
+	for _, contentType := range []string{"", "application/x-www-form-urlencoded"} {
+		w = serveValidated(router, "POST", "/receipt/process", contentType, validBatchReceipt)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestOpenAPIValidationMiddleware_ValidatesPathParameters(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	router := newValidatedRouter(t, mockService)
+
+	w := serveValidated(router, "GET", "/receipt/%20/points", "", "")
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var body validationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Fields, 1)
+	assert.Equal(t, "id", body.Fields[0].Field)
+	mockService.AssertNotCalled(t, "GetPoints", mock.Anything, mock.Anything)
+}
+
+func TestOpenAPIValidationMiddleware_SkipsUndocumentedRoutes(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	router := newValidatedRouter(t, mockService)
+
+	// PUT /receipt/:id is not in the document, so the handler validates the body against the Receipt schema itself
+	w := serveValidated(router, "PUT", "/receipt/abc", "application/json", `{"retailer": "Target"}`)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var body validationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	fields := make([]string, len(body.Fields))
+	for i, field := range body.Fields {
+		fields[i] = field.Field
+	}
+	assert.ElementsMatch(t, []string{"purchaseDate", "purchaseTime", "items", "total"}, fields)
+	mockService.AssertNotCalled(t, "GetReceipt", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,81 @@
+package http_test
+
+import (
+	"fmt"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReadinessHandler_Ready(t *testing.T) {
+	// Arrange
+	mockChecker := new(local_mocks.MockHealthChecker)
+	mockChecker.On("CheckHealth", mock.Anything).Return(map[string]int64{"idleConns": 2}, nil)
+
+	handler := adaptersHttp.NewReadinessHandler(map[string]repository.HealthChecker{"receiptStore": mockChecker})
+	router := gin.Default()
+	router.GET("/health/ready", handler.Ready)
+
+	// Act
+	req, err := http.NewRequest("GET", "/health/ready", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	expectedResponse := `{"status":"ready","checks":{"receiptStore":{"healthy":true,"stats":{"idleConns":2}}}}`
+	assert.JSONEq(t, expectedResponse, w.Body.String())
+
+	mockChecker.AssertExpectations(t)
+}
+
+func TestReadinessHandler_Unavailable(t *testing.T) {
+	// Arrange
+	mockChecker := new(local_mocks.MockHealthChecker)
+	mockChecker.On("CheckHealth", mock.Anything).Return(nil, fmt.Errorf("connection refused"))
+
+	handler := adaptersHttp.NewReadinessHandler(map[string]repository.HealthChecker{"receiptStore": mockChecker})
+	router := gin.Default()
+	router.GET("/health/ready", handler.Ready)
+
+	// Act
+	req, err := http.NewRequest("GET", "/health/ready", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	expectedResponse := `{"status":"unavailable","checks":{"receiptStore":{"healthy":false,"error":"connection refused"}}}`
+	assert.JSONEq(t, expectedResponse, w.Body.String())
+
+	mockChecker.AssertExpectations(t)
+}
+
+func TestReadinessHandler_NoDependencies(t *testing.T) {
+	handler := adaptersHttp.NewReadinessHandler(map[string]repository.HealthChecker{})
+	router := gin.Default()
+	router.GET("/health/ready", handler.Ready)
+
+	req, err := http.NewRequest("GET", "/health/ready", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ready"}`, w.Body.String())
+}
@@ -0,0 +1,139 @@
+package http_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/adapters/memory"
+	"go-receipt-processor/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sseEvent is one event read from a text/event-stream body
+type sseEvent struct {
+	id, event, data string
+}
+
+// openEventStream starts a server for a ReceiptEventsHandler on stream and opens GET /receipts/stream
+func openEventStream(t *testing.T, stream *memory.EventStreamImpl, query string, lastEventID string) (*http.Response, *bufio.Reader) {
+	router := gin.Default()
+	router.GET("/receipts/stream", adaptersHttp.NewReceiptEventsHandler(stream, 0).StreamReceipts)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/receipts/stream"+query, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewReader(resp.Body)
+}
+
+// readEvent reads the next event from a server-sent events body, skipping comments
+func readEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	lines := make(chan sseEvent, 1)
+	go func() {
+		var event sseEvent
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "" && event.id != "":
+				lines <- event
+				return
+			case strings.HasPrefix(line, "id: "):
+				event.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				event.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				event.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+
+	select {
+	case event := <-lines:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+		return sseEvent{}
+	}
+}
+
+// scored builds a receipt.scored event
+func scored(id, retailer string, points int) domain.ReceiptEvent {
+	return domain.ReceiptEvent{
+		ID:         id,
+		Type:       domain.EventReceiptScored,
+		ReceiptID:  "receipt-" + id,
+		Retailer:   retailer,
+		Points:     points,
+		OccurredAt: time.Date(2024, 11, 29, 14, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestReceiptEventsHandler_StreamsScoredReceipts(t *testing.T) {
+	stream := memory.NewEventStream(10)
+	resp, reader := openEventStream(t, stream, "", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, adaptersHttp.EventStreamContentType, resp.Header.Get("Content-Type"))
+
+	stream.Publish(domain.ReceiptEvent{ID: "deleted", Type: domain.EventReceiptDeleted, ReceiptID: "receipt-0"})
+	stream.Publish(scored("1", "Target", 28))
+
+	event := readEvent(t, reader)
+	assert.Equal(t, "1", event.id)
+	assert.Equal(t, domain.EventReceiptScored, event.event)
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(event.data), &data))
+	assert.Equal(t, map[string]interface{}{
+		"receiptId": "receipt-1",
+		"retailer":  "Target",
+		"points":    float64(28),
+		"timestamp": "2024-11-29T14:30:00Z",
+	}, data)
+}
+
+func TestReceiptEventsHandler_FiltersByRetailer(t *testing.T) {
+	stream := memory.NewEventStream(10)
+	_, reader := openEventStream(t, stream, "?retailer=target&retailer=%20Walgreens", "")
+
+	stream.Publish(scored("1", "M&M Corner Market", 109))
+	stream.Publish(scored("2", "Target", 28))
+	// Retailers are compared as the indexes compare them, whitespace and all
+	stream.Publish(scored("3", "Walgreens ", 15))
+
+	assert.Equal(t, "2", readEvent(t, reader).id)
+	assert.Equal(t, "3", readEvent(t, reader).id)
+}
+
+func TestReceiptEventsHandler_ResumesFromLastEventID(t *testing.T) {
+	stream := memory.NewEventStream(10)
+	stream.Publish(scored("1", "Target", 28))
+	stream.Publish(scored("2", "Target", 30))
+	stream.Publish(scored("3", "Target", 32))
+
+	_, reader := openEventStream(t, stream, "", "1")
+	stream.Publish(scored("4", "Target", 34))
+
+	assert.Equal(t, "2", readEvent(t, reader).id)
+	assert.Equal(t, "3", readEvent(t, reader).id)
+	assert.Equal(t, "4", readEvent(t, reader).id)
+}
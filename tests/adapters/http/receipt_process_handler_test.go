@@ -1,9 +1,12 @@
 package http_test
 
 import (
+	"encoding/json"
 	"fmt"
 	adaptersHttp "go-receipt-processor/internal/adapters/http"
 	"go-receipt-processor/internal/domain"
+	portsHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/repository"
 	"go-receipt-processor/tests/local_mocks"
 	"net/http"
 	"net/http/httptest"
@@ -18,7 +21,7 @@ import (
 func TestProcessReceipt_Success(t *testing.T) {
 	// Arrange: Create a mock service and set expectations for a successful response
 	mockService := new(local_mocks.MockReceiptService)
-	mockService.On("ProcessReceipt", mock.Anything).Return("receipt123", nil) // Mock successful receipt processing
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Return("receipt123", nil) // Mock successful receipt processing
 
 	// Create the handler and the router
 	handler := adaptersHttp.NewReceiptProcessHandler(mockService)
@@ -97,10 +100,37 @@ func TestProcessReceipt_InvalidJSON(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestProcessReceipt_SchemaInvalidWithoutValidationMiddleware(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	handler := adaptersHttp.NewReceiptProcessHandler(mockService)
+	router := gin.Default()
+	router.POST("/receipt/process", handler.ProcessReceipt)
+
+	req := httptest.NewRequest("POST", "/receipt/process", strings.NewReader(`{"retailer": "Target", "purchaseTime": "25:00"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var body struct {
+		Error  string `json:"error"`
+		Fields []struct {
+			Field string `json:"field"`
+		} `json:"fields"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Invalid request payload", body.Error)
+	var fields []string
+	for _, field := range body.Fields {
+		fields = append(fields, field.Field)
+	}
+	assert.Contains(t, fields, "purchaseTime")
+	mockService.AssertNotCalled(t, "ProcessReceipt", mock.Anything, mock.Anything)
+}
+
 func TestProcessReceipt_ServiceError(t *testing.T) {
 	// Arrange: Create a mock service and set expectations for an error case
 	mockService := new(local_mocks.MockReceiptService)
-	mockService.On("ProcessReceipt", mock.Anything).Return("", fmt.Errorf("processing failed")) // Mock a failure
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Return("", fmt.Errorf("processing failed")) // Mock a failure
 
 	// Create the handler and the router
 	handler := adaptersHttp.NewReceiptProcessHandler(mockService)
@@ -150,3 +180,73 @@ func TestProcessReceipt_ServiceError(t *testing.T) {
 	// Verify that the mock service method was called as expected
 	mockService.AssertExpectations(t)
 }
+
+func TestProcessReceipt_DuplicateRejected(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).
+		Return("", fmt.Errorf("unable to process receipt: %w", &portsHttp.DuplicateReceiptError{OriginalID: "original-id"}))
+
+	handler := adaptersHttp.NewReceiptProcessHandler(mockService)
+	router := gin.Default()
+	router.POST("/receipt/process", handler.ProcessReceipt)
+
+	req, err := http.NewRequest("POST", "/receipt/process", strings.NewReader(validBatchReceipt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error":"unable to process receipt: duplicate of receipt original-id","originalId":"original-id"}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+// serveEnqueue routes a single request to the EnqueueReceipt handler backed by mockService
+func serveEnqueue(t *testing.T, mockService *local_mocks.MockReceiptService, body string) *httptest.ResponseRecorder {
+	handler := adaptersHttp.NewReceiptProcessHandler(mockService)
+	router := gin.Default()
+	router.POST("/receipt/process", handler.EnqueueReceipt)
+
+	req, err := http.NewRequest("POST", "/receipt/process", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestEnqueueReceipt_Accepted(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("EnqueueReceipt", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool {
+		return r.Retailer == "Target"
+	})).Return(domain.ReceiptJob{ID: "job-1", Status: domain.JobStatusPending}, nil)
+
+	w := serveEnqueue(t, mockService, validBatchReceipt)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"id":"job-1","status":"pending"}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestEnqueueReceipt_QueueFull(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("EnqueueReceipt", mock.Anything, mock.Anything).
+		Return(domain.ReceiptJob{}, fmt.Errorf("unable to enqueue receipt: %w", repository.ErrQueueFull))
+
+	w := serveEnqueue(t, mockService, validBatchReceipt)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":"unable to enqueue receipt: receipt queue is full"}`, w.Body.String())
+}
+
+func TestEnqueueReceipt_InvalidPayload(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+
+	w := serveEnqueue(t, mockService, `{"retailer": "Target"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "EnqueueReceipt", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,169 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-receipt-processor/api"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newV2Router serves the v2 receipt routes under /v2, as the server does, behind validation with the v2 error envelope
+func newV2Router(t *testing.T, mockService *local_mocks.MockReceiptService, calculator *local_mocks.MockPointsCalculator) *gin.Engine {
+	doc, err := api.LoadOpenAPI()
+	require.NoError(t, err)
+	validator, err := api.NewValidator(doc)
+	require.NoError(t, err)
+
+	handler := adaptersHttp.NewReceiptV2Handler(mockService, calculator)
+	router := gin.Default()
+	v2 := router.Group("/v2", adaptersHttp.RequestContextMiddleware(0),
+		adaptersHttp.OpenAPIValidationMiddleware(validator, "/v2", adaptersHttp.WriteV2ValidationError))
+	v2.POST("/receipt/process", handler.ProcessReceipt)
+	v2.GET("/receipt/:id/points", handler.GetPoints)
+	return router
+}
+
+var v2Receipt = domain.Receipt{
+	ID:             "abc",
+	Retailer:       "Target",
+	Points:         31,
+	ReceivedAt:     time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+	ScoringVersion: "v1",
+	Revision:       1,
+	Fingerprint:    "f1",
+}
+
+var v2Breakdown = []domain.RulePoints{{Rule: domain.RuleRetailerName, Points: 6}, {Rule: domain.RuleItemCount, Points: 25}}
+
+func TestReceiptV2Handler_ProcessReceipt(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Return("abc", nil)
+	mockService.On("GetReceipt", mock.Anything, "abc").Return(v2Receipt, nil)
+	calculator := new(local_mocks.MockPointsCalculator)
+	calculator.On("Breakdown", mock.Anything, v2Receipt).Return(v2Breakdown, nil)
+
+	w := serveValidated(newV2Router(t, mockService, calculator), "POST", "/v2/receipt/process", "application/json", validBatchReceipt)
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "/v2/receipt/abc/points", w.Header().Get("Location"))
+	assert.JSONEq(t, `{
+		"id": "abc",
+		"points": 31,
+		"breakdown": [{"rule": "retailerName", "points": 6}, {"rule": "itemCount", "points": 25}],
+		"metadata": {"receivedAt": "2026-10-19T12:00:00Z", "scoringVersion": "v1", "revision": 1, "fingerprint": "f1"}
+	}`, w.Body.String())
+}
+
+func TestReceiptV2Handler_ErrorEnvelope(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).Return("", &internalHttp.DuplicateReceiptError{OriginalID: "first"})
+	mockService.On("GetPoints", mock.Anything, "missing").Return(0, fmt.Errorf("failed to find receipt: %w", repository.ErrReceiptNotFound))
+	router := newV2Router(t, mockService, new(local_mocks.MockPointsCalculator))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"invalid receipt", "POST", "/v2/receipt/process", `{"retailer": "Target"}`, http.StatusBadRequest, "invalid_request"},
+		{"malformed json", "POST", "/v2/receipt/process", `{"retailer": `, http.StatusBadRequest, "invalid_request"},
+		{"duplicate", "POST", "/v2/receipt/process", validBatchReceipt, http.StatusConflict, "duplicate_receipt"},
+		{"not found", "GET", "/v2/receipt/missing/points", "", http.StatusNotFound, "not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(adaptersHttp.RequestIDHeader, "req-1")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			var body response.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantCode, body.Error.Code)
+			assert.NotEmpty(t, body.Error.Message)
+			assert.Equal(t, "req-1", body.Error.RequestID)
+		})
+	}
+
+	// Validation failures and duplicates carry their details in the envelope
+	w := serveValidated(router, "POST", "/v2/receipt/process", "application/json", `{"retailer": "Target"}`)
+	var invalid response.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &invalid))
+	assert.Len(t, invalid.Error.Fields, 4)
+
+	w = serveValidated(router, "POST", "/v2/receipt/process", "application/json", validBatchReceipt)
+	var duplicate response.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &duplicate))
+	assert.Equal(t, "first", duplicate.Error.OriginalID)
+}
+
+func TestReceiptV2Handler_ValidatesWithoutMiddleware(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	handler := adaptersHttp.NewReceiptV2Handler(mockService, new(local_mocks.MockPointsCalculator))
+	router := gin.Default()
+	router.POST("/v2/receipt/process", handler.ProcessReceipt)
+
+	w := serveValidated(router, "POST", "/v2/receipt/process", "application/json", `{"retailer": "Target"}`)
+
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	var body response.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "invalid_request", body.Error.Code)
+	assert.Len(t, body.Error.Fields, 4)
+	mockService.AssertNotCalled(t, "ProcessReceipt", mock.Anything, mock.Anything)
+}
+
+func TestReceiptV2Handler_GetPoints(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetPoints", mock.Anything, "abc").Return(31, nil)
+	mockService.On("GetReceipt", mock.Anything, "abc").Return(v2Receipt, nil)
+	mockService.On("GetPoints", mock.Anything, "queued").Return(0, &internalHttp.ReceiptPendingError{Status: domain.JobStatusPending})
+	calculator := new(local_mocks.MockPointsCalculator)
+	calculator.On("Breakdown", mock.Anything, v2Receipt).Return(v2Breakdown, nil)
+	router := newV2Router(t, mockService, calculator)
+
+	w := serveValidated(router, "GET", "/v2/receipt/abc/points", "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body response.ReceiptV2Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 31, body.Points)
+	assert.Len(t, body.Breakdown, 2)
+	assert.Equal(t, "v1", body.Metadata.ScoringVersion)
+
+	w = serveValidated(router, "GET", "/v2/receipt/queued/points", "", "")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"id": "queued", "status": "pending"}`, w.Body.String())
+}
+
+func TestDeprecationMiddleware(t *testing.T) {
+	router := gin.Default()
+	deprecatedAt := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC)
+	router.GET("/receipts", adaptersHttp.DeprecationMiddleware(deprecatedAt, sunset), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	w := serveValidated(router, "GET", "/receipts", "", "")
+
+	assert.Equal(t, "@1792368000", w.Header().Get(adaptersHttp.DeprecationHeader))
+	assert.Equal(t, "Fri, 30 Apr 2027 00:00:00 GMT", w.Header().Get(adaptersHttp.SunsetHeader))
+}
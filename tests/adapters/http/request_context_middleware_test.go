@@ -0,0 +1,104 @@
+package http_test
+
+import (
+	"context"
+	"fmt"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/pkg/utils"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestContextMiddleware_GeneratesRequestID(t *testing.T) {
+	var seenRequestID string
+	router := gin.Default()
+	router.Use(adaptersHttp.RequestContextMiddleware(0))
+	router.GET("/ping", func(c *gin.Context) {
+		seenRequestID = utils.RequestIDFrom(c.Request.Context())
+	})
+
+	req, err := http.NewRequest("GET", "/ping", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// A request ID is generated, stored in the context and echoed to the client
+	assert.NotEmpty(t, seenRequestID)
+	assert.Equal(t, seenRequestID, w.Header().Get(adaptersHttp.RequestIDHeader))
+}
+
+func TestRequestContextMiddleware_PropagatesHeaders(t *testing.T) {
+	var seenRequestID, seenTenantID string
+	router := gin.Default()
+	router.Use(adaptersHttp.RequestContextMiddleware(0))
+	router.GET("/ping", func(c *gin.Context) {
+		seenRequestID = utils.RequestIDFrom(c.Request.Context())
+		seenTenantID = utils.TenantIDFrom(c.Request.Context())
+	})
+
+	req, err := http.NewRequest("GET", "/ping", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(adaptersHttp.RequestIDHeader, "req-42")
+	req.Header.Set(adaptersHttp.TenantIDHeader, "tenant-a")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "req-42", seenRequestID)
+	assert.Equal(t, "tenant-a", seenTenantID)
+	assert.Equal(t, "req-42", w.Header().Get(adaptersHttp.RequestIDHeader))
+}
+
+func TestRequestContextMiddleware_AppliesDeadline(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	router := gin.Default()
+	router.Use(adaptersHttp.RequestContextMiddleware(time.Minute))
+	router.GET("/ping", func(c *gin.Context) {
+		deadline, hasDeadline = c.Request.Context().Deadline()
+	})
+
+	req, err := http.NewRequest("GET", "/ping", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.True(t, hasDeadline)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+}
+
+func TestGetPointsHandler_DeadlineExceeded(t *testing.T) {
+	// Arrange: the service gives up because the request's deadline passed
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetPoints", mock.Anything, "123").
+		Return(0, fmt.Errorf("failed to find receipt: %w", context.DeadlineExceeded))
+
+	handler := adaptersHttp.NewGetReceiptPointsHandler(mockService)
+	router := gin.Default()
+	router.Use(adaptersHttp.RequestContextMiddleware(time.Millisecond))
+	router.GET("/receipts/:id/points", handler.GetPoints)
+
+	// Act
+	req, err := http.NewRequest("GET", "/receipts/123/points", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.JSONEq(t, `{"error":"failed to find receipt: context deadline exceeded"}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
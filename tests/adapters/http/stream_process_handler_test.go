@@ -0,0 +1,122 @@
+package http_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/tests/local_mocks"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newStreamRouter(mockService *local_mocks.MockReceiptService) *gin.Engine {
+	handler := adaptersHttp.NewStreamProcessHandler(mockService, 0)
+	router := gin.Default()
+	router.POST("/receipts/stream", handler.ProcessStream)
+	return router
+}
+
+// decodeStreamResults parses every NDJSON line of a streamed response
+func decodeStreamResults(t *testing.T, body io.Reader) []response.StreamLineResult {
+	var results []response.StreamLineResult
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		var result response.StreamLineResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &result))
+		results = append(results, result)
+	}
+	require.NoError(t, scanner.Err())
+	return results
+}
+
+func TestProcessStream_ReportsEachLine(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.AnythingOfType("domain.Receipt")).Return("id-1", nil).Once()
+	mockService.On("ProcessReceipt", mock.Anything, mock.AnythingOfType("domain.Receipt")).Return("id-3", nil).Once()
+
+	oneLine := strings.ReplaceAll(validBatchReceipt, "\n", " ")
+	body := oneLine + "\n" + `{"retailer": "Target"}` + "\n\n" + oneLine
+
+	req, err := http.NewRequest("POST", "/receipts/stream", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", adaptersHttp.NDJSONContentType)
+	w := httptest.NewRecorder()
+	newStreamRouter(mockService).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, adaptersHttp.NDJSONContentType, w.Header().Get("Content-Type"))
+
+	results := decodeStreamResults(t, w.Body)
+	require.Len(t, results, 3)
+	assert.Equal(t, response.StreamLineResult{Line: 1, ID: "id-1"}, results[0])
+	assert.Equal(t, 2, results[1].Line)
+	assert.Equal(t, http.StatusBadRequest, results[1].Error.Status)
+	// The blank third line is skipped but still counted
+	assert.Equal(t, response.StreamLineResult{Line: 4, ID: "id-3"}, results[2])
+}
+
+func TestProcessStream_RequiresNDJSON(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+
+	req, err := http.NewRequest("POST", "/receipts/stream", strings.NewReader(`[]`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	newStreamRouter(mockService).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
+
+func TestProcessStream_RespondsBeforeUploadFinishes(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.AnythingOfType("domain.Receipt")).Return("streamed-id", nil)
+
+	server := httptest.NewServer(newStreamRouter(mockService))
+	defer server.Close()
+
+	upload, uploadWriter := io.Pipe()
+	req, err := http.NewRequest("POST", server.URL+"/receipts/stream", upload)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", adaptersHttp.NDJSONContentType)
+
+	responses := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			uploadWriter.CloseWithError(err)
+			return
+		}
+		responses <- resp
+	}()
+
+	receipt, err := json.Marshal(domain.Receipt{
+		Retailer: "Target", PurchaseDate: "2022-01-01", PurchaseTime: "13:01",
+		Items: []domain.Item{{ShortDescription: "Pepsi", Price: "1.25"}}, Total: "1.25",
+	})
+	require.NoError(t, err)
+	_, err = fmt.Fprintf(uploadWriter, "%s\n", receipt)
+	require.NoError(t, err)
+
+	// The first result arrives while the upload is still open
+	resp := <-responses
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadBytes('\n')
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"line": 1, "id": "streamed-id"}`, string(line))
+
+	require.NoError(t, uploadWriter.Close())
+	_, err = reader.ReadBytes('\n')
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,118 @@
+package http_test
+
+import (
+	"encoding/json"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/domain"
+	internalHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/http/response"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const printedReceipt = `TARGET
+1234 Main St
+01/13/2022 1:01 PM
+
+Mountain Dew 12PK      6.49
+Emils Cheese Pizza    12.25
+
+SUBTOTAL              18.74
+TOTAL                 18.74
+VISA                  18.74
+`
+
+// serveText posts text to a TextReceiptHandler backed by mockService
+func serveText(mockService *local_mocks.MockReceiptService, text string) (*httptest.ResponseRecorder, response.ParsedReceiptResponse) {
+	router := gin.Default()
+	router.POST("/receipts/text", adaptersHttp.NewTextReceiptHandler(mockService).ProcessText)
+
+	req := httptest.NewRequest("POST", "/receipts/text", strings.NewReader(text))
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var body response.ParsedReceiptResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return w, body
+}
+
+func TestProcessText_ScoresTheExtractedReceipt(t *testing.T) {
+	expected := domain.Receipt{
+		Retailer:     "TARGET",
+		PurchaseDate: "2022-01-13",
+		PurchaseTime: "13:01",
+		Items: []domain.Item{
+			{ShortDescription: "Mountain Dew 12PK", Price: "6.49"},
+			{ShortDescription: "Emils Cheese Pizza", Price: "12.25"},
+		},
+		Total: "18.74",
+	}
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, expected).Return("12345", nil).Once()
+
+	w, body := serveText(mockService, printedReceipt)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "12345", body.ID)
+	assert.Equal(t, "TARGET", body.Receipt.Retailer)
+	assert.Len(t, body.Receipt.Items, 2)
+	assert.Equal(t, map[string]float64{
+		"retailer": 0.9, "purchaseDate": 0.9, "purchaseTime": 0.95, "items": 0.95, "total": 0.95,
+	}, body.Confidence)
+	mockService.AssertExpectations(t)
+}
+
+func TestProcessText_ReportsFieldsThatCouldNotBeExtracted(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+
+	w, body := serveText(mockService, "Corner Deli\nSandwich 7.95\n")
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	assert.Empty(t, body.ID)
+	assert.Equal(t, "Corner Deli", body.Receipt.Retailer)
+	assert.Equal(t, 0.0, body.Confidence["purchaseDate"])
+	var fields []string
+	for _, field := range body.Fields {
+		fields = append(fields, field.Field)
+	}
+	assert.ElementsMatch(t, []string{"purchaseDate", "purchaseTime"}, fields)
+	mockService.AssertNotCalled(t, "ProcessReceipt", mock.Anything, mock.Anything)
+}
+
+func TestProcessText_RejectsEmptyBodies(t *testing.T) {
+	w, _ := serveText(new(local_mocks.MockReceiptService), " \n ")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "receipt text is empty")
+}
+
+func TestProcessText_RejectsBodiesOverOneMiB(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+
+	w, _ := serveText(mockService, printedReceipt+strings.Repeat("x", 1<<20))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "receipt text exceeds 1048576 bytes")
+	mockService.AssertNotCalled(t, "ProcessReceipt", mock.Anything, mock.Anything)
+}
+
+func TestProcessText_ReportsDuplicates(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ProcessReceipt", mock.Anything, mock.Anything).
+		Return("", &internalHttp.DuplicateReceiptError{OriginalID: "original"}).Once()
+
+	w, body := serveText(mockService, printedReceipt)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "original", body.OriginalID)
+	assert.NotEmpty(t, body.Error)
+}
@@ -0,0 +1,135 @@
+package http_test
+
+import (
+	"fmt"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// serveUpdate routes a single request to an UpdateReceiptHandler backed by mockService
+func serveUpdate(t *testing.T, mockService *local_mocks.MockReceiptService, method, url, ifMatch, body string) *httptest.ResponseRecorder {
+	handler := adaptersHttp.NewUpdateReceiptHandler(mockService)
+	router := gin.Default()
+	router.PUT("/receipt/:id", handler.PutReceipt)
+	router.PATCH("/receipt/:id", handler.PatchReceipt)
+	router.GET("/receipt/:id/revisions", handler.GetRevisions)
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// rescored returns the stored receipt as the service would return it after correcting the total
+func rescored(total string, points int) domain.Receipt {
+	receipt := storedReceipt
+	receipt.Total = total
+	receipt.Points = points
+	receipt.PointsDelta = points - storedReceipt.Points
+	receipt.Revision = storedReceipt.Revision + 1
+	receipt.RevisedAt = time.Date(2024, 11, 30, 9, 0, 0, 0, time.UTC)
+	return receipt
+}
+
+func TestPutReceipt_Success(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetReceipt", mock.Anything, "123").Return(storedReceipt, nil)
+	mockService.On("UpdateReceipt", mock.Anything, "123", mock.MatchedBy(func(r domain.Receipt) bool {
+		return r.Total == "7.00" && r.Retailer == "Target"
+	}), 1).Return(rescored("7.00", 103), nil)
+
+	body := `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01",
+		"items": [{"shortDescription": "Mountain Dew 12PK", "price": "7.00"}], "total": "7.00"}`
+	w := serveUpdate(t, mockService, "PUT", "/receipt/123", `"1"`, body)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), `"pointsDelta":75`)
+	assert.Contains(t, w.Body.String(), `"revision":2`)
+	mockService.AssertExpectations(t)
+}
+
+func TestPutReceipt_InvalidPayload(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+
+	w := serveUpdate(t, mockService, "PUT", "/receipt/123", `"1"`, `{"retailer": "Target"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "UpdateReceipt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchReceipt_MergesIntoCurrentReceipt(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetReceipt", mock.Anything, "123").Return(storedReceipt, nil)
+	mockService.On("UpdateReceipt", mock.Anything, "123", mock.MatchedBy(func(r domain.Receipt) bool {
+		// Only the total changes; everything else comes from the stored receipt
+		return r.Total == "7.00" && r.Retailer == storedReceipt.Retailer && len(r.Items) == 1
+	}), 1).Return(rescored("7.00", 103), nil)
+
+	w := serveUpdate(t, mockService, "PATCH", "/receipt/123", `"1"`, `{"total": "7.00"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchReceipt_RemovingRequiredFieldFailsValidation(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetReceipt", mock.Anything, "123").Return(storedReceipt, nil)
+
+	w := serveUpdate(t, mockService, "PATCH", "/receipt/123", `"1"`, `{"retailer": null}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "UpdateReceipt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateReceipt_Preconditions(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetReceipt", mock.Anything, "123").Return(storedReceipt, nil)
+
+	w := serveUpdate(t, mockService, "PATCH", "/receipt/123", "", `{"total": "7.00"}`)
+	assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+
+	w = serveUpdate(t, mockService, "PATCH", "/receipt/123", `"7"`, `{"total": "7.00"}`)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	mockService.AssertNotCalled(t, "UpdateReceipt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateReceipt_LostRace(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("GetReceipt", mock.Anything, "123").Return(storedReceipt, nil)
+	mockService.On("UpdateReceipt", mock.Anything, "123", mock.Anything, 1).
+		Return(domain.Receipt{}, fmt.Errorf("failed to update receipt: %w", repository.ErrRevisionConflict))
+
+	w := serveUpdate(t, mockService, "PATCH", "/receipt/123", "*", `{"total": "7.00"}`)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+}
+
+func TestGetRevisions_Success(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	mockService.On("ListRevisions", mock.Anything, "123").Return([]domain.Receipt{storedReceipt}, nil)
+
+	w := serveUpdate(t, mockService, "GET", "/receipt/123/revisions", "", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"revisions":[{"id":"123"`)
+}
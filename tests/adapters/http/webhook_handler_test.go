@@ -0,0 +1,103 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/adapters/memory"
+	"go-receipt-processor/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveWebhooks routes a single request to a WebhookHandler backed by store
+func serveWebhooks(t *testing.T, store *memory.WebhookStoreImpl, method, url, body string) *httptest.ResponseRecorder {
+	handler := adaptersHttp.NewWebhookHandler(store)
+	router := gin.Default()
+	router.POST("/webhooks", handler.CreateSubscription)
+	router.GET("/webhooks", handler.ListSubscriptions)
+	router.GET("/webhooks/:id", handler.GetSubscription)
+	router.DELETE("/webhooks/:id", handler.DeleteSubscription)
+	router.GET("/webhooks/:id/deliveries", handler.ListDeliveries)
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestWebhookHandler_CreateGetAndDelete(t *testing.T) {
+	store := memory.NewWebhookStore()
+
+	w := serveWebhooks(t, store, http.MethodPost, "/webhooks",
+		`{"url": "https://partner.example/hooks", "events": ["receipt.scored"], "secret": "s3cret"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "s3cret")
+
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	id := created["id"].(string)
+	assert.Equal(t, "https://partner.example/hooks", created["url"])
+	assert.Equal(t, []interface{}{"receipt.scored"}, created["events"])
+
+	subscription, err := store.Subscription(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", subscription.Secret)
+
+	w = serveWebhooks(t, store, http.MethodGet, "/webhooks/"+id, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "s3cret")
+
+	w = serveWebhooks(t, store, http.MethodGet, "/webhooks", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+
+	w = serveWebhooks(t, store, http.MethodDelete, "/webhooks/"+id, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = serveWebhooks(t, store, http.MethodGet, "/webhooks/"+id, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhookHandler_CreateRejectsInvalidRequests(t *testing.T) {
+	tests := map[string]string{
+		"missing secret":     `{"url": "https://partner.example", "events": ["receipt.scored"]}`,
+		"no events":          `{"url": "https://partner.example", "events": [], "secret": "s"}`,
+		"unknown event":      `{"url": "https://partner.example", "events": ["receipt.lost"], "secret": "s"}`,
+		"relative url":       `{"url": "/hooks", "events": ["receipt.scored"], "secret": "s"}`,
+		"unsupported scheme": `{"url": "ftp://partner.example", "events": ["receipt.scored"], "secret": "s"}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := serveWebhooks(t, memory.NewWebhookStore(), http.MethodPost, "/webhooks", body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestWebhookHandler_ListDeliveries(t *testing.T) {
+	store := memory.NewWebhookStore()
+	subscription, err := store.CreateSubscription(context.Background(), domain.WebhookSubscription{URL: "https://partner.example", Events: []string{domain.EventReceiptScored}})
+	require.NoError(t, err)
+	require.NoError(t, store.RecordDelivery(context.Background(), domain.WebhookDelivery{
+		SubscriptionID: subscription.ID, EventID: "event-1", EventType: domain.EventReceiptScored, Attempt: 1, StatusCode: 503, Error: "receiver responded 503 Service Unavailable",
+	}))
+
+	w := serveWebhooks(t, store, http.MethodGet, "/webhooks/"+subscription.ID+"/deliveries", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"eventId":"event-1"`)
+	assert.Contains(t, w.Body.String(), `"statusCode":503`)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+
+	w = serveWebhooks(t, store, http.MethodGet, "/webhooks/missing/deliveries", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
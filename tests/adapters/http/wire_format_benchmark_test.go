@@ -0,0 +1,70 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-receipt-processor/api"
+	"go-receipt-processor/internal/adapters/format"
+	adaptersHttp "go-receipt-processor/internal/adapters/http"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/tests/local_mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// benchmarkService answers ProcessReceipt without the bookkeeping of a mock, so the benchmark measures the transport
+type benchmarkService struct {
+	*local_mocks.MockReceiptService
+}
+
+func (benchmarkService) ProcessReceipt(context.Context, domain.Receipt) (string, error) {
+	return "7fb1377b-b223-49d9-a31a-5a02701dd310", nil
+}
+
+// BenchmarkProcessReceipt compares the throughput of POST /receipt/process, validation included,
+// when the receipt and the response are sent in each wire format.
+func BenchmarkProcessReceipt(b *testing.B) {
+	gin.SetMode(gin.ReleaseMode)
+	doc, err := api.LoadOpenAPI()
+	require.NoError(b, err)
+	validator, err := api.NewValidator(doc)
+	require.NoError(b, err)
+
+	router := gin.New()
+	router.Use(adaptersHttp.OpenAPIValidationMiddleware(validator, "", adaptersHttp.WriteValidationError))
+	router.POST("/receipt/process", adaptersHttp.NewReceiptProcessHandler(benchmarkService{}).ProcessReceipt)
+
+	formats := []struct {
+		name      string
+		mediaType string
+		encode    func(domain.Receipt) ([]byte, error)
+	}{
+		{"json", format.MIMEJSON, func(r domain.Receipt) ([]byte, error) { return json.Marshal(r) }},
+		{"protobuf", format.MIMEProtobuf, format.MarshalReceiptProtobuf},
+		{"msgpack", format.MIMEMsgpack, func(r domain.Receipt) ([]byte, error) { return format.MarshalMsgpack(r) }},
+	}
+	for _, f := range formats {
+		body, err := f.encode(negotiatedReceipt)
+		require.NoError(b, err)
+
+		b.Run(f.name, func(b *testing.B) {
+			b.SetBytes(int64(len(body)))
+			b.ReportAllocs()
+			for range b.N {
+				req := httptest.NewRequest("POST", "/receipt/process", bytes.NewReader(body))
+				req.Header.Set("Content-Type", f.mediaType)
+				req.Header.Set("Accept", f.mediaType)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				if w.Code != http.StatusOK {
+					b.Fatalf("status %d: %s", w.Code, w.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package plaintext_test
+
+import (
+	"encoding/json"
+	"go-receipt-processor/internal/adapters/plaintext"
+	"go-receipt-processor/internal/domain"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expectedParse is the parse recorded next to each sample receipt in testdata, as <name>.json beside <name>.txt
+type expectedParse struct {
+	Receipt    domain.Receipt     `json:"receipt"`
+	Confidence map[string]float64 `json:"confidence"`
+}
+
+func TestParse_Corpus(t *testing.T) {
+	samples, err := filepath.Glob(filepath.Join("testdata", "*.txt"))
+	require.NoError(t, err)
+	require.NotEmpty(t, samples)
+
+	for _, sample := range samples {
+		name := strings.TrimSuffix(filepath.Base(sample), ".txt")
+		t.Run(name, func(t *testing.T) {
+			text, err := os.ReadFile(sample)
+			require.NoError(t, err)
+			data, err := os.ReadFile(strings.TrimSuffix(sample, ".txt") + ".json")
+			require.NoError(t, err)
+			var expected expectedParse
+			require.NoError(t, json.Unmarshal(data, &expected))
+
+			result := plaintext.Parse(string(text))
+
+			assert.Equal(t, expected.Receipt, result.Receipt)
+			assert.Equal(t, expected.Confidence, result.Confidence)
+		})
+	}
+}
+
+func TestParse_EmptyText(t *testing.T) {
+	result := plaintext.Parse("  \n\n")
+
+	assert.Equal(t, domain.Receipt{}, result.Receipt)
+	assert.Equal(t, map[string]float64{
+		plaintext.FieldRetailer:     0,
+		plaintext.FieldPurchaseDate: 0,
+		plaintext.FieldPurchaseTime: 0,
+		plaintext.FieldItems:        0,
+		plaintext.FieldTotal:        0,
+	}, result.Confidence)
+}
+
+func TestParse_AmbiguousDatesAreLessCertain(t *testing.T) {
+	tests := []struct {
+		line       string
+		date       string
+		confidence float64
+	}{
+		{"2022-01-02", "2022-01-02", 0.95},
+		{"01/02/2022", "2022-01-02", 0.7},
+		{"01/13/2022", "2022-01-13", 0.9},
+		{"13/01/2022", "2022-01-13", 0.85},
+		{"13.01.22", "2022-01-13", 0.75},
+		{"January 2nd, 2022", "2022-01-02", 0.9},
+		{"02/30/2022", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			result := plaintext.Parse("Target\n" + tt.line)
+
+			assert.Equal(t, tt.date, result.Receipt.PurchaseDate)
+			assert.Equal(t, tt.confidence, result.Confidence[plaintext.FieldPurchaseDate])
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package webhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"go-receipt-processor/internal/adapters/memory"
+	"go-receipt-processor/internal/adapters/webhook"
+	"go-receipt-processor/internal/domain"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "s3cret"
+
+var scoredEvent = domain.ReceiptEvent{
+	ID:         "event-1",
+	Type:       domain.EventReceiptScored,
+	ReceiptID:  "receipt-1",
+	Retailer:   "Target",
+	Points:     28,
+	OccurredAt: time.Date(2024, 11, 29, 14, 30, 0, 0, time.UTC),
+}
+
+// receivedDelivery is what the test receiver observed for one request
+type receivedDelivery struct {
+	event         domain.ReceiptEvent
+	signatureOK   bool
+	eventHeader   string
+	eventIDHeader string
+}
+
+// newReceiver starts an httptest server that checks signatures, answers with the given status codes in
+// turn (200 once they run out) and reports each request on the returned channel
+func newReceiver(t *testing.T, statuses ...int) (*httptest.Server, <-chan receivedDelivery) {
+	received := make(chan receivedDelivery, 10)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		expected := webhook.Sign(secret, r.Header.Get(webhook.HeaderTimestamp), body)
+		delivery := receivedDelivery{
+			signatureOK:   hmac.Equal([]byte(expected), []byte(r.Header.Get(webhook.HeaderSignature))),
+			eventHeader:   r.Header.Get(webhook.HeaderEvent),
+			eventIDHeader: r.Header.Get(webhook.HeaderEventID),
+		}
+		assert.NoError(t, json.Unmarshal(body, &delivery.event))
+		received <- delivery
+
+		status := http.StatusOK
+		if n := int(calls.Add(1)); n <= len(statuses) {
+			status = statuses[n-1]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+// startDispatcher runs a dispatcher with fast retries until the test ends
+func startDispatcher(t *testing.T, store *memory.WebhookStoreImpl, maxAttempts int) *webhook.Dispatcher {
+	dispatcher := webhook.NewDispatcher(store, nil, webhook.Options{
+		Workers:        1,
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Timeout:        time.Second,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return dispatcher
+}
+
+// awaitDelivery returns the next request seen by the receiver, failing the test if none arrives
+func awaitDelivery(t *testing.T, received <-chan receivedDelivery) receivedDelivery {
+	select {
+	case delivery := <-received:
+		return delivery
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not delivered")
+		return receivedDelivery{}
+	}
+}
+
+func TestDispatcher_DeliversSignedEvent(t *testing.T) {
+	server, received := newReceiver(t)
+	store := memory.NewWebhookStore()
+	subscription, err := store.CreateSubscription(context.Background(), domain.WebhookSubscription{
+		URL: server.URL, Events: []string{domain.EventReceiptScored}, Secret: secret,
+	})
+	require.NoError(t, err)
+	dispatcher := startDispatcher(t, store, 3)
+
+	dispatcher.Publish(scoredEvent)
+
+	delivery := awaitDelivery(t, received)
+	assert.True(t, delivery.signatureOK)
+	assert.Equal(t, domain.EventReceiptScored, delivery.eventHeader)
+	assert.Equal(t, "event-1", delivery.eventIDHeader)
+	assert.Equal(t, scoredEvent, delivery.event)
+
+	assert.Eventually(t, func() bool {
+		deliveries, _ := store.Deliveries(context.Background(), subscription.ID)
+		return len(deliveries) == 1 && deliveries[0].Succeeded && deliveries[0].StatusCode == http.StatusOK
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestDispatcher_RetriesFailedDeliveries(t *testing.T) {
+	server, received := newReceiver(t, http.StatusInternalServerError, http.StatusServiceUnavailable)
+	store := memory.NewWebhookStore()
+	subscription, err := store.CreateSubscription(context.Background(), domain.WebhookSubscription{
+		URL: server.URL, Events: []string{domain.EventReceiptScored}, Secret: secret,
+	})
+	require.NoError(t, err)
+	dispatcher := startDispatcher(t, store, 5)
+
+	dispatcher.Publish(scoredEvent)
+
+	for i := 0; i < 3; i++ {
+		delivery := awaitDelivery(t, received)
+		assert.True(t, delivery.signatureOK)
+		assert.Equal(t, "event-1", delivery.eventIDHeader)
+	}
+
+	assert.Eventually(t, func() bool {
+		deliveries, _ := store.Deliveries(context.Background(), subscription.ID)
+		return len(deliveries) == 3
+	}, time.Second, 5*time.Millisecond)
+	deliveries, err := store.Deliveries(context.Background(), subscription.ID)
+	require.NoError(t, err)
+	assert.True(t, deliveries[0].Succeeded)
+	assert.Equal(t, 3, deliveries[0].Attempt)
+	assert.False(t, deliveries[1].Succeeded)
+	assert.Equal(t, http.StatusServiceUnavailable, deliveries[1].StatusCode)
+	assert.Equal(t, http.StatusInternalServerError, deliveries[2].StatusCode)
+	assert.Equal(t, 1, deliveries[2].Attempt)
+}
+
+func TestDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	server, received := newReceiver(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	store := memory.NewWebhookStore()
+	subscription, err := store.CreateSubscription(context.Background(), domain.WebhookSubscription{
+		URL: server.URL, Events: []string{domain.EventReceiptScored}, Secret: secret,
+	})
+	require.NoError(t, err)
+	dispatcher := startDispatcher(t, store, 2)
+
+	dispatcher.Publish(scoredEvent)
+	awaitDelivery(t, received)
+	awaitDelivery(t, received)
+
+	assert.Eventually(t, func() bool {
+		deliveries, _ := store.Deliveries(context.Background(), subscription.ID)
+		return len(deliveries) == 2
+	}, time.Second, 5*time.Millisecond)
+	select {
+	case <-received:
+		t.Fatal("delivery retried past MaxAttempts")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestDispatcher_FailingReceiverDoesNotDelayOthers(t *testing.T) {
+	var failures atomic.Int32
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failures.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(failing.Close)
+	healthy, received := newReceiver(t)
+	store := memory.NewWebhookStore()
+	for _, url := range []string{failing.URL, healthy.URL} {
+		_, err := store.CreateSubscription(context.Background(), domain.WebhookSubscription{
+			URL: url, Events: []string{domain.EventReceiptScored}, Secret: secret,
+		})
+		require.NoError(t, err)
+	}
+	// A single worker and backoffs far longer than the test: waiting for a retry in the worker would
+	// hold the healthy receiver's second event back by seconds
+	dispatcher := webhook.NewDispatcher(store, nil, webhook.Options{
+		Workers:        1,
+		MaxAttempts:    5,
+		InitialBackoff: 5 * time.Second,
+		MaxBackoff:     time.Minute,
+		Timeout:        time.Second,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	second := scoredEvent
+	second.ID = "event-2"
+	dispatcher.Publish(scoredEvent)
+	dispatcher.Publish(second)
+
+	start := time.Now()
+	assert.Equal(t, "event-1", awaitDelivery(t, received).eventIDHeader)
+	assert.Equal(t, "event-2", awaitDelivery(t, received).eventIDHeader)
+	assert.Less(t, time.Since(start), time.Second)
+	// Each event is attempted once at the failing receiver too, and its retry waits on a timer
+	assert.Eventually(t, func() bool { return failures.Load() == 2 }, time.Second, 5*time.Millisecond)
+}
+
+func TestDispatcher_SkipsUnwantedEvents(t *testing.T) {
+	server, received := newReceiver(t)
+	store := memory.NewWebhookStore()
+	_, err := store.CreateSubscription(context.Background(), domain.WebhookSubscription{
+		URL: server.URL, Events: []string{domain.EventReceiptDeleted}, Secret: secret,
+	})
+	require.NoError(t, err)
+	dispatcher := startDispatcher(t, store, 1)
+
+	dispatcher.Publish(scoredEvent)
+	deleted := scoredEvent
+	deleted.ID, deleted.Type = "event-2", domain.EventReceiptDeleted
+	dispatcher.Publish(deleted)
+
+	assert.Equal(t, "event-2", awaitDelivery(t, received).eventIDHeader)
+}
+
+func TestDispatcher_PublishDoesNotBlock(t *testing.T) {
+	dispatcher := webhook.NewDispatcher(memory.NewWebhookStore(), nil, webhook.Options{BufferSize: 1})
+
+	done := make(chan struct{})
+	go func() {
+		// Nothing is running the dispatcher, so every event after the first must be dropped
+		for i := 0; i < 10; i++ {
+			dispatcher.Publish(scoredEvent)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a full buffer")
+	}
+}
+
+func TestSign(t *testing.T) {
+	signature := webhook.Sign("key", "1700000000", []byte(`{"id":"1"}`))
+
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.Equal(t, signature, webhook.Sign("key", "1700000000", []byte(`{"id":"1"}`)))
+	assert.NotEqual(t, signature, webhook.Sign("other", "1700000000", []byte(`{"id":"1"}`)))
+	assert.NotEqual(t, signature, webhook.Sign("key", "1700000001", []byte(`{"id":"1"}`)))
+}
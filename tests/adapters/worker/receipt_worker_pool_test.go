@@ -0,0 +1,67 @@
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"go-receipt-processor/internal/adapters/memory"
+	"go-receipt-processor/internal/adapters/worker"
+	"go-receipt-processor/internal/application"
+	"go-receipt-processor/tests/local_mocks"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiptWorkerPool_ProcessesQueuedReceipts(t *testing.T) {
+	service := application.NewReceiptService(
+		application.NewPointsCalculator(application.NewPointsCalculatorHelper()),
+		memory.NewReceiptStore(),
+		application.WithReceiptQueue(memory.NewReceiptQueue(10)),
+	)
+	receipt := local_mocks.MockReceipt
+	receipt.Retailer = "Worker Pool Mart"
+	job, err := service.EnqueueReceipt(context.Background(), receipt)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		worker.NewReceiptWorkerPool(service, 2).Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		_, err := service.GetPoints(context.Background(), job.ID)
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+
+	stored, err := service.GetReceipt(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Worker Pool Mart", stored.Retailer)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("workers did not stop after cancellation")
+	}
+}
+
+func TestReceiptWorkerPool_KeepsRunningAfterFailedJobs(t *testing.T) {
+	mockService := new(local_mocks.MockReceiptService)
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	mockService.On("ProcessNextJob", mock.Anything).Return(errors.New("job failed")).Run(func(mock.Arguments) {
+		if calls.Add(1) == 3 {
+			cancel()
+		}
+	})
+
+	worker.NewReceiptWorkerPool(mockService, 1).Run(ctx)
+
+	assert.Equal(t, int32(3), calls.Load())
+}
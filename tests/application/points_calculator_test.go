@@ -1,7 +1,9 @@
 package application_test
 
 import (
+	"context"
 	"go-receipt-processor/internal/application"
+	"go-receipt-processor/internal/domain"
 	"go-receipt-processor/tests/local_mocks"
 	"testing"
 
@@ -45,7 +47,7 @@ func TestCalculatePoints_MockedHelpers(t *testing.T) {
 	calculator := application.NewPointsCalculator(mockRules)
 
 	// Calculate points
-	points, err := calculator.CalculatePoints(local_mocks.MockReceipt)
+	points, err := calculator.CalculatePoints(context.Background(), local_mocks.MockReceipt)
 
 	// Assert the points and no error
 	assert.NoError(t, err)
@@ -54,3 +56,51 @@ func TestCalculatePoints_MockedHelpers(t *testing.T) {
 	// Assert that all expected mock methods were called
 	mockRules.AssertExpectations(t)
 }
+
+func TestCalculatePoints_CancelledContext(t *testing.T) {
+	// No rule should run once the caller has gone away
+	mockRules := new(local_mocks.MockPointsCalculatorRules)
+	calculator := application.NewPointsCalculator(mockRules)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	points, err := calculator.CalculatePoints(ctx, local_mocks.MockReceipt)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, points)
+	mockRules.AssertNotCalled(t, "AddPointsForRetailerName", mock.Anything)
+}
+
+func TestBreakdown_ReportsEachRule(t *testing.T) {
+	calculator := application.NewPointsCalculator(application.NewPointsCalculatorHelper())
+	receipt := domain.Receipt{
+		Retailer:     "M&M Corner Market",
+		PurchaseDate: "2022-03-20",
+		PurchaseTime: "14:33",
+		Items: []domain.Item{
+			{ShortDescription: "Gatorade", Price: "2.25"},
+			{ShortDescription: "Gatorade", Price: "2.25"},
+			{ShortDescription: "Gatorade", Price: "2.25"},
+			{ShortDescription: "Gatorade", Price: "2.25"},
+		},
+		Total: "9.00",
+	}
+
+	breakdown, err := calculator.Breakdown(context.Background(), receipt)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.RulePoints{
+		{Rule: domain.RuleRetailerName, Points: 14},
+		{Rule: domain.RuleRoundDollarTotal, Points: 50},
+		{Rule: domain.RuleMultipleOfQuarter, Points: 25},
+		{Rule: domain.RuleItemCount, Points: 10},
+		{Rule: domain.RuleItemDescriptions, Points: 0},
+		{Rule: domain.RuleOddDay, Points: 0},
+		{Rule: domain.RuleAfternoonPurchaseTime, Points: 10},
+	}, breakdown)
+
+	points, err := calculator.CalculatePoints(context.Background(), receipt)
+	assert.NoError(t, err)
+	assert.Equal(t, 109, points)
+}
@@ -1,12 +1,19 @@
 package application_test
 
 import (
+	"context"
 	"fmt"
+	"go-receipt-processor/internal/adapters/memory"
 	"go-receipt-processor/internal/application"
 	"go-receipt-processor/internal/domain"
+	portsHttp "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/repository"
+	"go-receipt-processor/pkg/utils"
 	"go-receipt-processor/tests/local_mocks"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -24,16 +31,16 @@ func TestReceiptService_ProcessReceipt(t *testing.T) {
 	receipt.Points = 50 // Set the expected points value after calculation
 
 	// Mock behavior for CalculatePoints to return 50 points
-	mockPointsCalculator.On("CalculatePoints", receipt).Return(50, nil)
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, receipt).Return(50, nil)
 
 	// Mock behavior for Save to expect the receipt with Points set to 50
-	mockReceiptStore.On("Save", mock.MatchedBy(func(r domain.Receipt) bool {
+	mockReceiptStore.On("Save", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool {
 		// Ensure the Points are 50 when saving
 		return r.Points == 50
 	})).Return("12345", nil)
 
 	// Call ProcessReceipt method
-	receiptID, err := receiptService.ProcessReceipt(receipt)
+	receiptID, err := receiptService.ProcessReceipt(context.Background(), receipt)
 
 	// Assertions
 	assert.NoError(t, err)
@@ -54,10 +61,10 @@ func TestReceiptService_ProcessReceipt_ErrorInPointsCalculation(t *testing.T) {
 	receipt := local_mocks.MockReceipt
 
 	// Mock behavior for CalculatePoints to return an error
-	mockPointsCalculator.On("CalculatePoints", receipt).Return(0, fmt.Errorf("invalid purchase time format"))
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, receipt).Return(0, fmt.Errorf("invalid purchase time format"))
 
 	// Call ProcessReceipt method
-	receiptID, err := receiptService.ProcessReceipt(receipt)
+	receiptID, err := receiptService.ProcessReceipt(context.Background(), receipt)
 
 	// Assertions
 	assert.Error(t, err)
@@ -79,10 +86,10 @@ func TestReceiptService_GetPoints(t *testing.T) {
 	receipt.Points = 50 // Set the expected points for the receipt
 
 	// Mock the behavior of Find method to return the receipt
-	mockReceiptStore.On("Find", "12345").Return(receipt, nil)
+	mockReceiptStore.On("Find", mock.Anything, "12345").Return(receipt, nil)
 
 	// Call the GetPoints method
-	points, err := receiptService.GetPoints("12345")
+	points, err := receiptService.GetPoints(context.Background(), "12345")
 
 	// Assertions
 	assert.NoError(t, err)      // No error should be returned
@@ -99,13 +106,698 @@ func TestReceiptService_GetPoints_ReceiptNotFound(t *testing.T) {
 	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore)
 
 	// Mock the behavior of Find method to return an error (receipt not found)
-	mockReceiptStore.On("Find", "12345").Return(domain.Receipt{}, fmt.Errorf("receipt not found"))
+	mockReceiptStore.On("Find", mock.Anything, "12345").Return(domain.Receipt{}, fmt.Errorf("receipt not found"))
 
 	// Call the GetPoints method
-	points, err := receiptService.GetPoints("12345")
+	points, err := receiptService.GetPoints(context.Background(), "12345")
 
 	// Assertions
 	assert.Error(t, err)       // Error should be returned
 	assert.Equal(t, 0, points) // Points should be 0
 	mockReceiptStore.AssertExpectations(t)
 }
+
+func TestReceiptService_ProcessReceipt_PropagatesContext(t *testing.T) {
+	// Create mock objects
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore)
+
+	// The context handed to the service must reach both dependencies unchanged
+	ctx := utils.WithRequestID(context.Background(), "req-1")
+	isRequestContext := mock.MatchedBy(func(c context.Context) bool {
+		return utils.RequestIDFrom(c) == "req-1"
+	})
+	mockPointsCalculator.On("CalculatePoints", isRequestContext, mock.Anything).Return(50, nil)
+	mockReceiptStore.On("Save", isRequestContext, mock.Anything).Return("12345", nil)
+
+	receiptID, err := receiptService.ProcessReceipt(ctx, local_mocks.MockReceipt)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", receiptID)
+	mockPointsCalculator.AssertExpectations(t)
+	mockReceiptStore.AssertExpectations(t)
+}
+
+func TestReceiptService_GetPoints_DeadlineExceeded(t *testing.T) {
+	// Create mock objects
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore)
+
+	// A store timing out must remain recognisable to callers after wrapping
+	mockReceiptStore.On("Find", mock.Anything, "12345").Return(domain.Receipt{}, context.DeadlineExceeded)
+
+	_, err := receiptService.GetPoints(context.Background(), "12345")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	mockReceiptStore.AssertExpectations(t)
+}
+
+func TestReceiptService_ListReceipts(t *testing.T) {
+	// Create mock objects
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore)
+
+	query := repository.ReceiptQuery{Retailer: "StoreABC", Limit: 10}
+	expectedPage := repository.ReceiptPage{Receipts: []domain.Receipt{local_mocks.MockReceipt}, NextCursor: "next"}
+	mockReceiptStore.On("Query", mock.Anything, query).Return(expectedPage, nil)
+
+	page, err := receiptService.ListReceipts(context.Background(), query)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedPage, page)
+	mockReceiptStore.AssertExpectations(t)
+}
+
+func TestReceiptService_ProcessReceipt_RecordsMetadata(t *testing.T) {
+	// Create mock objects
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore)
+
+	before := time.Now().UTC()
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(50, nil)
+	mockReceiptStore.On("Save", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool {
+		return r.ScoringVersion == application.ScoringVersion && !r.ReceivedAt.Before(before)
+	})).Return("12345", nil)
+
+	_, err := receiptService.ProcessReceipt(context.Background(), local_mocks.MockReceipt)
+
+	assert.NoError(t, err)
+	mockReceiptStore.AssertExpectations(t)
+}
+
+func TestReceiptService_ProcessReceipt_ResetsRevisionMetadata(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore)
+
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(50, nil)
+	mockReceiptStore.On("Save", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool {
+		return r.Revision == 1 && r.PointsDelta == 0 && r.RevisedAt.IsZero()
+	})).Return("12345", nil)
+
+	receipt := local_mocks.MockReceipt
+	receipt.Revision = 7
+	receipt.PointsDelta = 999
+	receipt.RevisedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := receiptService.ProcessReceipt(context.Background(), receipt)
+
+	assert.NoError(t, err)
+	mockReceiptStore.AssertExpectations(t)
+}
+
+func TestReceiptService_GetReceipt(t *testing.T) {
+	// Create mock objects
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore)
+
+	mockReceiptStore.On("Find", mock.Anything, "12345").Return(local_mocks.MockReceipt, nil)
+	mockReceiptStore.On("Find", mock.Anything, "missing").Return(domain.Receipt{}, repository.ErrReceiptNotFound)
+
+	receipt, err := receiptService.GetReceipt(context.Background(), "12345")
+	assert.NoError(t, err)
+	assert.Equal(t, local_mocks.MockReceipt, receipt)
+
+	_, err = receiptService.GetReceipt(context.Background(), "missing")
+	assert.ErrorIs(t, err, repository.ErrReceiptNotFound)
+	mockReceiptStore.AssertExpectations(t)
+}
+
+func TestReceiptService_DeleteReceipt(t *testing.T) {
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(new(local_mocks.MockPointsCalculator), mockReceiptStore)
+
+	mockReceiptStore.On("Delete", mock.Anything, "123", mock.MatchedBy(func(tombstone domain.Tombstone) bool {
+		return tombstone.ReceiptID == "123" && tombstone.DeletedBy == "alice" && tombstone.Reason == "duplicate" && !tombstone.DeletedAt.IsZero()
+	})).Return(nil)
+
+	tombstone, err := receiptService.DeleteReceipt(context.Background(), "123", "alice", "duplicate")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "123", tombstone.ReceiptID)
+	assert.WithinDuration(t, time.Now(), tombstone.DeletedAt, time.Minute)
+	mockReceiptStore.AssertExpectations(t)
+}
+
+func TestReceiptService_DeleteReceipt_NotFound(t *testing.T) {
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(new(local_mocks.MockPointsCalculator), mockReceiptStore)
+
+	mockReceiptStore.On("Delete", mock.Anything, "missing", mock.Anything).Return(repository.ErrReceiptNotFound)
+
+	_, err := receiptService.DeleteReceipt(context.Background(), "missing", "alice", "duplicate")
+
+	assert.ErrorIs(t, err, repository.ErrReceiptNotFound)
+}
+
+func TestReceiptService_EraseReceipts(t *testing.T) {
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(new(local_mocks.MockPointsCalculator), mockReceiptStore)
+
+	selector := repository.ErasureSelector{Retailer: "Target"}
+	mockReceiptStore.On("Erase", mock.Anything, selector, mock.MatchedBy(func(audit domain.Tombstone) bool {
+		return audit.DeletedBy == "dpo" && audit.Reason == "gdpr" && !audit.DeletedAt.IsZero()
+	})).Return([]string{"1", "2"}, nil)
+
+	erased, err := receiptService.EraseReceipts(context.Background(), selector, "dpo", "gdpr")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, erased)
+
+	_, err = receiptService.EraseReceipts(context.Background(), repository.ErasureSelector{}, "dpo", "gdpr")
+	assert.Error(t, err)
+	mockReceiptStore.AssertNumberOfCalls(t, "Erase", 1)
+}
+
+func TestReceiptService_UpdateReceipt_RescoresAndRecordsDelta(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore)
+
+	current := local_mocks.MockReceipt
+	current.ID = "123"
+	current.Points = 28
+	current.Revision = 1
+	current.ReceivedAt = time.Date(2024, 11, 29, 14, 30, 0, 0, time.UTC)
+	mockReceiptStore.On("Find", mock.Anything, "123").Return(current, nil)
+
+	corrected := local_mocks.MockReceipt
+	corrected.Total = "99.00"
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, corrected).Return(103, nil)
+	mockReceiptStore.On("Update", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool {
+		return r.ID == "123" && r.Revision == 2 && r.Points == 103 && r.PointsDelta == 75
+	}), 1).Return(nil)
+
+	updated, err := receiptService.UpdateReceipt(context.Background(), "123", corrected, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated.Revision)
+	assert.Equal(t, 75, updated.PointsDelta)
+	assert.Equal(t, current.ReceivedAt, updated.ReceivedAt)
+	assert.False(t, updated.RevisedAt.IsZero())
+	mockReceiptStore.AssertExpectations(t)
+}
+
+func TestReceiptService_UpdateReceipt_StaleRevision(t *testing.T) {
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(new(local_mocks.MockPointsCalculator), mockReceiptStore)
+
+	current := local_mocks.MockReceipt
+	current.Revision = 3
+	mockReceiptStore.On("Find", mock.Anything, "123").Return(current, nil)
+
+	_, err := receiptService.UpdateReceipt(context.Background(), "123", local_mocks.MockReceipt, 2)
+
+	assert.ErrorIs(t, err, repository.ErrRevisionConflict)
+	mockReceiptStore.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReceiptService_ProcessReceipts_KeepsOrderAndIsolatesFailures(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore, application.WithBatchWorkers(3))
+
+	receipts := make([]domain.Receipt, 10)
+	for i := range receipts {
+		receipts[i] = local_mocks.MockReceipt
+		receipts[i].Retailer = fmt.Sprintf("Retailer %d", i)
+		mockReceiptStore.On("Save", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool {
+			return r.Retailer == receipts[i].Retailer
+		})).Return(fmt.Sprintf("id-%d", i), nil)
+	}
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool {
+		return r.Retailer != "Retailer 4"
+	})).Return(10, nil)
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool {
+		return r.Retailer == "Retailer 4"
+	})).Return(0, fmt.Errorf("invalid total"))
+
+	results := receiptService.ProcessReceipts(context.Background(), receipts)
+
+	assert.Len(t, results, len(receipts))
+	for i, result := range results {
+		if i == 4 {
+			assert.Error(t, result.Err)
+			assert.Empty(t, result.ReceiptID)
+			continue
+		}
+		assert.NoError(t, result.Err)
+		assert.Equal(t, fmt.Sprintf("id-%d", i), result.ReceiptID)
+	}
+}
+
+func TestReceiptService_ProcessReceipts_CancelledContext(t *testing.T) {
+	receiptService := application.NewReceiptService(new(local_mocks.MockPointsCalculator), new(local_mocks.MockReceiptStore))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := receiptService.ProcessReceipts(ctx, []domain.Receipt{local_mocks.MockReceipt, local_mocks.MockReceipt})
+
+	for _, result := range results {
+		assert.ErrorIs(t, result.Err, context.Canceled)
+	}
+}
+
+func TestReceiptService_ProcessReceipt_StoresFingerprintWithoutLookupWhenOff(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore)
+
+	fingerprint := local_mocks.MockReceipt.CanonicalFingerprint()
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(50, nil)
+	mockReceiptStore.On("Save", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool {
+		return r.Fingerprint == fingerprint && r.DuplicateOf == ""
+	})).Return("12345", nil)
+
+	_, err := receiptService.ProcessReceipt(context.Background(), local_mocks.MockReceipt)
+
+	assert.NoError(t, err)
+	mockReceiptStore.AssertExpectations(t)
+	mockReceiptStore.AssertNotCalled(t, "FindByFingerprint", mock.Anything, mock.Anything)
+}
+
+func TestReceiptService_ProcessReceipt_RejectsDuplicate(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore,
+		application.WithDuplicatePolicy(application.DuplicatePolicyReject))
+
+	received := time.Date(2024, 11, 29, 15, 0, 0, 0, time.UTC)
+	mockReceiptStore.On("FindByFingerprint", mock.Anything, local_mocks.MockReceipt.CanonicalFingerprint()).Return([]domain.Receipt{
+		{ID: "later", ReceivedAt: received.Add(time.Minute)},
+		{ID: "original", ReceivedAt: received},
+	}, nil)
+
+	_, err := receiptService.ProcessReceipt(context.Background(), local_mocks.MockReceipt)
+
+	var duplicate *portsHttp.DuplicateReceiptError
+	assert.ErrorAs(t, err, &duplicate)
+	assert.Equal(t, "original", duplicate.OriginalID)
+	assert.ErrorIs(t, err, portsHttp.ErrDuplicateReceipt)
+	mockReceiptStore.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	mockPointsCalculator.AssertNotCalled(t, "CalculatePoints", mock.Anything, mock.Anything)
+}
+
+func TestReceiptService_ProcessReceipt_FlagsDuplicate(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore,
+		application.WithDuplicatePolicy(application.DuplicatePolicyFlag))
+
+	mockReceiptStore.On("FindByFingerprint", mock.Anything, mock.Anything).Return([]domain.Receipt{{ID: "original"}}, nil)
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(50, nil)
+	mockReceiptStore.On("Save", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool {
+		return r.DuplicateOf == "original"
+	})).Return("12345", nil)
+
+	receiptID, err := receiptService.ProcessReceipt(context.Background(), local_mocks.MockReceipt)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", receiptID)
+	mockReceiptStore.AssertExpectations(t)
+}
+
+func TestReceiptService_ProcessReceipt_RejectsDuplicateSavedSinceLookup(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore,
+		application.WithDuplicatePolicy(application.DuplicatePolicyReject))
+
+	// Nothing was stored at lookup, but the same receipt was saved before this one could be
+	mockReceiptStore.On("FindByFingerprint", mock.Anything, mock.Anything).Return([]domain.Receipt{}, nil)
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(50, nil)
+	mockReceiptStore.On("SaveUnique", mock.Anything, mock.Anything).Return("", "original", nil)
+
+	_, err := receiptService.ProcessReceipt(context.Background(), local_mocks.MockReceipt)
+
+	var duplicate *portsHttp.DuplicateReceiptError
+	assert.ErrorAs(t, err, &duplicate)
+	assert.Equal(t, "original", duplicate.OriginalID)
+	mockReceiptStore.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestReceiptService_ProcessReceipt_FlagsDuplicateSavedSinceLookup(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore,
+		application.WithDuplicatePolicy(application.DuplicatePolicyFlag))
+
+	mockReceiptStore.On("FindByFingerprint", mock.Anything, mock.Anything).Return([]domain.Receipt{}, nil)
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(50, nil)
+	mockReceiptStore.On("SaveUnique", mock.Anything, mock.Anything).Return("", "original", nil)
+	mockReceiptStore.On("Save", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool {
+		return r.DuplicateOf == "original"
+	})).Return("12345", nil)
+
+	receiptID, err := receiptService.ProcessReceipt(context.Background(), local_mocks.MockReceipt)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", receiptID)
+	mockReceiptStore.AssertExpectations(t)
+}
+
+func TestReceiptService_ProcessReceipts_StoresOneOfIdenticalReceipts(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	receiptService := application.NewReceiptService(mockPointsCalculator, memory.NewReceiptStore(),
+		application.WithBatchWorkers(8), application.WithDuplicatePolicy(application.DuplicatePolicyReject))
+	// Scoring happens between the duplicate lookup and the save; slowing it lets every worker look up first
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).After(20*time.Millisecond).Return(50, nil)
+
+	// The memory store is shared by the whole package, so the receipt is made unique to this run
+	receipt := local_mocks.MockReceipt
+	receipt.Retailer = "Batch Mart " + uuid.NewString()
+	batch := make([]domain.Receipt, 8)
+	for i := range batch {
+		batch[i] = receipt
+	}
+
+	results := receiptService.ProcessReceipts(context.Background(), batch)
+
+	stored := 0
+	for _, result := range results {
+		if result.Err == nil {
+			stored++
+			continue
+		}
+		assert.ErrorIs(t, result.Err, portsHttp.ErrDuplicateReceipt)
+	}
+	assert.Equal(t, 1, stored)
+}
+
+func TestReceiptService_EnqueueAndProcessNextJob(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	queue := memory.NewReceiptQueue(10)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore, application.WithReceiptQueue(queue))
+	ctx := context.Background()
+
+	submitted := local_mocks.MockReceipt
+	submitted.ID = "client-supplied"
+	job, err := receiptService.EnqueueReceipt(ctx, submitted)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, job.ID)
+	assert.NotEqual(t, "client-supplied", job.ID)
+	assert.Equal(t, domain.JobStatusPending, job.Status)
+
+	_, err = receiptService.GetPoints(ctx, job.ID)
+	var pending *portsHttp.ReceiptPendingError
+	assert.ErrorAs(t, err, &pending)
+	assert.Equal(t, domain.JobStatusPending, pending.Status)
+
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(50, nil)
+	mockReceiptStore.On("Save", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool {
+		return r.ID == job.ID && r.Points == 50
+	})).Return(job.ID, nil)
+	assert.NoError(t, receiptService.ProcessNextJob(ctx))
+
+	mockReceiptStore.On("Find", mock.Anything, job.ID).Return(domain.Receipt{ID: job.ID, Points: 50}, nil)
+	points, err := receiptService.GetPoints(ctx, job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 50, points)
+	mockReceiptStore.AssertExpectations(t)
+}
+
+func TestReceiptService_ProcessNextJob_RecordsFailure(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	queue := memory.NewReceiptQueue(10)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore, application.WithReceiptQueue(queue))
+	ctx := context.Background()
+
+	job, err := receiptService.EnqueueReceipt(ctx, local_mocks.MockReceipt)
+	assert.NoError(t, err)
+
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(0, fmt.Errorf("invalid total"))
+	assert.Error(t, receiptService.ProcessNextJob(ctx))
+
+	_, err = receiptService.GetPoints(ctx, job.ID)
+	assert.EqualError(t, err, "receipt processing failed: unable to process receipt: invalid total")
+	assert.NotErrorIs(t, err, portsHttp.ErrReceiptPending)
+	mockReceiptStore.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestReceiptService_EnqueueReceipt_QueueFull(t *testing.T) {
+	receiptService := application.NewReceiptService(new(local_mocks.MockPointsCalculator), new(local_mocks.MockReceiptStore),
+		application.WithReceiptQueue(memory.NewReceiptQueue(1)))
+
+	_, err := receiptService.EnqueueReceipt(context.Background(), local_mocks.MockReceipt)
+	assert.NoError(t, err)
+	_, err = receiptService.EnqueueReceipt(context.Background(), local_mocks.MockReceipt)
+	assert.ErrorIs(t, err, repository.ErrQueueFull)
+}
+
+func TestReceiptService_EnqueueReceipt_WithoutQueue(t *testing.T) {
+	receiptService := application.NewReceiptService(new(local_mocks.MockPointsCalculator), new(local_mocks.MockReceiptStore))
+
+	_, err := receiptService.EnqueueReceipt(context.Background(), local_mocks.MockReceipt)
+	assert.EqualError(t, err, "asynchronous processing is not enabled")
+}
+
+// newAsyncReceiptService builds a service with an in-memory queue and dead-letter store and fast retries
+func newAsyncReceiptService(calculator *local_mocks.MockPointsCalculator, store *local_mocks.MockReceiptStore, queueCapacity int) (portsHttp.ReceiptService, *memory.DeadLetterStoreImpl) {
+	deadLetters := memory.NewDeadLetterStore()
+	service := application.NewReceiptService(calculator, store,
+		application.WithReceiptQueue(memory.NewReceiptQueue(queueCapacity)),
+		application.WithDeadLetterStore(deadLetters),
+		application.WithRetryPolicy(application.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+	)
+	return service, deadLetters
+}
+
+func TestReceiptService_ProcessNextJob_RetriesTransientStoreErrors(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService, deadLetters := newAsyncReceiptService(mockPointsCalculator, mockReceiptStore, 10)
+	ctx := context.Background()
+
+	job, err := receiptService.EnqueueReceipt(ctx, local_mocks.MockReceipt)
+	assert.NoError(t, err)
+
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(50, nil)
+	mockReceiptStore.On("Save", mock.Anything, mock.Anything).Return("", &repository.TransientError{Err: fmt.Errorf("connection reset")}).Twice()
+	mockReceiptStore.On("Save", mock.Anything, mock.Anything).Return(job.ID, nil).Once()
+
+	assert.NoError(t, receiptService.ProcessNextJob(ctx))
+
+	mockReceiptStore.AssertNumberOfCalls(t, "Save", 3)
+	letters, err := deadLetters.List(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, letters)
+}
+
+func TestReceiptService_ProcessNextJob_RetryIsNotADuplicateOfItself(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	deadLetters := memory.NewDeadLetterStore()
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore,
+		application.WithReceiptQueue(memory.NewReceiptQueue(10)),
+		application.WithDeadLetterStore(deadLetters),
+		application.WithRetryPolicy(application.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+		application.WithDuplicatePolicy(application.DuplicatePolicyReject))
+	ctx := context.Background()
+
+	mockReceiptStore.On("FindByFingerprint", mock.Anything, mock.Anything).Return([]domain.Receipt{}, nil).Twice()
+	job, err := receiptService.EnqueueReceipt(ctx, local_mocks.MockReceipt)
+	assert.NoError(t, err)
+
+	// The first save is stored but its reply is lost, so the retry finds the receipt under the job's own ID
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(50, nil)
+	mockReceiptStore.On("SaveUnique", mock.Anything, mock.Anything).
+		Return("", "", &repository.TransientError{Err: fmt.Errorf("connection reset")}).Once()
+	mockReceiptStore.On("FindByFingerprint", mock.Anything, mock.Anything).Return([]domain.Receipt{{ID: job.ID}}, nil)
+	mockReceiptStore.On("SaveUnique", mock.Anything, mock.Anything).Return(job.ID, "", nil)
+
+	assert.NoError(t, receiptService.ProcessNextJob(ctx))
+
+	mockReceiptStore.AssertNumberOfCalls(t, "SaveUnique", 2)
+	_, err = deadLetters.Get(ctx, job.ID)
+	assert.ErrorIs(t, err, repository.ErrDeadLetterNotFound)
+}
+
+func TestReceiptService_ProcessNextJob_DeadLettersAfterLastAttempt(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService, _ := newAsyncReceiptService(mockPointsCalculator, mockReceiptStore, 10)
+	ctx := context.Background()
+
+	job, err := receiptService.EnqueueReceipt(ctx, local_mocks.MockReceipt)
+	assert.NoError(t, err)
+
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(50, nil)
+	mockReceiptStore.On("Save", mock.Anything, mock.Anything).Return("", &repository.TransientError{Err: fmt.Errorf("connection reset")})
+
+	assert.Error(t, receiptService.ProcessNextJob(ctx))
+
+	mockReceiptStore.AssertNumberOfCalls(t, "Save", 3)
+	letter, err := receiptService.GetDeadLetter(ctx, job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "failed to insert receipt: connection reset", letter.Reason)
+	assert.Equal(t, 3, letter.Attempts)
+	assert.Equal(t, local_mocks.MockReceipt.Retailer, letter.Receipt.Retailer)
+}
+
+func TestReceiptService_ProcessNextJob_StopsTrackingDeadLetteredJobs(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	queue := memory.NewReceiptQueue(10)
+	receiptService := application.NewReceiptService(mockPointsCalculator, new(local_mocks.MockReceiptStore),
+		application.WithReceiptQueue(queue), application.WithDeadLetterStore(memory.NewDeadLetterStore()))
+	ctx := context.Background()
+
+	job, err := receiptService.EnqueueReceipt(ctx, local_mocks.MockReceipt)
+	assert.NoError(t, err)
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(0, fmt.Errorf("invalid total"))
+	assert.Error(t, receiptService.ProcessNextJob(ctx))
+
+	_, err = queue.Job(ctx, job.ID)
+	assert.ErrorIs(t, err, repository.ErrJobNotFound)
+
+	// The dead letter reports the failure in the job's place
+	_, err = receiptService.GetPoints(ctx, job.ID)
+	assert.EqualError(t, err, "receipt processing failed: unable to process receipt: invalid total")
+}
+
+func TestReceiptService_ProcessNextJob_DoesNotRetryPermanentStoreErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"unmarked store error", fmt.Errorf("unable to encode receipt")},
+		{"repository sentinel", repository.ErrRevisionConflict},
+		{"deadline exceeded", &repository.TransientError{Err: context.DeadlineExceeded}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+			mockReceiptStore := new(local_mocks.MockReceiptStore)
+			receiptService, _ := newAsyncReceiptService(mockPointsCalculator, mockReceiptStore, 10)
+			ctx := context.Background()
+
+			job, err := receiptService.EnqueueReceipt(ctx, local_mocks.MockReceipt)
+			assert.NoError(t, err)
+
+			mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(50, nil)
+			mockReceiptStore.On("Save", mock.Anything, mock.Anything).Return("", tt.err)
+			assert.Error(t, receiptService.ProcessNextJob(ctx))
+
+			// The job fails on its first attempt instead of being retried
+			mockReceiptStore.AssertNumberOfCalls(t, "Save", 1)
+			letter, err := receiptService.GetDeadLetter(ctx, job.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, letter.Attempts)
+		})
+	}
+}
+
+func TestReceiptService_ProcessNextJob_DoesNotRetryPermanentFailures(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService, _ := newAsyncReceiptService(mockPointsCalculator, mockReceiptStore, 10)
+	ctx := context.Background()
+
+	job, err := receiptService.EnqueueReceipt(ctx, local_mocks.MockReceipt)
+	assert.NoError(t, err)
+
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(0, fmt.Errorf("invalid total"))
+	assert.Error(t, receiptService.ProcessNextJob(ctx))
+
+	mockPointsCalculator.AssertNumberOfCalls(t, "CalculatePoints", 1)
+	letter, err := receiptService.GetDeadLetter(ctx, job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, letter.Attempts)
+}
+
+func TestReceiptService_RequeueDeadLetter(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	receiptService, _ := newAsyncReceiptService(mockPointsCalculator, mockReceiptStore, 1)
+	ctx := context.Background()
+
+	job, err := receiptService.EnqueueReceipt(ctx, local_mocks.MockReceipt)
+	assert.NoError(t, err)
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(0, fmt.Errorf("rules unavailable")).Once()
+	assert.Error(t, receiptService.ProcessNextJob(ctx))
+
+	// A full queue leaves the dead letter in place
+	_, err = receiptService.EnqueueReceipt(ctx, local_mocks.MockReceipt)
+	assert.NoError(t, err)
+	_, err = receiptService.RequeueDeadLetter(ctx, job.ID)
+	assert.ErrorIs(t, err, repository.ErrQueueFull)
+	_, err = receiptService.GetDeadLetter(ctx, job.ID)
+	assert.NoError(t, err)
+
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(50, nil)
+	mockReceiptStore.On("Save", mock.Anything, mock.Anything).Return("", nil)
+	assert.NoError(t, receiptService.ProcessNextJob(ctx))
+
+	requeued, err := receiptService.RequeueDeadLetter(ctx, job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, job.ID, requeued.ID)
+	assert.Equal(t, domain.JobStatusPending, requeued.Status)
+
+	_, err = receiptService.GetDeadLetter(ctx, job.ID)
+	assert.ErrorIs(t, err, repository.ErrDeadLetterNotFound)
+	_, err = receiptService.RequeueDeadLetter(ctx, job.ID)
+	assert.ErrorIs(t, err, repository.ErrDeadLetterNotFound)
+
+	_, err = receiptService.GetPoints(ctx, job.ID)
+	assert.ErrorIs(t, err, portsHttp.ErrReceiptPending)
+	assert.NoError(t, receiptService.ProcessNextJob(ctx))
+	mockReceiptStore.AssertCalled(t, "Save", mock.Anything, mock.MatchedBy(func(r domain.Receipt) bool { return r.ID == job.ID }))
+}
+
+func TestReceiptService_PublishesEvents(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	mockPublisher := new(local_mocks.MockEventPublisher)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore, application.WithEventPublisher(mockPublisher))
+
+	receipt := local_mocks.MockReceipt
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(28, nil)
+	mockReceiptStore.On("Save", mock.Anything, mock.Anything).Return("123", nil)
+	mockReceiptStore.On("Find", mock.Anything, "123").Return(domain.Receipt{ID: "123", Points: 28, Revision: 1}, nil)
+	mockReceiptStore.On("Update", mock.Anything, mock.Anything, 1).Return(nil)
+	mockReceiptStore.On("Delete", mock.Anything, "123", mock.Anything).Return(nil)
+
+	var events []domain.ReceiptEvent
+	mockPublisher.On("Publish", mock.Anything).Run(func(args mock.Arguments) {
+		events = append(events, args.Get(0).(domain.ReceiptEvent))
+	})
+
+	_, err := receiptService.ProcessReceipt(context.Background(), receipt)
+	assert.NoError(t, err)
+	_, err = receiptService.UpdateReceipt(context.Background(), "123", receipt, 1)
+	assert.NoError(t, err)
+	_, err = receiptService.DeleteReceipt(context.Background(), "123", "alice", "duplicate")
+	assert.NoError(t, err)
+
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, domain.EventReceiptScored, events[0].Type)
+		assert.Equal(t, "123", events[0].ReceiptID)
+		assert.Equal(t, receipt.Retailer, events[0].Retailer)
+		assert.Equal(t, 28, events[0].Points)
+		assert.NotEmpty(t, events[0].ID)
+		assert.Equal(t, domain.EventReceiptCorrected, events[1].Type)
+		assert.Equal(t, domain.EventReceiptDeleted, events[2].Type)
+		assert.NotEqual(t, events[0].ID, events[1].ID)
+	}
+}
+
+func TestReceiptService_DoesNotPublishFailures(t *testing.T) {
+	mockPointsCalculator := new(local_mocks.MockPointsCalculator)
+	mockReceiptStore := new(local_mocks.MockReceiptStore)
+	mockPublisher := new(local_mocks.MockEventPublisher)
+	receiptService := application.NewReceiptService(mockPointsCalculator, mockReceiptStore, application.WithEventPublisher(mockPublisher))
+
+	mockPointsCalculator.On("CalculatePoints", mock.Anything, mock.Anything).Return(28, nil)
+	mockReceiptStore.On("Save", mock.Anything, mock.Anything).Return("", fmt.Errorf("connection reset"))
+
+	_, err := receiptService.ProcessReceipt(context.Background(), local_mocks.MockReceipt)
+
+	assert.Error(t, err)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything)
+}
@@ -0,0 +1,25 @@
+package application_test
+
+import (
+	"go-receipt-processor/internal/application"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_BackoffDoublesUpToMax(t *testing.T) {
+	policy := application.RetryPolicy{MaxAttempts: 10, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.Backoff(2))
+	assert.Equal(t, 800*time.Millisecond, policy.Backoff(4))
+	assert.Equal(t, time.Second, policy.Backoff(5))
+	assert.Equal(t, time.Second, policy.Backoff(60))
+}
+
+func TestRetryPolicy_UnboundedBackoff(t *testing.T) {
+	policy := application.RetryPolicy{InitialBackoff: time.Millisecond}
+
+	assert.Equal(t, 1024*time.Millisecond, policy.Backoff(11))
+}
@@ -0,0 +1,337 @@
+package bolt_test
+
+import (
+	"context"
+	"go-receipt-processor/internal/adapters/bolt"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStore opens a bolt store in a temporary directory that is cleaned up after the test
+func newStore(t *testing.T) *bolt.ReceiptStoreImpl {
+	store, err := bolt.NewReceiptStore(filepath.Join(t.TempDir(), "receipts.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func sampleReceipt(retailer, purchaseDate string) domain.Receipt {
+	return domain.Receipt{
+		Retailer:     retailer,
+		PurchaseDate: purchaseDate,
+		PurchaseTime: "14:30",
+		Items: []domain.Item{
+			{ShortDescription: "Item 1", Price: "50.00"},
+			{ShortDescription: "Item 2", Price: "50.00"},
+		},
+		Total:          "100.00",
+		Points:         100,
+		ReceivedAt:     time.Date(2024, 11, 29, 14, 31, 0, 0, time.UTC),
+		ScoringVersion: "v1",
+	}
+}
+
+func TestSaveReceipt_Success(t *testing.T) {
+	store := newStore(t)
+	receipt := sampleReceipt("Store A", "2024-11-29")
+
+	// Save the receipt and read it back
+	receiptID, err := store.Save(context.Background(), receipt)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, receiptID)
+
+	savedReceipt, err := store.Find(context.Background(), receiptID)
+	assert.NoError(t, err)
+	assert.Equal(t, receiptID, savedReceipt.ID)
+	assert.Equal(t, receipt.Retailer, savedReceipt.Retailer)
+	assert.Equal(t, receipt.Items, savedReceipt.Items)
+	assert.Equal(t, receipt.Total, savedReceipt.Total)
+	assert.Equal(t, receipt.Points, savedReceipt.Points)
+	assert.True(t, receipt.ReceivedAt.Equal(savedReceipt.ReceivedAt))
+	assert.Equal(t, receipt.ScoringVersion, savedReceipt.ScoringVersion)
+}
+
+func TestFindReceipt_NotFound(t *testing.T) {
+	store := newStore(t)
+
+	_, err := store.Find(context.Background(), "nonexistent-id")
+
+	assert.ErrorIs(t, err, repository.ErrReceiptNotFound)
+}
+
+func TestFindByRetailer_UsesNormalizedIndex(t *testing.T) {
+	store := newStore(t)
+
+	idA, err := store.Save(context.Background(), sampleReceipt("Target", "2024-11-29"))
+	require.NoError(t, err)
+	idB, err := store.Save(context.Background(), sampleReceipt("  TARGET ", "2024-11-30"))
+	require.NoError(t, err)
+	_, err = store.Save(context.Background(), sampleReceipt("Targetted", "2024-11-30"))
+	require.NoError(t, err)
+
+	receipts, err := store.FindByRetailer(context.Background(), "target")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{idA, idB}, []string{receipts[0].ID, receipts[1].ID})
+	assert.Len(t, receipts, 2)
+}
+
+func TestFindByPurchaseDate(t *testing.T) {
+	store := newStore(t)
+
+	_, err := store.Save(context.Background(), sampleReceipt("Store A", "2024-11-29"))
+	require.NoError(t, err)
+	idB, err := store.Save(context.Background(), sampleReceipt("Store B", "2024-11-30"))
+	require.NoError(t, err)
+
+	receipts, err := store.FindByPurchaseDate(context.Background(), "2024-11-30")
+	assert.NoError(t, err)
+	assert.Len(t, receipts, 1)
+	assert.Equal(t, idB, receipts[0].ID)
+
+	receipts, err = store.FindByPurchaseDate(context.Background(), "2020-01-01")
+	assert.NoError(t, err)
+	assert.Empty(t, receipts)
+}
+
+func TestReceiptsPersistAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "receipts.db")
+
+	store, err := bolt.NewReceiptStore(path)
+	require.NoError(t, err)
+	receiptID, err := store.Save(context.Background(), sampleReceipt("Store A", "2024-11-29"))
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	reopened, err := bolt.NewReceiptStore(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	savedReceipt, err := reopened.Find(context.Background(), receiptID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Store A", savedReceipt.Retailer)
+}
+
+func TestBackupToFile_WhileOnline(t *testing.T) {
+	store := newStore(t)
+	receiptID, err := store.Save(context.Background(), sampleReceipt("Store A", "2024-11-29"))
+	require.NoError(t, err)
+
+	// Take a backup without closing the live store
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	written, err := store.BackupToFile(context.Background(), backupPath)
+	assert.NoError(t, err)
+	assert.Greater(t, written, int64(0))
+
+	// The live store keeps accepting writes after the backup
+	_, err = store.Save(context.Background(), sampleReceipt("Store B", "2024-11-30"))
+	assert.NoError(t, err)
+
+	// The backup is a usable database containing the receipt saved before it was taken
+	backup, err := bolt.NewReceiptStore(backupPath)
+	require.NoError(t, err)
+	defer backup.Close()
+
+	savedReceipt, err := backup.Find(context.Background(), receiptID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Store A", savedReceipt.Retailer)
+
+	receipts, err := backup.FindByRetailer(context.Background(), "Store B")
+	assert.NoError(t, err)
+	assert.Empty(t, receipts)
+}
+
+func TestQuery_UsesIndexesAndPages(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	idA, err := store.Save(ctx, sampleReceipt("Target", "2024-11-29"))
+	require.NoError(t, err)
+	idB, err := store.Save(ctx, sampleReceipt("Walgreens", "2024-11-30"))
+	require.NoError(t, err)
+	idC, err := store.Save(ctx, sampleReceipt("target", "2024-12-01"))
+	require.NoError(t, err)
+
+	// Retailer index
+	page, err := store.Query(ctx, repository.ReceiptQuery{Retailer: "TARGET"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{idA, idC}, receiptIDs(page.Receipts))
+
+	// Date index range scan
+	page, err = store.Query(ctx, repository.ReceiptQuery{PurchaseDateFrom: "2024-11-30", PurchaseDateTo: "2024-11-30"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{idB}, receiptIDs(page.Receipts))
+
+	// Full scan with descending sort and pagination
+	query := repository.ReceiptQuery{SortDescending: true, Limit: 2}
+	page, err = store.Query(ctx, query)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{idC, idB}, receiptIDs(page.Receipts))
+
+	query.Cursor = page.NextCursor
+	page, err = store.Query(ctx, query)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{idA}, receiptIDs(page.Receipts))
+	assert.Empty(t, page.NextCursor)
+}
+
+func receiptIDs(receipts []domain.Receipt) []string {
+	ids := make([]string, len(receipts))
+	for i, receipt := range receipts {
+		ids[i] = receipt.ID
+	}
+	return ids
+}
+
+func TestDelete_RemovesIndexesAndLeavesTombstone(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	id, err := store.Save(ctx, sampleReceipt("Target", "2024-11-29"))
+	require.NoError(t, err)
+
+	require.NoError(t, store.Delete(ctx, id, domain.Tombstone{DeletedBy: "alice", Reason: "duplicate"}))
+
+	_, err = store.Find(ctx, id)
+	assert.ErrorIs(t, err, repository.ErrReceiptDeleted)
+	assert.ErrorIs(t, store.Delete(ctx, "nonexistent-id", domain.Tombstone{}), repository.ErrReceiptNotFound)
+
+	byRetailer, err := store.FindByRetailer(ctx, "Target")
+	require.NoError(t, err)
+	assert.Empty(t, byRetailer)
+
+	byDate, err := store.FindByPurchaseDate(ctx, "2024-11-29")
+	require.NoError(t, err)
+	assert.Empty(t, byDate)
+}
+
+func TestErase_ByRetailer(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	targetID1, err := store.Save(ctx, sampleReceipt("Target", "2024-11-29"))
+	require.NoError(t, err)
+	targetID2, err := store.Save(ctx, sampleReceipt(" target ", "2024-11-30"))
+	require.NoError(t, err)
+	walmartID, err := store.Save(ctx, sampleReceipt("Walmart", "2024-11-29"))
+	require.NoError(t, err)
+
+	erased, err := store.Erase(ctx, repository.ErasureSelector{Retailer: "TARGET"}, domain.Tombstone{DeletedBy: "dpo", Reason: "gdpr"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{targetID1, targetID2}, erased)
+
+	_, err = store.Find(ctx, targetID2)
+	assert.ErrorIs(t, err, repository.ErrReceiptDeleted)
+	_, err = store.Find(ctx, walmartID)
+	assert.NoError(t, err)
+}
+
+func TestUpdate_KeepsRevisionsAndReindexes(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	original := sampleReceipt("Target", "2024-11-29")
+	original.Revision = 1
+	id, err := store.Save(ctx, original)
+	require.NoError(t, err)
+
+	corrected := original
+	corrected.ID = id
+	corrected.Retailer = "Walmart"
+	corrected.Revision = 2
+	require.NoError(t, store.Update(ctx, corrected, 1))
+	assert.ErrorIs(t, store.Update(ctx, corrected, 1), repository.ErrRevisionConflict)
+	assert.ErrorIs(t, store.Update(ctx, domain.Receipt{ID: "nonexistent-id"}, 1), repository.ErrReceiptNotFound)
+
+	byOldRetailer, err := store.FindByRetailer(ctx, "Target")
+	require.NoError(t, err)
+	assert.Empty(t, byOldRetailer)
+	byNewRetailer, err := store.FindByRetailer(ctx, "Walmart")
+	require.NoError(t, err)
+	assert.Equal(t, []string{id}, receiptIDs(byNewRetailer))
+
+	revisions, err := store.Revisions(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, "Target", revisions[0].Retailer)
+
+	// Deleting the receipt also discards its history
+	require.NoError(t, store.Delete(ctx, id, domain.Tombstone{DeletedBy: "alice", Reason: "test"}))
+	_, err = store.Revisions(ctx, id)
+	assert.ErrorIs(t, err, repository.ErrReceiptDeleted)
+}
+
+func TestFindByFingerprint_UsesIndex(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	receipt := sampleReceipt("Target", "2024-11-29")
+	receipt.Fingerprint = receipt.CanonicalFingerprint()
+	first, err := store.Save(ctx, receipt)
+	require.NoError(t, err)
+	second, err := store.Save(ctx, receipt)
+	require.NoError(t, err)
+	_, err = store.Save(ctx, sampleReceipt("Walmart", "2024-11-29"))
+	require.NoError(t, err)
+
+	matches, err := store.FindByFingerprint(ctx, receipt.Fingerprint)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{first, second}, receiptIDs(matches))
+
+	require.NoError(t, store.Delete(ctx, first, domain.Tombstone{}))
+	matches, err = store.FindByFingerprint(ctx, receipt.Fingerprint)
+	require.NoError(t, err)
+	assert.Equal(t, []string{second}, receiptIDs(matches))
+}
+
+func TestSaveUnique_StoresOneOfConcurrentDuplicates(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	receipt := sampleReceipt("Target", "2024-11-29")
+	receipt.Fingerprint = receipt.CanonicalFingerprint()
+
+	const submissions = 8
+	receiptIDs, originalIDs := make([]string, submissions), make([]string, submissions)
+	var wg sync.WaitGroup
+	for i := 0; i < submissions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			receiptIDs[i], originalIDs[i], err = store.SaveUnique(ctx, receipt)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	matches, err := store.FindByFingerprint(ctx, receipt.Fingerprint)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	original := matches[0].ID
+	saved := 0
+	for i := range receiptIDs {
+		if receiptIDs[i] != "" {
+			saved++
+			assert.Equal(t, original, receiptIDs[i])
+			assert.Empty(t, originalIDs[i])
+		} else {
+			assert.Equal(t, original, originalIDs[i])
+		}
+	}
+	assert.Equal(t, 1, saved)
+
+	// Saving the stored receipt again under its own ID, as a retry does, is not a duplicate of itself
+	retried := receipt
+	retried.ID = original
+	receiptID, originalID, err := store.SaveUnique(ctx, retried)
+	require.NoError(t, err)
+	assert.Equal(t, original, receiptID)
+	assert.Empty(t, originalID)
+}
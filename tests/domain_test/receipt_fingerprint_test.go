@@ -0,0 +1,53 @@
+package domain_test
+
+import (
+	"go-receipt-processor/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fingerprintReceipt = domain.Receipt{
+	Retailer:     "M&M Corner Market",
+	PurchaseDate: "2022-03-20",
+	PurchaseTime: "14:33",
+	Items: []domain.Item{
+		{ShortDescription: "Gatorade", Price: "2.25"},
+		{ShortDescription: "Doritos Nacho Cheese", Price: "3.50"},
+	},
+	Total: "5.75",
+}
+
+func TestCanonicalFingerprint_IgnoresFormatting(t *testing.T) {
+	resubmitted := domain.Receipt{
+		Retailer:     "  m&m   CORNER market ",
+		PurchaseDate: "2022-03-20 ",
+		PurchaseTime: " 14:33",
+		Items: []domain.Item{
+			{ShortDescription: "DORITOS  nacho cheese", Price: "3.5"},
+			{ShortDescription: " gatorade", Price: "2.25"},
+		},
+		Total:      "5.750",
+		CustomerID: "someone-else",
+		Points:     99,
+	}
+
+	assert.Len(t, fingerprintReceipt.CanonicalFingerprint(), 64)
+	assert.Equal(t, fingerprintReceipt.CanonicalFingerprint(), resubmitted.CanonicalFingerprint())
+}
+
+func TestCanonicalFingerprint_DistinguishesReceipts(t *testing.T) {
+	base := fingerprintReceipt.CanonicalFingerprint()
+
+	otherTime := fingerprintReceipt
+	otherTime.PurchaseTime = "14:34"
+	assert.NotEqual(t, base, otherTime.CanonicalFingerprint())
+
+	otherTotal := fingerprintReceipt
+	otherTotal.Total = "5.76"
+	assert.NotEqual(t, base, otherTotal.CanonicalFingerprint())
+
+	extraItem := fingerprintReceipt
+	extraItem.Items = append([]domain.Item{{ShortDescription: "Gatorade", Price: "2.25"}}, fingerprintReceipt.Items...)
+	assert.NotEqual(t, base, extraItem.CanonicalFingerprint())
+}
@@ -0,0 +1,17 @@
+package local_mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBackupStore is a mock of the BackupStore interface for unit testing
+type MockBackupStore struct {
+	mock.Mock
+}
+
+func (m *MockBackupStore) BackupToFile(ctx context.Context, path string) (int64, error) {
+	args := m.Called(ctx, path)
+	return args.Get(0).(int64), args.Error(1)
+}
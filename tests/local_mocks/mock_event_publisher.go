@@ -0,0 +1,16 @@
+package local_mocks
+
+import (
+	"go-receipt-processor/internal/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEventPublisher is a mock of the EventPublisher interface for unit testing
+type MockEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockEventPublisher) Publish(event domain.ReceiptEvent) {
+	m.Called(event)
+}
@@ -0,0 +1,18 @@
+package local_mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockHealthChecker is a mock of the HealthChecker interface for unit testing
+type MockHealthChecker struct {
+	mock.Mock
+}
+
+func (m *MockHealthChecker) CheckHealth(ctx context.Context) (map[string]int64, error) {
+	args := m.Called(ctx)
+	stats, _ := args.Get(0).(map[string]int64)
+	return stats, args.Error(1)
+}
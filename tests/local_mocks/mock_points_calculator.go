@@ -1,6 +1,7 @@
 package local_mocks
 
 import (
+	"context"
 	"go-receipt-processor/internal/domain"
 
 	"github.com/stretchr/testify/mock"
@@ -11,7 +12,13 @@ type MockPointsCalculator struct {
 	mock.Mock
 }
 
-func (m *MockPointsCalculator) CalculatePoints(receipt domain.Receipt) (int, error) {
-	args := m.Called(receipt)
+func (m *MockPointsCalculator) CalculatePoints(ctx context.Context, receipt domain.Receipt) (int, error) {
+	args := m.Called(ctx, receipt)
 	return args.Int(0), args.Error(1)
 }
+
+func (m *MockPointsCalculator) Breakdown(ctx context.Context, receipt domain.Receipt) ([]domain.RulePoints, error) {
+	args := m.Called(ctx, receipt)
+	breakdown, _ := args.Get(0).([]domain.RulePoints)
+	return breakdown, args.Error(1)
+}
@@ -1,7 +1,10 @@
 package local_mocks
 
 import (
+	"context"
 	"go-receipt-processor/internal/domain"
+	http "go-receipt-processor/internal/ports/core"
+	"go-receipt-processor/internal/ports/repository"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -11,12 +14,75 @@ type MockReceiptService struct {
 	mock.Mock
 }
 
-func (m *MockReceiptService) GetPoints(receiptID string) (int, error) {
-	args := m.Called(receiptID)
+func (m *MockReceiptService) GetPoints(ctx context.Context, receiptID string) (int, error) {
+	args := m.Called(ctx, receiptID)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockReceiptService) ProcessReceipt(receipt domain.Receipt) (string, error) {
-	args := m.Called(receipt)
+func (m *MockReceiptService) GetReceipt(ctx context.Context, receiptID string) (domain.Receipt, error) {
+	args := m.Called(ctx, receiptID)
+	return args.Get(0).(domain.Receipt), args.Error(1)
+}
+
+func (m *MockReceiptService) ProcessReceipt(ctx context.Context, receipt domain.Receipt) (string, error) {
+	args := m.Called(ctx, receipt)
 	return args.String(0), args.Error(1)
 }
+
+func (m *MockReceiptService) ListReceipts(ctx context.Context, query repository.ReceiptQuery) (repository.ReceiptPage, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).(repository.ReceiptPage), args.Error(1)
+}
+
+func (m *MockReceiptService) DeleteReceipt(ctx context.Context, id string, actor string, reason string) (domain.Tombstone, error) {
+	args := m.Called(ctx, id, actor, reason)
+	return args.Get(0).(domain.Tombstone), args.Error(1)
+}
+
+func (m *MockReceiptService) EraseReceipts(ctx context.Context, selector repository.ErasureSelector, actor string, reason string) ([]string, error) {
+	args := m.Called(ctx, selector, actor, reason)
+	erased, _ := args.Get(0).([]string)
+	return erased, args.Error(1)
+}
+
+func (m *MockReceiptService) UpdateReceipt(ctx context.Context, id string, receipt domain.Receipt, expectedRevision int) (domain.Receipt, error) {
+	args := m.Called(ctx, id, receipt, expectedRevision)
+	return args.Get(0).(domain.Receipt), args.Error(1)
+}
+
+func (m *MockReceiptService) ListRevisions(ctx context.Context, id string) ([]domain.Receipt, error) {
+	args := m.Called(ctx, id)
+	revisions, _ := args.Get(0).([]domain.Receipt)
+	return revisions, args.Error(1)
+}
+
+func (m *MockReceiptService) ProcessReceipts(ctx context.Context, receipts []domain.Receipt) []http.ProcessResult {
+	args := m.Called(ctx, receipts)
+	return args.Get(0).([]http.ProcessResult)
+}
+
+func (m *MockReceiptService) EnqueueReceipt(ctx context.Context, receipt domain.Receipt) (domain.ReceiptJob, error) {
+	args := m.Called(ctx, receipt)
+	return args.Get(0).(domain.ReceiptJob), args.Error(1)
+}
+
+func (m *MockReceiptService) ProcessNextJob(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockReceiptService) ListDeadLetters(ctx context.Context) ([]domain.DeadLetter, error) {
+	args := m.Called(ctx)
+	letters, _ := args.Get(0).([]domain.DeadLetter)
+	return letters, args.Error(1)
+}
+
+func (m *MockReceiptService) GetDeadLetter(ctx context.Context, id string) (domain.DeadLetter, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.DeadLetter), args.Error(1)
+}
+
+func (m *MockReceiptService) RequeueDeadLetter(ctx context.Context, id string) (domain.ReceiptJob, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.ReceiptJob), args.Error(1)
+}
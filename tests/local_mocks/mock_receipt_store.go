@@ -1,7 +1,9 @@
 package local_mocks
 
 import (
+	"context"
 	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -11,12 +13,50 @@ type MockReceiptStore struct {
 	mock.Mock
 }
 
-func (m *MockReceiptStore) Save(receipt domain.Receipt) (string, error) {
-	args := m.Called(receipt)
+func (m *MockReceiptStore) Save(ctx context.Context, receipt domain.Receipt) (string, error) {
+	args := m.Called(ctx, receipt)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockReceiptStore) Find(id string) (domain.Receipt, error) {
-	args := m.Called(id)
+func (m *MockReceiptStore) SaveUnique(ctx context.Context, receipt domain.Receipt) (string, string, error) {
+	args := m.Called(ctx, receipt)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockReceiptStore) Find(ctx context.Context, id string) (domain.Receipt, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(domain.Receipt), args.Error(1)
 }
+
+func (m *MockReceiptStore) Query(ctx context.Context, query repository.ReceiptQuery) (repository.ReceiptPage, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).(repository.ReceiptPage), args.Error(1)
+}
+
+func (m *MockReceiptStore) FindByFingerprint(ctx context.Context, fingerprint string) ([]domain.Receipt, error) {
+	args := m.Called(ctx, fingerprint)
+	receipts, _ := args.Get(0).([]domain.Receipt)
+	return receipts, args.Error(1)
+}
+
+func (m *MockReceiptStore) Delete(ctx context.Context, id string, tombstone domain.Tombstone) error {
+	args := m.Called(ctx, id, tombstone)
+	return args.Error(0)
+}
+
+func (m *MockReceiptStore) Erase(ctx context.Context, selector repository.ErasureSelector, audit domain.Tombstone) ([]string, error) {
+	args := m.Called(ctx, selector, audit)
+	erased, _ := args.Get(0).([]string)
+	return erased, args.Error(1)
+}
+
+func (m *MockReceiptStore) Update(ctx context.Context, receipt domain.Receipt, expectedRevision int) error {
+	args := m.Called(ctx, receipt, expectedRevision)
+	return args.Error(0)
+}
+
+func (m *MockReceiptStore) Revisions(ctx context.Context, id string) ([]domain.Receipt, error) {
+	args := m.Called(ctx, id)
+	revisions, _ := args.Get(0).([]domain.Receipt)
+	return revisions, args.Error(1)
+}
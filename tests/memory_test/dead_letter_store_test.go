@@ -0,0 +1,37 @@
+package memory_test
+
+import (
+	"context"
+	"go-receipt-processor/internal/adapters/memory"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetterStore_AddListGetRemove(t *testing.T) {
+	store := memory.NewDeadLetterStore()
+	ctx := context.Background()
+	failedAt := time.Date(2024, 11, 29, 15, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.Add(ctx, domain.DeadLetter{ID: "later", Reason: "timeout", FailedAt: failedAt.Add(time.Minute)}))
+	require.NoError(t, store.Add(ctx, domain.DeadLetter{ID: "earlier", Reason: "invalid total", FailedAt: failedAt}))
+
+	letters, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, letters, 2)
+	assert.Equal(t, "earlier", letters[0].ID)
+	assert.Equal(t, "later", letters[1].ID)
+
+	letter, err := store.Get(ctx, "later")
+	require.NoError(t, err)
+	assert.Equal(t, "timeout", letter.Reason)
+
+	require.NoError(t, store.Remove(ctx, "later"))
+	_, err = store.Get(ctx, "later")
+	assert.ErrorIs(t, err, repository.ErrDeadLetterNotFound)
+	assert.ErrorIs(t, store.Remove(ctx, "later"), repository.ErrDeadLetterNotFound)
+}
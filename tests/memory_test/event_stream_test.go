@@ -0,0 +1,86 @@
+package memory_test
+
+import (
+	"fmt"
+	"go-receipt-processor/internal/adapters/memory"
+	"go-receipt-processor/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventIDs returns the IDs of events, in order
+func eventIDs(events []domain.ReceiptEvent) []string {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+func TestEventStream_DeliversToSubscribers(t *testing.T) {
+	stream := memory.NewEventStream(10)
+	replay, events, cancel := stream.Subscribe("")
+	defer cancel()
+	assert.Empty(t, replay)
+
+	stream.Publish(domain.ReceiptEvent{ID: "1", Type: domain.EventReceiptScored})
+
+	event := <-events
+	assert.Equal(t, "1", event.ID)
+}
+
+func TestEventStream_ReplaysAfterLastEventID(t *testing.T) {
+	stream := memory.NewEventStream(3)
+	for i := 1; i <= 5; i++ {
+		stream.Publish(domain.ReceiptEvent{ID: fmt.Sprint(i)})
+	}
+
+	replay, _, cancel := stream.Subscribe("4")
+	cancel()
+	assert.Equal(t, []string{"5"}, eventIDs(replay))
+
+	replay, _, cancel = stream.Subscribe("5")
+	cancel()
+	assert.Empty(t, replay)
+
+	// Event 1 has left the buffer, so everything still buffered is replayed
+	replay, _, cancel = stream.Subscribe("1")
+	cancel()
+	assert.Equal(t, []string{"3", "4", "5"}, eventIDs(replay))
+}
+
+func TestEventStream_DisconnectsSlowSubscribers(t *testing.T) {
+	stream := memory.NewEventStream(1000)
+	_, slow, cancelSlow := stream.Subscribe("")
+	defer cancelSlow()
+
+	// Nobody reads from slow, so Publish must drop it rather than block
+	for i := 0; i < 500; i++ {
+		stream.Publish(domain.ReceiptEvent{ID: fmt.Sprint(i)})
+	}
+
+	received := 0
+	for range slow {
+		received++
+	}
+	assert.Less(t, received, 500)
+
+	replay, _, cancel := stream.Subscribe(fmt.Sprint(received - 1))
+	defer cancel()
+	require.NotEmpty(t, replay)
+	assert.Equal(t, fmt.Sprint(received), replay[0].ID)
+}
+
+func TestEventStream_CancelClosesChannel(t *testing.T) {
+	stream := memory.NewEventStream(10)
+	_, events, cancel := stream.Subscribe("")
+
+	cancel()
+	cancel()
+
+	_, ok := <-events
+	assert.False(t, ok)
+	stream.Publish(domain.ReceiptEvent{ID: "1"})
+}
@@ -0,0 +1,52 @@
+package memory_test
+
+import (
+	"context"
+	"go-receipt-processor/internal/adapters/memory"
+	"go-receipt-processor/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyStore_ReserveCompleteAndReplay(t *testing.T) {
+	store := memory.NewIdempotencyStore()
+	ctx := context.Background()
+	record := domain.IdempotencyRecord{Key: "key-1", RequestHash: "abc", ExpiresAt: time.Now().Add(time.Hour)}
+
+	_, reserved, err := store.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	existing, reserved, err := store.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.False(t, existing.Completed)
+
+	record.StatusCode = 200
+	record.Body = []byte(`{"id":"1"}`)
+	assert.NoError(t, store.Complete(ctx, record))
+
+	existing, reserved, err = store.Reserve(ctx, record)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.True(t, existing.Completed)
+	assert.Equal(t, `{"id":"1"}`, string(existing.Body))
+}
+
+func TestIdempotencyStore_ExpiredAndReleasedKeysCanBeReused(t *testing.T) {
+	store := memory.NewIdempotencyStore()
+	ctx := context.Background()
+
+	_, reserved, _ := store.Reserve(ctx, domain.IdempotencyRecord{Key: "expired", ExpiresAt: time.Now().Add(-time.Second)})
+	assert.True(t, reserved)
+	_, reserved, _ = store.Reserve(ctx, domain.IdempotencyRecord{Key: "expired", ExpiresAt: time.Now().Add(time.Hour)})
+	assert.True(t, reserved)
+
+	_, reserved, _ = store.Reserve(ctx, domain.IdempotencyRecord{Key: "released", ExpiresAt: time.Now().Add(time.Hour)})
+	assert.True(t, reserved)
+	assert.NoError(t, store.Release(ctx, "released"))
+	_, reserved, _ = store.Reserve(ctx, domain.IdempotencyRecord{Key: "released", ExpiresAt: time.Now().Add(time.Hour)})
+	assert.True(t, reserved)
+}
@@ -0,0 +1,86 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"go-receipt-processor/internal/adapters/memory"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiptQueue_BoundedCapacity(t *testing.T) {
+	queue := memory.NewReceiptQueue(2)
+	ctx := context.Background()
+
+	require.NoError(t, queue.Enqueue(ctx, domain.ReceiptJob{ID: "job-1"}))
+	require.NoError(t, queue.Enqueue(ctx, domain.ReceiptJob{ID: "job-2"}))
+	assert.ErrorIs(t, queue.Enqueue(ctx, domain.ReceiptJob{ID: "job-3"}), repository.ErrQueueFull)
+
+	_, err := queue.Job(ctx, "job-3")
+	assert.ErrorIs(t, err, repository.ErrJobNotFound)
+
+	// Taking a job frees its slot
+	_, err = queue.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.NoError(t, queue.Enqueue(ctx, domain.ReceiptJob{ID: "job-3"}))
+}
+
+func TestReceiptQueue_TracksJobUntilFinished(t *testing.T) {
+	queue := memory.NewReceiptQueue(10)
+	ctx := context.Background()
+
+	require.NoError(t, queue.Enqueue(ctx, domain.ReceiptJob{ID: "ok", Receipt: domain.Receipt{Retailer: "Target"}}))
+	require.NoError(t, queue.Enqueue(ctx, domain.ReceiptJob{ID: "broken"}))
+
+	job, err := queue.Job(ctx, "ok")
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusPending, job.Status)
+
+	job, err = queue.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", job.ID)
+	assert.Equal(t, "Target", job.Receipt.Retailer)
+	assert.Equal(t, domain.JobStatusProcessing, job.Status)
+
+	require.NoError(t, queue.Finish(ctx, "ok", nil))
+	_, err = queue.Job(ctx, "ok")
+	assert.ErrorIs(t, err, repository.ErrJobNotFound)
+
+	_, err = queue.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NoError(t, queue.Finish(ctx, "broken", errors.New("store unavailable")))
+	job, err = queue.Job(ctx, "broken")
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusFailed, job.Status)
+	assert.Equal(t, "store unavailable", job.Error)
+
+	assert.ErrorIs(t, queue.Finish(ctx, "unknown", nil), repository.ErrJobNotFound)
+}
+
+func TestReceiptQueue_Remove(t *testing.T) {
+	queue := memory.NewReceiptQueue(10)
+	ctx := context.Background()
+
+	require.NoError(t, queue.Enqueue(ctx, domain.ReceiptJob{ID: "broken"}))
+	_, err := queue.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NoError(t, queue.Finish(ctx, "broken", errors.New("store unavailable")))
+
+	require.NoError(t, queue.Remove(ctx, "broken"))
+	_, err = queue.Job(ctx, "broken")
+	assert.ErrorIs(t, err, repository.ErrJobNotFound)
+	assert.ErrorIs(t, queue.Remove(ctx, "broken"), repository.ErrJobNotFound)
+}
+
+func TestReceiptQueue_DequeueStopsWithContext(t *testing.T) {
+	queue := memory.NewReceiptQueue(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := queue.Dequeue(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
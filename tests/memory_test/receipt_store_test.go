@@ -1,9 +1,14 @@
 package memory_test
 
 import (
+	"context"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go-receipt-processor/internal/adapters/memory"
 	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"sync"
 	"testing"
 )
 
@@ -25,14 +30,14 @@ func TestSaveReceipt_Success(t *testing.T) {
 	}
 
 	// Save the receipt in the store
-	receiptID, err := store.Save(receipt)
+	receiptID, err := store.Save(context.Background(), receipt)
 
 	// Assert that there is no error and that the ID is returned
 	assert.NoError(t, err)
 	assert.NotEmpty(t, receiptID)
 
 	// Retrieve the receipt by ID and assert that it matches the saved receipt
-	savedReceipt, err := store.Find(receiptID)
+	savedReceipt, err := store.Find(context.Background(), receiptID)
 	assert.NoError(t, err)
 	assert.Equal(t, receipt.Retailer, savedReceipt.Retailer)
 	assert.Equal(t, receipt.Total, savedReceipt.Total)
@@ -56,11 +61,11 @@ func TestFindReceipt_Success(t *testing.T) {
 		Points: 100,
 	}
 
-	receiptID, err := store.Save(receipt)
+	receiptID, err := store.Save(context.Background(), receipt)
 	assert.NoError(t, err)
 
 	// Retrieve the saved receipt
-	savedReceipt, err := store.Find(receiptID)
+	savedReceipt, err := store.Find(context.Background(), receiptID)
 	assert.NoError(t, err)
 	assert.Equal(t, receipt.Retailer, savedReceipt.Retailer)
 	assert.Equal(t, receipt.Total, savedReceipt.Total)
@@ -73,11 +78,10 @@ func TestFindReceipt_Failure(t *testing.T) {
 
 	// Attempt to find a non-existent receipt
 	nonExistentID := "nonexistent-id"
-	_, err := store.Find(nonExistentID)
+	_, err := store.Find(context.Background(), nonExistentID)
 
-	// Assert that no error is returned (as per the current implementation)
-	// (You can modify this behavior in the future to return an error if needed)
-	assert.NoError(t, err)
+	// Assert that the store reports the receipt as missing, like every other adapter
+	assert.ErrorIs(t, err, repository.ErrReceiptNotFound)
 }
 
 func TestSaveMultipleReceipts_UniqueIDs(t *testing.T) {
@@ -109,22 +113,231 @@ func TestSaveMultipleReceipts_UniqueIDs(t *testing.T) {
 	}
 
 	// Save the receipts
-	receiptID1, err := store.Save(receipt1)
+	receiptID1, err := store.Save(context.Background(), receipt1)
 	assert.NoError(t, err)
-	receiptID2, err := store.Save(receipt2)
+	receiptID2, err := store.Save(context.Background(), receipt2)
 	assert.NoError(t, err)
 
 	// Assert that the IDs are unique
 	assert.NotEqual(t, receiptID1, receiptID2)
 
 	// Retrieve both receipts and assert that they match the saved data
-	savedReceipt1, err := store.Find(receiptID1)
+	savedReceipt1, err := store.Find(context.Background(), receiptID1)
 	assert.NoError(t, err)
 	assert.Equal(t, receipt1.Retailer, savedReceipt1.Retailer)
 	assert.Equal(t, receipt1.Total, savedReceipt1.Total)
 
-	savedReceipt2, err := store.Find(receiptID2)
+	savedReceipt2, err := store.Find(context.Background(), receiptID2)
 	assert.NoError(t, err)
 	assert.Equal(t, receipt2.Retailer, savedReceipt2.Retailer)
 	assert.Equal(t, receipt2.Total, savedReceipt2.Total)
 }
+
+func TestSaveReceipt_CancelledContext(t *testing.T) {
+	store := memory.NewReceiptStore()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := store.Save(ctx, domain.Receipt{Retailer: "Store E"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestQueryReceipts_FiltersSortsAndPages(t *testing.T) {
+	store := memory.NewReceiptStore()
+	ctx := context.Background()
+
+	// The store is shared by every test, so use a retailer no other test saves
+	var savedIDs []string
+	for _, date := range []string{"2024-03-03", "2024-03-01", "2024-03-02"} {
+		id, err := store.Save(ctx, domain.Receipt{Retailer: "Query Mart", PurchaseDate: date, PurchaseTime: "10:00", Total: "1.00"})
+		assert.NoError(t, err)
+		savedIDs = append(savedIDs, id)
+	}
+
+	query := repository.ReceiptQuery{Retailer: "query mart", Limit: 2}
+	first, err := store.Query(ctx, query)
+	assert.NoError(t, err)
+	assert.Len(t, first.Receipts, 2)
+	assert.Equal(t, savedIDs[1], first.Receipts[0].ID)
+	assert.Equal(t, savedIDs[2], first.Receipts[1].ID)
+
+	query.Cursor = first.NextCursor
+	second, err := store.Query(ctx, query)
+	assert.NoError(t, err)
+	assert.Len(t, second.Receipts, 1)
+	assert.Equal(t, savedIDs[0], second.Receipts[0].ID)
+	assert.Empty(t, second.NextCursor)
+}
+
+func TestQueryReceipts_DateRangeUsesIndex(t *testing.T) {
+	store := memory.NewReceiptStore()
+	ctx := context.Background()
+
+	id, err := store.Save(ctx, domain.Receipt{Retailer: "Range Mart", PurchaseDate: "1999-12-31", PurchaseTime: "10:00", Total: "1.00"})
+	assert.NoError(t, err)
+
+	page, err := store.Query(ctx, repository.ReceiptQuery{PurchaseDateFrom: "1999-12-31", PurchaseDateTo: "1999-12-31"})
+	assert.NoError(t, err)
+	assert.Len(t, page.Receipts, 1)
+	assert.Equal(t, id, page.Receipts[0].ID)
+}
+
+func TestDeleteReceipt_LeavesTombstone(t *testing.T) {
+	store := memory.NewReceiptStore()
+	ctx := context.Background()
+
+	id, err := store.Save(ctx, domain.Receipt{Retailer: "Delete Mart", PurchaseDate: "2024-04-01", PurchaseTime: "10:00", Total: "1.00"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.Delete(ctx, id, domain.Tombstone{DeletedBy: "alice", Reason: "customer request"}))
+
+	_, err = store.Find(ctx, id)
+	assert.ErrorIs(t, err, repository.ErrReceiptDeleted)
+	assert.ErrorIs(t, store.Delete(ctx, id, domain.Tombstone{}), repository.ErrReceiptDeleted)
+	assert.ErrorIs(t, store.Delete(ctx, "nonexistent-id", domain.Tombstone{}), repository.ErrReceiptNotFound)
+
+	page, err := store.Query(ctx, repository.ReceiptQuery{Retailer: "Delete Mart"})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Receipts)
+}
+
+func TestEraseReceipts_ByCustomer(t *testing.T) {
+	store := memory.NewReceiptStore()
+	ctx := context.Background()
+
+	var customerIDs []string
+	for _, customerID := range []string{"erase-customer", "erase-customer", "other-customer"} {
+		id, err := store.Save(ctx, domain.Receipt{Retailer: "Erase Mart", PurchaseDate: "2024-04-02", PurchaseTime: "10:00", Total: "1.00", CustomerID: customerID})
+		assert.NoError(t, err)
+		if customerID == "erase-customer" {
+			customerIDs = append(customerIDs, id)
+		}
+	}
+
+	erased, err := store.Erase(ctx, repository.ErasureSelector{CustomerID: "erase-customer"}, domain.Tombstone{DeletedBy: "dpo", Reason: "gdpr"})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, customerIDs, erased)
+
+	for _, id := range customerIDs {
+		_, err := store.Find(ctx, id)
+		assert.ErrorIs(t, err, repository.ErrReceiptDeleted)
+	}
+
+	page, err := store.Query(ctx, repository.ReceiptQuery{Retailer: "Erase Mart"})
+	assert.NoError(t, err)
+	assert.Len(t, page.Receipts, 1)
+	assert.Equal(t, "other-customer", page.Receipts[0].CustomerID)
+}
+
+func TestUpdateReceipt_KeepsRevisionsAndReindexes(t *testing.T) {
+	store := memory.NewReceiptStore()
+	ctx := context.Background()
+
+	original := domain.Receipt{Retailer: "Revise Mart", PurchaseDate: "2024-05-01", PurchaseTime: "10:00", Total: "1.00", Points: 10, Revision: 1}
+	id, err := store.Save(ctx, original)
+	assert.NoError(t, err)
+
+	corrected := original
+	corrected.ID = id
+	corrected.Retailer = "Revised Mart"
+	corrected.Total = "2.00"
+	corrected.Revision = 2
+	assert.NoError(t, store.Update(ctx, corrected, 1))
+	assert.ErrorIs(t, store.Update(ctx, corrected, 1), repository.ErrRevisionConflict)
+
+	found, err := store.Find(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "2.00", found.Total)
+
+	revisions, err := store.Revisions(ctx, id)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 1)
+	assert.Equal(t, "1.00", revisions[0].Total)
+
+	old, err := store.Query(ctx, repository.ReceiptQuery{Retailer: "Revise Mart"})
+	assert.NoError(t, err)
+	assert.Empty(t, old.Receipts)
+	renamed, err := store.Query(ctx, repository.ReceiptQuery{Retailer: "Revised Mart"})
+	assert.NoError(t, err)
+	assert.Len(t, renamed.Receipts, 1)
+}
+
+func TestFindByFingerprint_TracksSavesUpdatesAndDeletes(t *testing.T) {
+	store := memory.NewReceiptStore()
+	ctx := context.Background()
+
+	receipt := domain.Receipt{Retailer: "Fingerprint Mart", PurchaseDate: "2024-06-01", PurchaseTime: "10:00", Total: "1.00", Revision: 1}
+	receipt.Fingerprint = receipt.CanonicalFingerprint()
+	first, err := store.Save(ctx, receipt)
+	assert.NoError(t, err)
+	second, err := store.Save(ctx, receipt)
+	assert.NoError(t, err)
+
+	matches, err := store.FindByFingerprint(ctx, receipt.Fingerprint)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{first, second}, []string{matches[0].ID, matches[1].ID})
+
+	corrected := receipt
+	corrected.ID = second
+	corrected.Total = "2.00"
+	corrected.Fingerprint = corrected.CanonicalFingerprint()
+	corrected.Revision = 2
+	assert.NoError(t, store.Update(ctx, corrected, 1))
+	assert.NoError(t, store.Delete(ctx, first, domain.Tombstone{}))
+
+	matches, err = store.FindByFingerprint(ctx, receipt.Fingerprint)
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+	matches, err = store.FindByFingerprint(ctx, corrected.Fingerprint)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 1)
+	assert.Equal(t, second, matches[0].ID)
+}
+
+func TestSaveUnique_StoresOneOfConcurrentDuplicates(t *testing.T) {
+	store := memory.NewReceiptStore()
+	ctx := context.Background()
+
+	// The store is shared by every test in the package, so the receipt is made unique to this run
+	receipt := domain.Receipt{Retailer: "Unique Mart " + uuid.NewString(), PurchaseDate: "2024-06-01", PurchaseTime: "10:00", Total: "1.00"}
+	receipt.Fingerprint = receipt.CanonicalFingerprint()
+
+	const submissions = 8
+	receiptIDs, originalIDs := make([]string, submissions), make([]string, submissions)
+	var wg sync.WaitGroup
+	for i := 0; i < submissions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			receiptIDs[i], originalIDs[i], err = store.SaveUnique(ctx, receipt)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	matches, err := store.FindByFingerprint(ctx, receipt.Fingerprint)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	original := matches[0].ID
+	saved := 0
+	for i := range receiptIDs {
+		if receiptIDs[i] != "" {
+			saved++
+			assert.Equal(t, original, receiptIDs[i])
+			assert.Empty(t, originalIDs[i])
+		} else {
+			assert.Equal(t, original, originalIDs[i])
+		}
+	}
+	assert.Equal(t, 1, saved)
+
+	// Saving the stored receipt again under its own ID, as a retry does, is not a duplicate of itself
+	retried := receipt
+	retried.ID = original
+	receiptID, originalID, err := store.SaveUnique(ctx, retried)
+	require.NoError(t, err)
+	assert.Equal(t, original, receiptID)
+	assert.Empty(t, originalID)
+}
@@ -0,0 +1,62 @@
+package memory_test
+
+import (
+	"context"
+	"go-receipt-processor/internal/adapters/memory"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookStore_Subscriptions(t *testing.T) {
+	store := memory.NewWebhookStore()
+	ctx := context.Background()
+	createdAt := time.Date(2024, 11, 29, 15, 0, 0, 0, time.UTC)
+
+	later, err := store.CreateSubscription(ctx, domain.WebhookSubscription{URL: "https://b.example", Events: []string{domain.EventReceiptScored}, CreatedAt: createdAt.Add(time.Minute)})
+	require.NoError(t, err)
+	earlier, err := store.CreateSubscription(ctx, domain.WebhookSubscription{URL: "https://a.example", Events: []string{domain.EventReceiptDeleted}, CreatedAt: createdAt})
+	require.NoError(t, err)
+	assert.NotEmpty(t, later.ID)
+	assert.NotEqual(t, later.ID, earlier.ID)
+
+	subscriptions, err := store.Subscriptions(ctx)
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 2)
+	assert.Equal(t, earlier.ID, subscriptions[0].ID)
+	assert.Equal(t, later.ID, subscriptions[1].ID)
+
+	found, err := store.Subscription(ctx, later.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://b.example", found.URL)
+
+	require.NoError(t, store.DeleteSubscription(ctx, later.ID))
+	_, err = store.Subscription(ctx, later.ID)
+	assert.ErrorIs(t, err, repository.ErrSubscriptionNotFound)
+	assert.ErrorIs(t, store.DeleteSubscription(ctx, later.ID), repository.ErrSubscriptionNotFound)
+}
+
+func TestWebhookStore_DeliveriesAreBoundedAndNewestFirst(t *testing.T) {
+	store := memory.NewWebhookStore()
+	ctx := context.Background()
+	subscription, err := store.CreateSubscription(ctx, domain.WebhookSubscription{URL: "https://a.example"})
+	require.NoError(t, err)
+
+	for attempt := 1; attempt <= 150; attempt++ {
+		require.NoError(t, store.RecordDelivery(ctx, domain.WebhookDelivery{SubscriptionID: subscription.ID, Attempt: attempt}))
+	}
+
+	deliveries, err := store.Deliveries(ctx, subscription.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 100)
+	assert.Equal(t, 150, deliveries[0].Attempt)
+	assert.Equal(t, 51, deliveries[99].Attempt)
+
+	_, err = store.Deliveries(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrSubscriptionNotFound)
+	assert.ErrorIs(t, store.RecordDelivery(ctx, domain.WebhookDelivery{SubscriptionID: "missing"}), repository.ErrSubscriptionNotFound)
+}
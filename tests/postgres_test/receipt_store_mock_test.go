@@ -0,0 +1,194 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"go-receipt-processor/internal/adapters/postgres"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockStore returns a store on a mock pool, so the store's own logic runs without a Postgres server.
+// Every expectation set on the pool must have been met by the end of the test.
+func newMockStore(t *testing.T) (*postgres.ReceiptStoreImpl, pgxmock.PgxPoolIface) {
+	pool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, pool.ExpectationsWereMet()) })
+	return postgres.NewReceiptStore(pool, time.Second), pool
+}
+
+var mockReceipt = domain.Receipt{
+	ID:           "receipt-1",
+	Retailer:     "M&M Corner Market",
+	PurchaseDate: "2022-03-20",
+	PurchaseTime: "14:33",
+	Items:        []domain.Item{{ShortDescription: "Gatorade", Price: "2.25"}},
+	Total:        "2.25",
+	Fingerprint:  "f1",
+}
+
+// receiptRow returns the columns of receipt in the order the store selects them
+func receiptRow(receipt domain.Receipt) []any {
+	return []any{receipt.ID, receipt.Retailer, receipt.PurchaseDate, receipt.PurchaseTime, receipt.Total, receipt.Points,
+		receipt.CustomerID, (*time.Time)(nil), receipt.ScoringVersion, 1, 0, (*time.Time)(nil), receipt.Fingerprint, ""}
+}
+
+// anyArgs matches n arguments of any value
+func anyArgs(n int) []any {
+	args := make([]any, n)
+	for i := range args {
+		args[i] = pgxmock.AnyArg()
+	}
+	return args
+}
+
+// expectDeferredRollback expects the rollback pgx.BeginFunc always makes on its way out,
+// which a real transaction answers with ErrTxClosed once it has been committed or rolled back
+func expectDeferredRollback(pool pgxmock.PgxPoolIface) {
+	pool.ExpectRollback().WillReturnError(pgx.ErrTxClosed)
+}
+
+var receiptRowColumns = []string{"id", "retailer", "purchase_date", "purchase_time", "total", "points", "customer_id",
+	"received_at", "scoring_version", "revision", "points_delta", "revised_at", "fingerprint", "duplicate_of"}
+
+func TestMockSave_InsertsReceiptAndItemsInOneTransaction(t *testing.T) {
+	store, pool := newMockStore(t)
+
+	pool.ExpectBegin()
+	pool.ExpectExec("INSERT INTO receipts").WithArgs(anyArgs(14)...).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	batch := pool.ExpectBatch()
+	batch.ExpectExec("INSERT INTO receipt_items").WithArgs("receipt-1", 0, "Gatorade", "2.25").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	pool.ExpectCommit()
+	expectDeferredRollback(pool)
+
+	receiptID, err := store.Save(context.Background(), mockReceipt)
+	require.NoError(t, err)
+	assert.Equal(t, "receipt-1", receiptID)
+}
+
+func TestMockSave_RetryAfterCommitSucceeds(t *testing.T) {
+	store, pool := newMockStore(t)
+
+	// The first attempt committed but its reply was lost, so the receipt is already there and nothing is inserted
+	pool.ExpectBegin()
+	pool.ExpectExec("INSERT INTO receipts .* ON CONFLICT \\(id\\) DO NOTHING").WithArgs(anyArgs(14)...).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	pool.ExpectCommit()
+	expectDeferredRollback(pool)
+
+	receiptID, err := store.Save(context.Background(), mockReceipt)
+	require.NoError(t, err)
+	assert.Equal(t, "receipt-1", receiptID)
+}
+
+func TestMockSave_MarksRetryableFailuresTransient(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantTransient bool
+	}{
+		{"lost connection", &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}, true},
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"server shutting down", &pgconn.PgError{Code: "57P01"}, true},
+		{"check violation", &pgconn.PgError{Code: "23514"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, pool := newMockStore(t)
+			pool.ExpectBegin()
+			pool.ExpectExec("INSERT INTO receipts").WithArgs(anyArgs(14)...).WillReturnError(tt.err)
+			pool.ExpectRollback()
+			expectDeferredRollback(pool)
+
+			_, err := store.Save(context.Background(), mockReceipt)
+
+			var transient *repository.TransientError
+			assert.Equal(t, tt.wantTransient, errors.As(err, &transient), "%v", err)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestMockSaveUnique_ReportsOriginalWithoutInserting(t *testing.T) {
+	store, pool := newMockStore(t)
+
+	pool.ExpectBegin()
+	pool.ExpectExec("pg_advisory_xact_lock").WithArgs("f1").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	pool.ExpectQuery("SELECT id FROM receipts").WithArgs("receipt-1", "f1").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("original"))
+	pool.ExpectCommit()
+	expectDeferredRollback(pool)
+
+	receiptID, originalID, err := store.SaveUnique(context.Background(), mockReceipt)
+	require.NoError(t, err)
+	assert.Empty(t, receiptID)
+	assert.Equal(t, "original", originalID)
+}
+
+func TestMockSaveUnique_InsertsFirstReceiptAndAcceptsItsRetry(t *testing.T) {
+	store, pool := newMockStore(t)
+
+	pool.ExpectBegin()
+	pool.ExpectExec("pg_advisory_xact_lock").WithArgs("f1").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	pool.ExpectQuery("SELECT id FROM receipts").WithArgs("receipt-1", "f1").WillReturnRows(pgxmock.NewRows([]string{"id"}))
+	pool.ExpectExec("INSERT INTO receipts").WithArgs(anyArgs(14)...).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	batch := pool.ExpectBatch()
+	batch.ExpectExec("INSERT INTO receipt_items").WithArgs("receipt-1", 0, "Gatorade", "2.25").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	pool.ExpectCommit()
+	expectDeferredRollback(pool)
+
+	receiptID, originalID, err := store.SaveUnique(context.Background(), mockReceipt)
+	require.NoError(t, err)
+	assert.Equal(t, "receipt-1", receiptID)
+	assert.Empty(t, originalID)
+
+	// The retry finds the receipt under its own ID and saves nothing more
+	pool.ExpectBegin()
+	pool.ExpectExec("pg_advisory_xact_lock").WithArgs("f1").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	pool.ExpectQuery("SELECT id FROM receipts").WithArgs("receipt-1", "f1").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("receipt-1"))
+	pool.ExpectCommit()
+	expectDeferredRollback(pool)
+
+	receiptID, originalID, err = store.SaveUnique(context.Background(), mockReceipt)
+	require.NoError(t, err)
+	assert.Equal(t, "receipt-1", receiptID)
+	assert.Empty(t, originalID)
+}
+
+func TestMockFind_ReportsDeletedReceipts(t *testing.T) {
+	store, pool := newMockStore(t)
+
+	pool.ExpectQuery("FROM receipts WHERE id = \\$1").WithArgs("receipt-1").WillReturnError(pgx.ErrNoRows)
+	pool.ExpectQuery("FROM receipt_tombstones").WithArgs("receipt-1").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+
+	_, err := store.Find(context.Background(), "receipt-1")
+	assert.ErrorIs(t, err, repository.ErrReceiptDeleted)
+}
+
+func TestMockFindByFingerprint_LoadsItems(t *testing.T) {
+	store, pool := newMockStore(t)
+
+	pool.ExpectQuery("FROM receipts WHERE fingerprint = \\$1").WithArgs("f1").
+		WillReturnRows(pgxmock.NewRows(receiptRowColumns).AddRow(receiptRow(mockReceipt)...))
+	pool.ExpectQuery("FROM receipt_items").WithArgs([]string{"receipt-1"}).
+		WillReturnRows(pgxmock.NewRows([]string{"receipt_id", "short_description", "price"}).AddRow("receipt-1", "Gatorade", "2.25"))
+
+	receipts, err := store.FindByFingerprint(context.Background(), "f1")
+	require.NoError(t, err)
+	require.Len(t, receipts, 1)
+	assert.Equal(t, "receipt-1", receipts[0].ID)
+	assert.Equal(t, mockReceipt.Items, receipts[0].Items)
+}
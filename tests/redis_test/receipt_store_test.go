@@ -0,0 +1,384 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"go-receipt-processor/internal/adapters/redis"
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStore starts an in-process Redis stand-in and returns a store connected to it
+func newStore(t *testing.T, opts redis.Options) (*redis.ReceiptStoreImpl, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+
+	store, err := redis.NewReceiptStore(client, opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	return store, server
+}
+
+func sampleReceipt(retailer, purchaseDate string) domain.Receipt {
+	return domain.Receipt{
+		Retailer:     retailer,
+		PurchaseDate: purchaseDate,
+		PurchaseTime: "14:30",
+		Items: []domain.Item{
+			{ShortDescription: "Item 1", Price: "50.00"},
+			{ShortDescription: "Item 2", Price: "50.00"},
+		},
+		Total:          "100.00",
+		Points:         100,
+		ReceivedAt:     time.Date(2024, 11, 29, 14, 31, 0, 0, time.UTC),
+		ScoringVersion: "v1",
+	}
+}
+
+func TestSaveAndFind_Encodings(t *testing.T) {
+	for _, encoding := range []redis.Encoding{redis.EncodingJSON, redis.EncodingMsgpack} {
+		t.Run(string(encoding), func(t *testing.T) {
+			store, _ := newStore(t, redis.Options{KeyPrefix: "receipts:", Encoding: encoding})
+			receipt := sampleReceipt("Store A", "2024-11-29")
+
+			receiptID, err := store.Save(context.Background(), receipt)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, receiptID)
+
+			savedReceipt, err := store.Find(context.Background(), receiptID)
+			assert.NoError(t, err)
+			assert.Equal(t, receiptID, savedReceipt.ID)
+			assert.Equal(t, receipt.Retailer, savedReceipt.Retailer)
+			assert.Equal(t, receipt.Items, savedReceipt.Items)
+			assert.Equal(t, receipt.Points, savedReceipt.Points)
+			assert.True(t, receipt.ReceivedAt.Equal(savedReceipt.ReceivedAt))
+			assert.Equal(t, receipt.ScoringVersion, savedReceipt.ScoringVersion)
+		})
+	}
+}
+
+func TestNewReceiptStore_UnsupportedEncoding(t *testing.T) {
+	client := goredis.NewClient(&goredis.Options{Addr: "localhost:0"})
+	defer client.Close()
+
+	_, err := redis.NewReceiptStore(client, redis.Options{Encoding: "xml"})
+
+	assert.Error(t, err)
+}
+
+func TestFindReceipt_NotFound(t *testing.T) {
+	store, _ := newStore(t, redis.Options{})
+
+	_, err := store.Find(context.Background(), "nonexistent-id")
+
+	assert.ErrorIs(t, err, repository.ErrReceiptNotFound)
+}
+
+func TestSave_UsesKeyPrefix(t *testing.T) {
+	store, server := newStore(t, redis.Options{KeyPrefix: "tenant-a:"})
+
+	receiptID, err := store.Save(context.Background(), sampleReceipt("Store A", "2024-11-29"))
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		"tenant-a:receipt:" + receiptID,
+		"tenant-a:idx:retailer:store a",
+		"tenant-a:idx:date:2024-11-29",
+		"tenant-a:idx:by-date",
+	}, server.Keys())
+}
+
+func TestSave_AppliesTTL(t *testing.T) {
+	store, server := newStore(t, redis.Options{KeyPrefix: "receipts:", TTL: time.Hour})
+
+	receiptID, err := store.Save(context.Background(), sampleReceipt("Store A", "2024-11-29"))
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, server.TTL("receipts:receipt:"+receiptID))
+	assert.Equal(t, time.Hour, server.TTL("receipts:idx:retailer:store a"))
+
+	// Once the TTL elapses the receipt is gone
+	server.FastForward(time.Hour + time.Second)
+	_, err = store.Find(context.Background(), receiptID)
+	assert.ErrorIs(t, err, repository.ErrReceiptNotFound)
+}
+
+func TestFindByRetailerAndDate(t *testing.T) {
+	store, _ := newStore(t, redis.Options{KeyPrefix: "receipts:"})
+
+	idA, err := store.Save(context.Background(), sampleReceipt("Target", "2024-11-29"))
+	require.NoError(t, err)
+	idB, err := store.Save(context.Background(), sampleReceipt(" TARGET", "2024-11-30"))
+	require.NoError(t, err)
+	_, err = store.Save(context.Background(), sampleReceipt("Walgreens", "2024-11-30"))
+	require.NoError(t, err)
+
+	byRetailer, err := store.FindByRetailer(context.Background(), "target")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{idA, idB}, receiptIDs(byRetailer))
+
+	byDate, err := store.FindByPurchaseDate(context.Background(), "2024-11-29")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{idA}, receiptIDs(byDate))
+}
+
+func TestFindByRetailer_SkipsExpiredReceipts(t *testing.T) {
+	store, server := newStore(t, redis.Options{KeyPrefix: "receipts:"})
+
+	receiptID, err := store.Save(context.Background(), sampleReceipt("Target", "2024-11-29"))
+	require.NoError(t, err)
+	server.Del("receipts:receipt:" + receiptID)
+
+	receipts, err := store.FindByRetailer(context.Background(), "Target")
+	assert.NoError(t, err)
+	assert.Empty(t, receipts)
+}
+
+func TestQuery_PrunesExpiredReceiptsFromDateIndex(t *testing.T) {
+	store, server := newStore(t, redis.Options{KeyPrefix: "receipts:", TTL: time.Hour})
+	ctx := context.Background()
+
+	expiredID, err := store.Save(ctx, sampleReceipt("Target", "2024-11-29"))
+	require.NoError(t, err)
+	server.FastForward(30 * time.Minute)
+	keptID, err := store.Save(ctx, sampleReceipt("Walgreens", "2024-11-30"))
+	require.NoError(t, err)
+	server.FastForward(31 * time.Minute)
+
+	page, err := store.Query(ctx, repository.ReceiptQuery{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{keptID}, receiptIDs(page.Receipts))
+
+	members, err := server.ZMembers("receipts:idx:by-date")
+	require.NoError(t, err)
+	assert.Equal(t, []string{keptID}, members)
+	assert.NotContains(t, members, expiredID)
+}
+
+func TestErase_PrunesExpiredReceiptsFromDateIndex(t *testing.T) {
+	store, server := newStore(t, redis.Options{KeyPrefix: "receipts:"})
+	ctx := context.Background()
+
+	expiredID, err := store.Save(ctx, sampleReceipt("Target", "2024-11-29"))
+	require.NoError(t, err)
+	keptID, err := store.Save(ctx, sampleReceipt("Target", "2024-11-30"))
+	require.NoError(t, err)
+	server.Del("receipts:receipt:" + expiredID)
+
+	erased, err := store.Erase(ctx, repository.ErasureSelector{CustomerID: "customer-1"}, domain.Tombstone{DeletedBy: "dpo"})
+	require.NoError(t, err)
+	assert.Empty(t, erased)
+
+	members, err := server.ZMembers("receipts:idx:by-date")
+	require.NoError(t, err)
+	assert.Equal(t, []string{keptID}, members)
+}
+
+func receiptIDs(receipts []domain.Receipt) []string {
+	ids := make([]string, len(receipts))
+	for i, receipt := range receipts {
+		ids[i] = receipt.ID
+	}
+	return ids
+}
+
+func TestQuery_UsesIndexesAndPages(t *testing.T) {
+	store, _ := newStore(t, redis.Options{KeyPrefix: "receipts:"})
+	ctx := context.Background()
+
+	idA, err := store.Save(ctx, sampleReceipt("Target", "2024-11-29"))
+	require.NoError(t, err)
+	idB, err := store.Save(ctx, sampleReceipt("Walgreens", "2024-11-30"))
+	require.NoError(t, err)
+	idC, err := store.Save(ctx, sampleReceipt("target", "2024-12-01"))
+	require.NoError(t, err)
+
+	// Retailer index set
+	page, err := store.Query(ctx, repository.ReceiptQuery{Retailer: "TARGET"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{idA, idC}, receiptIDs(page.Receipts))
+
+	// Date sorted set range
+	page, err = store.Query(ctx, repository.ReceiptQuery{PurchaseDateFrom: "2024-11-30", PurchaseDateTo: "2024-12-01"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{idB, idC}, receiptIDs(page.Receipts))
+
+	// Everything, sorted descending across two pages
+	query := repository.ReceiptQuery{SortDescending: true, Limit: 2}
+	page, err = store.Query(ctx, query)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{idC, idB}, receiptIDs(page.Receipts))
+
+	query.Cursor = page.NextCursor
+	page, err = store.Query(ctx, query)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{idA}, receiptIDs(page.Receipts))
+}
+
+func TestDelete_RemovesIndexesAndLeavesTombstone(t *testing.T) {
+	store, server := newStore(t, redis.Options{KeyPrefix: "receipts:", TTL: time.Hour})
+	ctx := context.Background()
+
+	id, err := store.Save(ctx, sampleReceipt("Target", "2024-11-29"))
+	require.NoError(t, err)
+
+	require.NoError(t, store.Delete(ctx, id, domain.Tombstone{DeletedBy: "alice", Reason: "duplicate"}))
+
+	_, err = store.Find(ctx, id)
+	assert.ErrorIs(t, err, repository.ErrReceiptDeleted)
+	assert.ErrorIs(t, store.Delete(ctx, id, domain.Tombstone{}), repository.ErrReceiptDeleted)
+	assert.ErrorIs(t, store.Delete(ctx, "nonexistent-id", domain.Tombstone{}), repository.ErrReceiptNotFound)
+
+	// The tombstone outlives the receipt TTL so the audit trail is kept
+	assert.Equal(t, time.Duration(0), server.TTL("receipts:tombstone:"+id))
+	assert.ElementsMatch(t, []string{"receipts:tombstone:" + id}, server.Keys())
+}
+
+func TestErase_ByCustomer(t *testing.T) {
+	store, _ := newStore(t, redis.Options{KeyPrefix: "receipts:"})
+	ctx := context.Background()
+
+	receipt := sampleReceipt("Target", "2024-11-29")
+	receipt.CustomerID = "customer-1"
+	erasedID, err := store.Save(ctx, receipt)
+	require.NoError(t, err)
+	keptID, err := store.Save(ctx, sampleReceipt("Target", "2024-11-30"))
+	require.NoError(t, err)
+
+	erased, err := store.Erase(ctx, repository.ErasureSelector{CustomerID: "customer-1"}, domain.Tombstone{DeletedBy: "dpo", Reason: "gdpr"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{erasedID}, erased)
+
+	_, err = store.Find(ctx, erasedID)
+	assert.ErrorIs(t, err, repository.ErrReceiptDeleted)
+
+	remaining, err := store.FindByRetailer(ctx, "Target")
+	require.NoError(t, err)
+	assert.Equal(t, []string{keptID}, receiptIDs(remaining))
+}
+
+func TestUpdate_KeepsRevisionsAndReindexes(t *testing.T) {
+	store, server := newStore(t, redis.Options{KeyPrefix: "receipts:"})
+	ctx := context.Background()
+
+	original := sampleReceipt("Target", "2024-11-29")
+	original.Revision = 1
+	id, err := store.Save(ctx, original)
+	require.NoError(t, err)
+
+	corrected := original
+	corrected.ID = id
+	corrected.Retailer = "Walmart"
+	corrected.Revision = 2
+	require.NoError(t, store.Update(ctx, corrected, 1))
+	assert.ErrorIs(t, store.Update(ctx, corrected, 1), repository.ErrRevisionConflict)
+
+	byNewRetailer, err := store.FindByRetailer(ctx, "Walmart")
+	require.NoError(t, err)
+	assert.Equal(t, []string{id}, receiptIDs(byNewRetailer))
+	assert.False(t, server.Exists("receipts:idx:retailer:target"))
+
+	revisions, err := store.Revisions(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, "Target", revisions[0].Retailer)
+
+	require.NoError(t, store.Delete(ctx, id, domain.Tombstone{DeletedBy: "alice", Reason: "test"}))
+	assert.False(t, server.Exists("receipts:revisions:"+id))
+}
+
+func TestFindByFingerprint_UsesIndex(t *testing.T) {
+	store, _ := newStore(t, redis.Options{KeyPrefix: "receipts:"})
+	ctx := context.Background()
+
+	receipt := sampleReceipt("Target", "2024-11-29")
+	receipt.Fingerprint = receipt.CanonicalFingerprint()
+	first, err := store.Save(ctx, receipt)
+	require.NoError(t, err)
+	second, err := store.Save(ctx, receipt)
+	require.NoError(t, err)
+	_, err = store.Save(ctx, sampleReceipt("Walmart", "2024-11-29"))
+	require.NoError(t, err)
+
+	matches, err := store.FindByFingerprint(ctx, receipt.Fingerprint)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{first, second}, receiptIDs(matches))
+
+	require.NoError(t, store.Delete(ctx, first, domain.Tombstone{}))
+	matches, err = store.FindByFingerprint(ctx, receipt.Fingerprint)
+	require.NoError(t, err)
+	assert.Equal(t, []string{second}, receiptIDs(matches))
+}
+
+func TestSave_MarksRetryableFailuresTransient(t *testing.T) {
+	store, server := newStore(t, redis.Options{})
+	var transient *repository.TransientError
+
+	server.SetError("ERR wrong number of arguments")
+	_, err := store.Save(context.Background(), sampleReceipt("Store A", "2024-11-29"))
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &transient))
+
+	server.SetError("LOADING Redis is loading the dataset in memory")
+	_, err = store.Save(context.Background(), sampleReceipt("Store A", "2024-11-29"))
+	assert.True(t, errors.As(err, &transient), "%v", err)
+
+	server.SetError("")
+	server.Close()
+	_, err = store.Save(context.Background(), sampleReceipt("Store A", "2024-11-29"))
+	assert.True(t, errors.As(err, &transient), "%v", err)
+}
+
+func TestSaveUnique_StoresOneOfConcurrentDuplicates(t *testing.T) {
+	store, _ := newStore(t, redis.Options{KeyPrefix: "receipts:"})
+	ctx := context.Background()
+
+	receipt := sampleReceipt("Target", "2024-11-29")
+	receipt.Fingerprint = receipt.CanonicalFingerprint()
+
+	const submissions = 8
+	receiptIDs, originalIDs := make([]string, submissions), make([]string, submissions)
+	var wg sync.WaitGroup
+	for i := 0; i < submissions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			receiptIDs[i], originalIDs[i], err = store.SaveUnique(ctx, receipt)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	matches, err := store.FindByFingerprint(ctx, receipt.Fingerprint)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	original := matches[0].ID
+	saved := 0
+	for i := range receiptIDs {
+		if receiptIDs[i] != "" {
+			saved++
+			assert.Equal(t, original, receiptIDs[i])
+			assert.Empty(t, originalIDs[i])
+		} else {
+			assert.Equal(t, original, originalIDs[i])
+		}
+	}
+	assert.Equal(t, 1, saved)
+
+	// Saving the stored receipt again under its own ID, as a retry does, is not a duplicate of itself
+	retried := receipt
+	retried.ID = original
+	receiptID, originalID, err := store.SaveUnique(ctx, retried)
+	require.NoError(t, err)
+	assert.Equal(t, original, receiptID)
+	assert.Empty(t, originalID)
+}
@@ -0,0 +1,89 @@
+package repository_test
+
+import (
+	"go-receipt-processor/internal/domain"
+	"go-receipt-processor/internal/ports/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var receipts = []domain.Receipt{
+	{ID: "a", Retailer: "Target", PurchaseDate: "2024-01-05", PurchaseTime: "10:00", Total: "9.00", Points: 30},
+	{ID: "b", Retailer: " target ", PurchaseDate: "2024-01-01", PurchaseTime: "12:00", Total: "35.35", Points: 28},
+	{ID: "c", Retailer: "Walgreens", PurchaseDate: "2024-01-03", PurchaseTime: "09:00", Total: "100.00", Points: 109},
+	{ID: "d", Retailer: "Walgreens", PurchaseDate: "2024-01-03", PurchaseTime: "08:00", Total: "2.65", Points: 15},
+}
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+func ids(page repository.ReceiptPage) []string {
+	result := make([]string, len(page.Receipts))
+	for i, receipt := range page.Receipts {
+		result[i] = receipt.ID
+	}
+	return result
+}
+
+func TestPaginate_DefaultSortIsPurchaseDateAndTime(t *testing.T) {
+	page, err := repository.ReceiptQuery{}.Paginate(receipts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "d", "c", "a"}, ids(page))
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestPaginate_Filters(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    repository.ReceiptQuery
+		expected []string
+	}{
+		{"retailer ignores case and whitespace", repository.ReceiptQuery{Retailer: "TARGET"}, []string{"b", "a"}},
+		{"purchase date range is inclusive", repository.ReceiptQuery{PurchaseDateFrom: "2024-01-03", PurchaseDateTo: "2024-01-05"}, []string{"d", "c", "a"}},
+		{"points range", repository.ReceiptQuery{MinPoints: intPtr(28), MaxPoints: intPtr(30)}, []string{"b", "a"}},
+		{"total range compares numerically", repository.ReceiptQuery{MinTotal: floatPtr(9), MaxTotal: floatPtr(40)}, []string{"b", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := tt.query.Paginate(receipts)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ids(page))
+		})
+	}
+}
+
+func TestPaginate_SortsNumericFieldsNumerically(t *testing.T) {
+	page, err := repository.ReceiptQuery{SortBy: repository.SortByTotal, SortDescending: true}.Paginate(receipts)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "b", "a", "d"}, ids(page))
+}
+
+func TestPaginate_CursorWalksEveryPageOnce(t *testing.T) {
+	query := repository.ReceiptQuery{SortBy: repository.SortByRetailer, Limit: 3}
+
+	first, err := query.Paginate(receipts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, ids(first))
+	require.NotEmpty(t, first.NextCursor)
+
+	query.Cursor = first.NextCursor
+	second, err := query.Paginate(receipts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d"}, ids(second))
+	assert.Empty(t, second.NextCursor)
+}
+
+func TestPaginate_RejectsCursorForDifferentSort(t *testing.T) {
+	first, err := repository.ReceiptQuery{SortBy: repository.SortByPoints, Limit: 1}.Paginate(receipts)
+	require.NoError(t, err)
+
+	_, err = repository.ReceiptQuery{SortBy: repository.SortByTotal, Cursor: first.NextCursor}.Paginate(receipts)
+	assert.ErrorIs(t, err, repository.ErrInvalidCursor)
+
+	_, err = repository.ReceiptQuery{Cursor: "not-a-cursor!"}.Paginate(receipts)
+	assert.ErrorIs(t, err, repository.ErrInvalidCursor)
+}